@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// metrics and operators usable in AlertRule
+const (
+	AlertMetricRTT  = "rtt"
+	AlertMetricLoss = "loss"
+
+	AlertOperatorGreater = "gt"
+	AlertOperatorLess    = "lt"
+)
+
+// AlertRule describes threshold-based notification
+type AlertRule struct {
+	ID        string   `json:"id,omitempty"` // empty for new rule, assigned by server
+	Target    string   `json:"target"`       // IP or group (with trailing "->")
+	Slave     string   `json:"slave"`        // empty means any slave
+	Metric    string   `json:"metric"`       // AlertMetricRTT or AlertMetricLoss
+	Operator  string   `json:"operator"`     // AlertOperatorGreater or AlertOperatorLess
+	Threshold float64  `json:"threshold"`    // ms for rtt, percent for loss
+	Channels  []string `json:"channels"`     // notification channels to use
+}
+
+// validate checks that rule fields have supported values
+func (r AlertRule) validate() error {
+	if "" == r.Target {
+		return errors.New("alert rule without target")
+	}
+	if AlertMetricRTT != r.Metric && AlertMetricLoss != r.Metric {
+		return errors.New("unsupported alert metric " + r.Metric)
+	}
+	if AlertOperatorGreater != r.Operator && AlertOperatorLess != r.Operator {
+		return errors.New("unsupported alert operator " + r.Operator)
+	}
+	return nil
+}
+
+// GetAlertRules returns all configured alert rules
+func (c *Client) GetAlertRules() ([]AlertRule, error) {
+	return c.GetAlertRulesContext(context.Background())
+}
+
+// GetAlertRulesContext is same as GetAlertRules but request is bound to ctx
+func (c *Client) GetAlertRulesContext(ctx context.Context) ([]AlertRule, error) {
+	var result []AlertRule
+	err := c.GetContext(ctx, c.url+"/v1/alerts", &result)
+	return result, err
+}
+
+// SetAlertRule creates new alert rule (empty ID) or replaces existing one
+func (c *Client) SetAlertRule(rule AlertRule) error {
+	return c.SetAlertRuleContext(context.Background(), rule)
+}
+
+// SetAlertRuleContext is same as SetAlertRule but request is bound to ctx
+func (c *Client) SetAlertRuleContext(ctx context.Context, rule AlertRule) error {
+	if err := rule.validate(); nil != err {
+		return err
+	}
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/alerts", rule)
+}
+
+// DeleteAlertRule removes alert rule
+func (c *Client) DeleteAlertRule(id string) error {
+	return c.DeleteAlertRuleContext(context.Background(), id)
+}
+
+// DeleteAlertRuleContext is same as DeleteAlertRule but request is bound to ctx
+func (c *Client) DeleteAlertRuleContext(ctx context.Context, id string) error {
+	return c._okResultSend(ctx, "DELETE", c.url+"/v1/alerts/"+url.PathEscape(id), nil)
+}
+
+// TestAlertRule asks server to evaluate alert rule against current data
+func (c *Client) TestAlertRule(id string) error {
+	return c.TestAlertRuleContext(context.Background(), id)
+}
+
+// TestAlertRuleContext is same as TestAlertRule but request is bound to ctx
+func (c *Client) TestAlertRuleContext(ctx context.Context, id string) error {
+	return c._okResultSend(ctx, "POST", c.url+"/v1/alerts/"+url.PathEscape(id)+"/test", nil)
+}
@@ -1,33 +1,59 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net"
+	"net/http"
 	"net/url"
 	"strings"
 )
 
-var (
-	mainAPIURL string
-)
+// normalizeIP returns canonical form of ip (IPv4-mapped IPv6 addresses are converted to plain IPv4),
+// hostnames and other non-IP values are returned unchanged
+func normalizeIP(ip string) string {
+	parsed := net.ParseIP(strings.Trim(ip, "[]"))
+	if nil == parsed {
+		return ip
+	}
+	return parsed.String()
+}
 
-// Init sets API url and authorization parameters
-func Init(url string, username string, password string) {
-	mainAPIURL = url
-	SetBasicAuth(username, password)
+// normalizeIPs returns copy of ips with every entry passed through normalizeIP
+func normalizeIPs(ips []string) []string {
+	result := make([]string, len(ips))
+	for i, ip := range ips {
+		result[i] = normalizeIP(ip)
+	}
+	return result
+}
+
+// ipPath returns ip escaped for use as URL path component, colons of IPv6 addresses are percent-encoded
+func ipPath(ip string) string {
+	return strings.Replace(url.PathEscape(normalizeIP(ip)), ":", "%3A", -1)
 }
 
 // GetConfigInfo returns current configuration
-func GetConfigInfo() (ConfigInfo, error) {
+func (c *Client) GetConfigInfo() (ConfigInfo, error) {
+	return c.GetConfigInfoContext(context.Background())
+}
+
+// GetConfigInfoContext is same as GetConfigInfo but request is bound to ctx
+func (c *Client) GetConfigInfoContext(ctx context.Context) (ConfigInfo, error) {
 	var result ConfigInfo
-	err := Get(mainAPIURL+"/v1/config", &result)
+	err := c.GetContext(ctx, c.url+"/v1/config", &result)
 	return result, err
 }
 
 // GetSlaveList returns list of defined slave probes
-func GetSlaveList() ([]string, error) {
+func (c *Client) GetSlaveList() ([]string, error) {
+	return c.GetSlaveListContext(context.Background())
+}
+
+// GetSlaveListContext is same as GetSlaveList but request is bound to ctx
+func (c *Client) GetSlaveListContext(ctx context.Context) ([]string, error) {
 	var result map[string]string
-	err := Get(mainAPIURL+"/v1/slaves", &result)
+	err := c.GetContext(ctx, c.url+"/v1/slaves", &result)
 	list := make([]string, 0, len(result))
 	for slave := range result {
 		list = append(list, slave)
@@ -36,19 +62,37 @@ func GetSlaveList() ([]string, error) {
 }
 
 // GetSlavesIPs returns list of defined slave probes with their ips
-func GetSlavesIPs() (map[string]string, error) {
+func (c *Client) GetSlavesIPs() (map[string]string, error) {
+	return c.GetSlavesIPsContext(context.Background())
+}
+
+// GetSlavesIPsContext is same as GetSlavesIPs but request is bound to ctx
+func (c *Client) GetSlavesIPsContext(ctx context.Context) (map[string]string, error) {
 	var result map[string]string
-	err := Get(mainAPIURL+"/v1/slaves", &result)
+	err := c.GetContext(ctx, c.url+"/v1/slaves", &result)
 	slave2ip := make(map[string]string, len(result))
 	for slave, addr := range result {
-		slave2ip[slave] = strings.SplitN(addr, ":", 2)[0]
+		host, _, splitErr := net.SplitHostPort(addr)
+		if nil != splitErr {
+			host = strings.SplitN(addr, ":", 2)[0]
+		}
+		slave2ip[slave] = host
 	}
 	return slave2ip, err
 }
 
 // GetSlavesSources returns list of defined slave probes with IPv4 ips from which ping/traces are initiated
-func GetSlavesSources() (map[string]string, error) {
-	slaves, err := GetSlavesStatus()
+//
+// Deprecated: use GetSlavesSourcesAll which fetches both IPv4 and IPv6 sources in one request
+func (c *Client) GetSlavesSources() (map[string]string, error) {
+	return c.GetSlavesSourcesContext(context.Background())
+}
+
+// GetSlavesSourcesContext is same as GetSlavesSources but request is bound to ctx
+//
+// Deprecated: use GetSlavesSourcesAllContext which fetches both IPv4 and IPv6 sources in one request
+func (c *Client) GetSlavesSourcesContext(ctx context.Context) (map[string]string, error) {
+	slaves, err := c.GetSlavesStatusContext(ctx)
 	if nil != err {
 		return nil, err
 	}
@@ -61,8 +105,17 @@ func GetSlavesSources() (map[string]string, error) {
 }
 
 // GetSlavesSources6 returns list of defined slave probes with IPv6 ips from which ping/traces are initiated
-func GetSlavesSources6() (map[string]string, error) {
-	slaves, err := GetSlavesStatus()
+//
+// Deprecated: use GetSlavesSourcesAll which fetches both IPv4 and IPv6 sources in one request
+func (c *Client) GetSlavesSources6() (map[string]string, error) {
+	return c.GetSlavesSources6Context(context.Background())
+}
+
+// GetSlavesSources6Context is same as GetSlavesSources6 but request is bound to ctx
+//
+// Deprecated: use GetSlavesSourcesAllContext which fetches both IPv4 and IPv6 sources in one request
+func (c *Client) GetSlavesSources6Context(ctx context.Context) (map[string]string, error) {
+	slaves, err := c.GetSlavesStatusContext(ctx)
 	if nil != err {
 		return nil, err
 	}
@@ -74,24 +127,65 @@ func GetSlavesSources6() (map[string]string, error) {
 	return result, err
 }
 
+// GetSlavesSourcesAll returns IPv4 and IPv6 source ips of all defined slave probes,
+// both maps contain every slave with empty string if slave has no source of given family
+func (c *Client) GetSlavesSourcesAll() (ipv4 map[string]string, ipv6 map[string]string, err error) {
+	return c.GetSlavesSourcesAllContext(context.Background())
+}
+
+// GetSlavesSourcesAllContext is same as GetSlavesSourcesAll but request is bound to ctx
+func (c *Client) GetSlavesSourcesAllContext(ctx context.Context) (ipv4 map[string]string, ipv6 map[string]string, err error) {
+	slaves, err := c.GetSlavesStatusContext(ctx)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	ipv4 = make(map[string]string, len(slaves))
+	ipv6 = make(map[string]string, len(slaves))
+	for slave, status := range slaves {
+		ipv4[slave] = status.Source
+		ipv6[slave] = status.Source6
+	}
+	return ipv4, ipv6, nil
+}
+
 // GetSlavesStatus returns actual slaves status
-func GetSlavesStatus() (map[string]SlaveStatus, error) {
+func (c *Client) GetSlavesStatus() (map[string]SlaveStatus, error) {
+	return c.GetSlavesStatusContext(context.Background())
+}
+
+// GetSlavesStatusContext is same as GetSlavesStatus but request is bound to ctx
+func (c *Client) GetSlavesStatusContext(ctx context.Context) (map[string]SlaveStatus, error) {
 	var result map[string]SlaveStatus
-	err := Get(mainAPIURL+"/v1/status/slaves", &result)
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves", &result)
 	return result, err
 }
 
 // GetSlavesAddrs returns list of defined slave probes with their ip:port
-func GetSlavesAddrs() (map[string]string, error) {
+func (c *Client) GetSlavesAddrs() (map[string]string, error) {
+	return c.GetSlavesAddrsContext(context.Background())
+}
+
+// GetSlavesAddrsContext is same as GetSlavesAddrs but request is bound to ctx
+func (c *Client) GetSlavesAddrsContext(ctx context.Context) (map[string]string, error) {
 	var result map[string]string
-	err := Get(mainAPIURL+"/v1/slaves", &result)
+	err := c.GetContext(ctx, c.url+"/v1/slaves", &result)
 	return result, err
 }
 
 // AddSlave adds slave to master on ip:port with name
 // and possibly copy list of ips from just existing slave copyFrom
-func AddSlave(ip net.IP, port uint16, name string, copyFrom string) error {
-	return _okResultSend("POST", mainAPIURL+"/v1/slaves", map[string]interface{}{
+func (c *Client) AddSlave(ip net.IP, port uint16, name string, copyFrom string) error {
+	return c.AddSlaveContext(context.Background(), ip, port, name, copyFrom)
+}
+
+// AddSlaveContext is same as AddSlave but request is bound to ctx
+func (c *Client) AddSlaveContext(ctx context.Context, ip net.IP, port uint16, name string, copyFrom string) error {
+	if nil == ip {
+		return errors.New("empty slave ip")
+	}
+	// net.IP.String() returns IPv4-mapped IPv6 addresses in plain IPv4 form
+	return c._okResultSend(ctx, "POST", c.url+"/v1/slaves", map[string]interface{}{
 		"ip":   ip.String(),
 		"port": port,
 		"name": name,
@@ -100,66 +194,177 @@ func AddSlave(ip net.IP, port uint16, name string, copyFrom string) error {
 }
 
 // DeleteSlave removes slave from master
-func DeleteSlave(slave string) error {
-	return _okResultSend("DELETE", mainAPIURL+"/v1/slaves?slave="+url.QueryEscape(slave), nil)
+func (c *Client) DeleteSlave(slave string) error {
+	return c.DeleteSlaveContext(context.Background(), slave)
+}
+
+// DeleteSlaveContext is same as DeleteSlave but request is bound to ctx
+func (c *Client) DeleteSlaveContext(ctx context.Context, slave string) error {
+	return c._okResultSend(ctx, "DELETE", c.url+"/v1/slaves?slave="+url.QueryEscape(slave), nil)
+}
+
+// ProbeOption customises probing of IP added by AddIP/AddIPs, without options slave defaults are used
+type ProbeOption func(*TestDesc)
+
+// WithPacketCount sets count of ICMP packets per test
+func WithPacketCount(count int) ProbeOption {
+	return func(d *TestDesc) {
+		d.PacketCount = count
+	}
+}
+
+// WithPacketSize sets size of ICMP packet in bytes
+func WithPacketSize(size int) ProbeOption {
+	return func(d *TestDesc) {
+		d.PacketSizeBytes = size
+	}
+}
+
+// WithProbeInterval sets seconds between tests
+func WithProbeInterval(seconds int) ProbeOption {
+	return func(d *TestDesc) {
+		d.ProbeIntervalSec = seconds
+	}
 }
 
 // AddIP is simple interface for single IP adding
-func AddIP(ip string, slaves []string, description string, groups []string, favorite bool) error {
-	return _okResultSend("PUT", mainAPIURL+"/v1/config/ping/"+ip, TestDesc{
+func (c *Client) AddIP(ip string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	return c.AddIPContext(context.Background(), ip, slaves, description, groups, favorite, opts...)
+}
+
+// AddIPContext is same as AddIP but request is bound to ctx
+func (c *Client) AddIPContext(ctx context.Context, ip string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	ip = normalizeIP(ip)
+	desc := TestDesc{
 		Description: ip + " " + description,
 		Favorite:    favorite,
-		Groups:      groups,
+		Groups:      c.withDefaultGroups(groups),
 		Slaves:      slaves,
-	})
+	}
+	for _, opt := range opts {
+		opt(&desc)
+	}
+
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/ping/"+ipPath(ip), desc)
 }
 
 // AddIPs function adds multiply ips using only one API call
-func AddIPs(ips []string, slaves []string, description string, groups []string, favorite bool) error {
+func (c *Client) AddIPs(ips []string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	return c.AddIPsContext(context.Background(), ips, slaves, description, groups, favorite, opts...)
+}
+
+// AddIPsContext is same as AddIPs but request is bound to ctx
+func (c *Client) AddIPsContext(ctx context.Context, ips []string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
 	payload := make(map[string]TestDesc, len(ips))
+	groups = c.withDefaultGroups(groups)
 
 	for _, ip := range ips {
-		payload[ip] = TestDesc{
+		ip = normalizeIP(ip)
+		desc := TestDesc{
 			Description: ip + " " + description,
 			Favorite:    favorite,
 			Groups:      groups,
 			Slaves:      slaves,
 		}
+		for _, opt := range opts {
+			opt(&desc)
+		}
+		payload[ip] = desc
 	}
 
-	return _okResultSend("PUT", mainAPIURL+"/v1/mconfig/add", map[string]interface{}{
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/mconfig/add", map[string]interface{}{
 		"ips": payload,
 	})
 }
 
 // AddIPsRaw is extended function adds multiply ips using only one API call
-func AddIPsRaw(ips map[string]TestDesc) error {
-	return _okResultSend("PUT", mainAPIURL+"/v1/mconfig/add", map[string]interface{}{
-		"ips": ips,
+func (c *Client) AddIPsRaw(ips map[string]TestDesc) error {
+	return c.AddIPsRawContext(context.Background(), ips)
+}
+
+// AddIPsRawContext is same as AddIPsRaw but request is bound to ctx
+func (c *Client) AddIPsRawContext(ctx context.Context, ips map[string]TestDesc) error {
+	payload := make(map[string]TestDesc, len(ips))
+	for ip, desc := range ips {
+		payload[normalizeIP(ip)] = desc
+	}
+
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/mconfig/add", map[string]interface{}{
+		"ips": payload,
 	})
 }
 
+// UpdateIP changes configuration of already monitored IP without re-adding it, ErrIPNotFound if IP isn't monitored
+func (c *Client) UpdateIP(ip string, patch TestDescPatch) error {
+	return c.UpdateIPContext(context.Background(), ip, patch)
+}
+
+// UpdateIPContext is same as UpdateIP but requests are bound to ctx
+func (c *Client) UpdateIPContext(ctx context.Context, ip string, patch TestDescPatch) error {
+	detail, err := c.GetIPDetailsContext(ctx, ip)
+	if nil != err {
+		return err
+	}
+
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/ping/"+ipPath(ip), patch.Apply(detail.TestDesc))
+}
+
 // DeleteIP removes one IP from cocopacket instance
-func DeleteIP(ip string) error {
-	return _okResultSend("DELETE", mainAPIURL+"/v1/config/ping/"+ip, nil)
+func (c *Client) DeleteIP(ip string) error {
+	return c.DeleteIPContext(context.Background(), ip)
+}
+
+// DeleteIPContext is same as DeleteIP but request is bound to ctx
+func (c *Client) DeleteIPContext(ctx context.Context, ip string) error {
+	return c._okResultSend(ctx, "DELETE", c.url+"/v1/config/ping/"+ipPath(ip), nil)
 }
 
 // DeleteIPs function deletes multiply ips using only one API call
-func DeleteIPs(ips []string) error {
-	return _okResultSend("PUT", mainAPIURL+"/v1/mconfig/delete", map[string]interface{}{
-		"ips": ips,
+func (c *Client) DeleteIPs(ips []string) error {
+	return c.DeleteIPsContext(context.Background(), ips)
+}
+
+// DeleteIPsContext is same as DeleteIPs but request is bound to ctx
+func (c *Client) DeleteIPsContext(ctx context.Context, ips []string) error {
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/mconfig/delete", map[string]interface{}{
+		"ips": normalizeIPs(ips),
 	})
 }
 
+// GetIPDetails returns configuration and current status of one monitored IP, ErrIPNotFound if IP isn't monitored
+func (c *Client) GetIPDetails(ip string) (IPDetail, error) {
+	return c.GetIPDetailsContext(context.Background(), ip)
+}
+
+// GetIPDetailsContext is same as GetIPDetails but request is bound to ctx
+func (c *Client) GetIPDetailsContext(ctx context.Context, ip string) (IPDetail, error) {
+	var result IPDetail
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/config/ping/"+ipPath(ip), nil, "", nil), &result)
+	if http.StatusNotFound == status {
+		return IPDetail{}, ErrIPNotFound
+	}
+	return result, err
+}
+
 // ListUsers return map with logins and associated boolean indicating if user is admin
-func ListUsers() (map[string]bool, error) {
+func (c *Client) ListUsers() (map[string]bool, error) {
+	return c.ListUsersContext(context.Background())
+}
+
+// ListUsersContext is same as ListUsers but request is bound to ctx
+func (c *Client) ListUsersContext(ctx context.Context) (map[string]bool, error) {
 	var users map[string]bool
-	err := Get(mainAPIURL+"/v1/users", &users)
+	err := c.GetContext(ctx, c.url+"/v1/users", &users)
 	return users, err
 }
 
 // AddUser adds new user (or replaces existing)
-func AddUser(login string, password string, admin bool) (map[string]bool, error) {
+func (c *Client) AddUser(login string, password string, admin bool) (map[string]bool, error) {
+	return c.AddUserContext(context.Background(), login, password, admin)
+}
+
+// AddUserContext is same as AddUser but request is bound to ctx
+func (c *Client) AddUserContext(ctx context.Context, login string, password string, admin bool) (map[string]bool, error) {
 
 	var users map[string]bool
 	t := "user"
@@ -167,7 +372,7 @@ func AddUser(login string, password string, admin bool) (map[string]bool, error)
 		t = "admin"
 	}
 
-	err := SendForm("PUT", mainAPIURL+"/v1/users", url.Values{
+	err := c.SendFormContext(ctx, "PUT", c.url+"/v1/users", url.Values{
 		"login":  []string{login},
 		"passwd": []string{password},
 		"type":   []string{t},
@@ -180,11 +385,16 @@ func AddUser(login string, password string, admin bool) (map[string]bool, error)
 }
 
 // DeleteUser removes user from master
-func DeleteUser(login string) (map[string]bool, error) {
+func (c *Client) DeleteUser(login string) (map[string]bool, error) {
+	return c.DeleteUserContext(context.Background(), login)
+}
+
+// DeleteUserContext is same as DeleteUser but request is bound to ctx
+func (c *Client) DeleteUserContext(ctx context.Context, login string) (map[string]bool, error) {
 
 	var users map[string]bool
 
-	err := Send("DELETE", mainAPIURL+"/v1/users?login="+url.QueryEscape(login), nil, &users)
+	err := c.SendContext(ctx, "DELETE", c.url+"/v1/users?login="+url.QueryEscape(login), nil, &users)
 	if nil != err {
 		return nil, err
 	}
@@ -193,25 +403,35 @@ func DeleteUser(login string) (map[string]bool, error) {
 }
 
 // GroupStats returns stats for all IPs/URLs in group for about last 24 hours with 1-hour aggregation (report -> limit only to ip+slaves selected for report using frontend)
-func GroupStats(group string, report bool) (GroupStatsData, error) {
+func (c *Client) GroupStats(group string, report bool) (GroupStatsData, error) {
+	return c.GroupStatsContext(context.Background(), group, report)
+}
+
+// GroupStatsContext is same as GroupStats but request is bound to ctx
+func (c *Client) GroupStatsContext(ctx context.Context, group string, report bool) (GroupStatsData, error) {
 	var data GroupStatsData
 	reportAdd := ""
 	if report {
 		reportAdd = "?report=true"
 	}
-	err := Get(mainAPIURL+"/v1/catstats/"+url.QueryEscape(group+"->")+reportAdd, &data)
+	err := c.GetContext(ctx, c.url+"/v1/catstats/"+url.QueryEscape(group+"->")+reportAdd, &data)
 	return data, err
 }
 
 // GroupLastStats returns stats for all IPs/URLs in group on one slave for last minute period (used for exports to other systems)
-func GroupLastStats(group string, slave string) (ips map[string]*AvgChunk, urls map[string]*AvgChunk, err error) {
+func (c *Client) GroupLastStats(group string, slave string) (ips map[string]*AvgChunk, urls map[string]*AvgChunk, err error) {
+	return c.GroupLastStatsContext(context.Background(), group, slave)
+}
+
+// GroupLastStatsContext is same as GroupLastStats but request is bound to ctx
+func (c *Client) GroupLastStatsContext(ctx context.Context, group string, slave string) (ips map[string]*AvgChunk, urls map[string]*AvgChunk, err error) {
 	var data struct {
 		Ping   map[string]*AvgChunk `json:"Ping"`
 		HTTP   map[string]*AvgChunk `json:"HTTP"`
 		Result string               `json:"result"`
 		Error  string               `json:"error"`
 	}
-	err = Get(mainAPIURL+"/v1/minute/"+url.QueryEscape(group+"->")+"?slave="+url.QueryEscape(slave), &data)
+	err = c.GetContext(ctx, c.url+"/v1/minute/"+url.QueryEscape(group+"->")+"?slave="+url.QueryEscape(slave), &data)
 	if nil == err && "error" == data.Result {
 		err = errors.New(data.Error)
 	}
@@ -219,16 +439,52 @@ func GroupLastStats(group string, slave string) (ips map[string]*AvgChunk, urls
 }
 
 // IPsSetSlaves add/remove slaves for list of ips, in case of "true" slave is added, in case of "false" slave removed, unlisted slaves are untouched
-func IPsSetSlaves(ips []string, slaves map[string]bool) error {
-	return _okResultSend("PUT", mainAPIURL+"/v1/mconfig/slaves", map[string]interface{}{
-		"ips":    ips,
+func (c *Client) IPsSetSlaves(ips []string, slaves map[string]bool) error {
+	return c.IPsSetSlavesContext(context.Background(), ips, slaves)
+}
+
+// IPsSetSlavesContext is same as IPsSetSlaves but request is bound to ctx
+func (c *Client) IPsSetSlavesContext(ctx context.Context, ips []string, slaves map[string]bool) error {
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/mconfig/slaves", map[string]interface{}{
+		"ips":    normalizeIPs(ips),
 		"slaves": slaves,
 	})
 }
 
+// AddIPsToSlave adds slave to all listed ips
+func (c *Client) AddIPsToSlave(ips []string, slave string) error {
+	return c.AddIPsToSlaveContext(context.Background(), ips, slave)
+}
+
+// AddIPsToSlaveContext is same as AddIPsToSlave but request is bound to ctx
+func (c *Client) AddIPsToSlaveContext(ctx context.Context, ips []string, slave string) error {
+	if "" == slave {
+		return ErrEmptySlaveName
+	}
+	return c.IPsSetSlavesContext(ctx, ips, map[string]bool{slave: true})
+}
+
+// RemoveIPsFromSlave removes slave from all listed ips
+func (c *Client) RemoveIPsFromSlave(ips []string, slave string) error {
+	return c.RemoveIPsFromSlaveContext(context.Background(), ips, slave)
+}
+
+// RemoveIPsFromSlaveContext is same as RemoveIPsFromSlave but request is bound to ctx
+func (c *Client) RemoveIPsFromSlaveContext(ctx context.Context, ips []string, slave string) error {
+	if "" == slave {
+		return ErrEmptySlaveName
+	}
+	return c.IPsSetSlavesContext(ctx, ips, map[string]bool{slave: false})
+}
+
 // GroupSetSlaves add/remove slaves for all ips in group, in case of "true" slave is added, in case of "false" slave removed, unlisted slaves are untouched; pass recursive=true to include subgroups
-func GroupSetSlaves(group string, slaves map[string]bool, recursive bool) error {
-	return _okResultSend("PUT", mainAPIURL+"/v1/groupslaves/"+url.QueryEscape(group+"->"), map[string]interface{}{
+func (c *Client) GroupSetSlaves(group string, slaves map[string]bool, recursive bool) error {
+	return c.GroupSetSlavesContext(context.Background(), group, slaves, recursive)
+}
+
+// GroupSetSlavesContext is same as GroupSetSlaves but request is bound to ctx
+func (c *Client) GroupSetSlavesContext(ctx context.Context, group string, slaves map[string]bool, recursive bool) error {
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/groupslaves/"+url.QueryEscape(group+"->"), map[string]interface{}{
 		"recursive": recursive,
 		"slaves":    slaves,
 	})
@@ -0,0 +1,407 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGetIPDetails(t *testing.T) {
+	var paths []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.EscapedPath())
+		switch r.URL.EscapedPath() {
+		case "/v1/config/ping/1.1.1.1", "/v1/config/ping/2001%3Adb8%3A%3A1", "/v1/config/ping/example.com":
+			writeTestJSON(w, IPDetail{
+				TestDesc: TestDesc{Description: "target", Groups: []string{"DNS->"}, Slaves: []string{"PRAGUE"}},
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	for _, ip := range []string{"1.1.1.1", "2001:db8::1", "2001:DB8:0::1", "[2001:db8::1]", "example.com"} {
+		details, err := c.GetIPDetails(ip)
+		if nil != err {
+			t.Fatalf("%s: %v", ip, err)
+		}
+		if "target" != details.Description || 1 != len(details.Slaves) || "PRAGUE" != details.Slaves[0] {
+			t.Fatalf("%s: unexpected details %+v", ip, details)
+		}
+	}
+
+	expected := []string{
+		"/v1/config/ping/1.1.1.1",
+		"/v1/config/ping/2001%3Adb8%3A%3A1",
+		"/v1/config/ping/2001%3Adb8%3A%3A1",
+		"/v1/config/ping/2001%3Adb8%3A%3A1",
+		"/v1/config/ping/example.com",
+	}
+	if len(expected) != len(paths) {
+		t.Fatalf("unexpected requests %v", paths)
+	}
+	for i := range expected {
+		if expected[i] != paths[i] {
+			t.Errorf("request %d: expected %s, got %s", i, expected[i], paths[i])
+		}
+	}
+}
+
+func TestGetIPDetailsNotMonitored(t *testing.T) {
+	c := newTestClient(t, http.NotFound)
+
+	for _, ip := range []string{"8.8.8.8", "2001:db8::2", "unknown.example.com"} {
+		if _, err := c.GetIPDetails(ip); !errors.Is(err, ErrIPNotFound) {
+			t.Fatalf("%s: expected ErrIPNotFound, got %v", ip, err)
+		}
+	}
+}
+
+// ipConfigServer stores ping tests and serves GET/PUT /v1/config/ping/:ip
+type ipConfigServer struct {
+	mu  sync.Mutex
+	ips map[string]TestDesc
+}
+
+func (s *ipConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ip := strings.TrimPrefix(r.URL.Path, "/v1/config/ping/")
+	if ip == r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		desc, ok := s.ips[ip]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeTestJSON(w, IPDetail{TestDesc: desc, LastRTT: 12.5})
+	case "PUT":
+		var desc TestDesc
+		if err := json.NewDecoder(r.Body).Decode(&desc); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.ips[ip] = desc
+		writeTestJSON(w, result{Result: "OK"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *ipConfigServer) get(ip string) TestDesc {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ips[ip]
+}
+
+func TestUpdateIPPartialPatches(t *testing.T) {
+	original := TestDesc{
+		Groups:           []string{"DNS->"},
+		Description:      "cloudflare",
+		Favorite:         false,
+		Slaves:           []string{"PRAGUE"},
+		AS:               13335,
+		PacketCount:      5,
+		PacketSizeBytes:  64,
+		ProbeIntervalSec: 30,
+	}
+
+	groups := []string{"DNS->", "ANYCAST->"}
+	description := "cloudflare dns"
+	favorite := true
+	slaves := []string{"PRAGUE", "LONDON"}
+	as := int64(1)
+	count, size, interval := 10, 1400, 60
+
+	// every field of patch is set in half of combinations
+	setters := []func(p *TestDescPatch, d *TestDesc){
+		func(p *TestDescPatch, d *TestDesc) { p.Groups = &groups; d.Groups = groups },
+		func(p *TestDescPatch, d *TestDesc) { p.Description = &description; d.Description = description },
+		func(p *TestDescPatch, d *TestDesc) { p.Favorite = &favorite; d.Favorite = favorite },
+		func(p *TestDescPatch, d *TestDesc) { p.Slaves = &slaves; d.Slaves = slaves },
+		func(p *TestDescPatch, d *TestDesc) { p.AS = &as; d.AS = as },
+		func(p *TestDescPatch, d *TestDesc) { p.PacketCount = &count; d.PacketCount = count },
+		func(p *TestDescPatch, d *TestDesc) { p.PacketSizeBytes = &size; d.PacketSizeBytes = size },
+		func(p *TestDescPatch, d *TestDesc) { p.ProbeIntervalSec = &interval; d.ProbeIntervalSec = interval },
+	}
+
+	s := &ipConfigServer{ips: map[string]TestDesc{}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for mask := 0; mask < 1<<len(setters); mask++ {
+		s.mu.Lock()
+		s.ips["1.1.1.1"] = original
+		s.mu.Unlock()
+
+		var patch TestDescPatch
+		expected := original
+		for i, set := range setters {
+			if 0 != mask&(1<<i) {
+				set(&patch, &expected)
+			}
+		}
+
+		if err := c.UpdateIP("1.1.1.1", patch); nil != err {
+			t.Fatalf("mask %b: %v", mask, err)
+		}
+		if got := s.get("1.1.1.1"); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("mask %b: expected %+v, got %+v", mask, expected, got)
+		}
+	}
+}
+
+func TestUpdateIPNotMonitored(t *testing.T) {
+	s := &ipConfigServer{ips: map[string]TestDesc{}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	description := "new"
+	if err := c.UpdateIP("1.1.1.1", TestDescPatch{Description: &description}); !errors.Is(err, ErrIPNotFound) {
+		t.Fatalf("expected ErrIPNotFound, got %v", err)
+	}
+	if 0 != len(s.ips) {
+		t.Fatalf("IP was created: %v", s.ips)
+	}
+}
+
+func TestNormalizeIP(t *testing.T) {
+	for input, expected := range map[string]string{
+		"1.1.1.1":              "1.1.1.1",
+		"2001:DB8:0:0::1":      "2001:db8::1",
+		"[2001:db8::1]":        "2001:db8::1",
+		"::ffff:192.0.2.1":     "192.0.2.1",
+		"::FFFF:C000:0201":     "192.0.2.1",
+		"::1":                  "::1",
+		"example.com":          "example.com",
+		"2001:db8::1%eth0":     "2001:db8::1%eth0",
+		"https://example.com/": "https://example.com/",
+	} {
+		if got := normalizeIP(input); expected != got {
+			t.Errorf("normalizeIP(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+func TestIPPath(t *testing.T) {
+	for input, expected := range map[string]string{
+		"1.1.1.1":          "1.1.1.1",
+		"2001:db8::1":      "2001%3Adb8%3A%3A1",
+		"[2001:DB8::1]":    "2001%3Adb8%3A%3A1",
+		"::ffff:192.0.2.1": "192.0.2.1",
+		"example.com":      "example.com",
+	} {
+		if got := ipPath(input); expected != got {
+			t.Errorf("ipPath(%q): expected %q, got %q", input, expected, got)
+		}
+	}
+}
+
+// recordedRequest is one request received by recordingServer
+type recordedRequest struct {
+	method string
+	path   string // escaped path
+	body   map[string]json.RawMessage
+}
+
+// recordingServer records all requests and answers {"result":"OK"}
+type recordingServer struct {
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+func (s *recordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body map[string]json.RawMessage
+	json.NewDecoder(r.Body).Decode(&body)
+
+	s.mu.Lock()
+	s.requests = append(s.requests, recordedRequest{method: r.Method, path: r.URL.EscapedPath(), body: body})
+	s.mu.Unlock()
+
+	writeTestJSON(w, result{Result: "OK"})
+}
+
+func (s *recordingServer) last() recordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[len(s.requests)-1]
+}
+
+func TestAddIPIPv6(t *testing.T) {
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for input, expectedPath := range map[string]string{
+		"2001:db8::1":      "/v1/config/ping/2001%3Adb8%3A%3A1",
+		"2001:DB8:0::1":    "/v1/config/ping/2001%3Adb8%3A%3A1",
+		"::ffff:192.0.2.1": "/v1/config/ping/192.0.2.1",
+		"192.0.2.1":        "/v1/config/ping/192.0.2.1",
+	} {
+		if err := c.AddIP(input, []string{"PRAGUE"}, "test", nil, false); nil != err {
+			t.Fatalf("%s: %v", input, err)
+		}
+		if r := s.last(); "PUT" != r.method || expectedPath != r.path {
+			t.Errorf("%s: expected PUT %s, got %s %s", input, expectedPath, r.method, r.path)
+		}
+	}
+}
+
+func TestAddIPsNormalizesKeys(t *testing.T) {
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	// dual-stack target with both address families in one call
+	if err := c.AddIPs([]string{"192.0.2.1", "2001:DB8::1", "::ffff:198.51.100.7"}, []string{"PRAGUE"}, "dual", nil, false); nil != err {
+		t.Fatal(err)
+	}
+
+	var ips map[string]TestDesc
+	if err := json.Unmarshal(s.last().body["ips"], &ips); nil != err {
+		t.Fatal(err)
+	}
+	for _, ip := range []string{"192.0.2.1", "2001:db8::1", "198.51.100.7"} {
+		desc, ok := ips[ip]
+		if !ok {
+			t.Fatalf("%s missing in payload %v", ip, ips)
+		}
+		if ip+" dual" != desc.Description {
+			t.Errorf("%s: unexpected description %q", ip, desc.Description)
+		}
+	}
+	if 3 != len(ips) {
+		t.Fatalf("unexpected payload %v", ips)
+	}
+
+	if err := c.AddIPsRaw(map[string]TestDesc{"2001:0DB8::2": {}}); nil != err {
+		t.Fatal(err)
+	}
+	ips = nil
+	json.Unmarshal(s.last().body["ips"], &ips)
+	if _, ok := ips["2001:db8::2"]; !ok || 1 != len(ips) {
+		t.Fatalf("unexpected raw payload %v", ips)
+	}
+
+	if err := c.DeleteIPs([]string{"2001:DB8::1", "::ffff:198.51.100.7"}); nil != err {
+		t.Fatal(err)
+	}
+	var deleted []string
+	json.Unmarshal(s.last().body["ips"], &deleted)
+	if !reflect.DeepEqual([]string{"2001:db8::1", "198.51.100.7"}, deleted) {
+		t.Fatalf("unexpected delete payload %v", deleted)
+	}
+}
+
+func TestAddIPProbeOptions(t *testing.T) {
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	// without options probe fields must not be sent at all so server keeps slave defaults
+	if err := c.AddIP("1.1.1.1", []string{"PRAGUE"}, "test", nil, false); nil != err {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"packetCount", "packetSize", "probeInterval"} {
+		if _, ok := s.last().body[key]; ok {
+			t.Errorf("zero %s should be omitted, got %s", key, s.last().body[key])
+		}
+	}
+
+	if err := c.AddIP("1.1.1.1", []string{"PRAGUE"}, "test", nil, false, WithPacketCount(10), WithPacketSize(1400), WithProbeInterval(30)); nil != err {
+		t.Fatal(err)
+	}
+	body := s.last().body
+	if "10" != string(body["packetCount"]) || "1400" != string(body["packetSize"]) || "30" != string(body["probeInterval"]) {
+		t.Fatalf("unexpected payload %v", body)
+	}
+
+	if err := c.AddIPs([]string{"1.1.1.1", "8.8.8.8"}, []string{"PRAGUE"}, "test", nil, false, WithPacketCount(3)); nil != err {
+		t.Fatal(err)
+	}
+	var ips map[string]map[string]json.RawMessage
+	json.Unmarshal(s.last().body["ips"], &ips)
+	for ip, desc := range ips {
+		_, hasSize := desc["packetSize"]
+		_, hasInterval := desc["probeInterval"]
+		if "3" != string(desc["packetCount"]) || hasSize || hasInterval {
+			t.Errorf("%s: unexpected payload %v", ip, desc)
+		}
+	}
+	if 2 != len(ips) {
+		t.Fatalf("unexpected payload %v", ips)
+	}
+}
+
+func TestAddSlaveIPv6(t *testing.T) {
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for input, expected := range map[string]string{
+		"2001:db8::10":      `"2001:db8::10"`,
+		"::ffff:192.0.2.10": `"192.0.2.10"`,
+		"192.0.2.10":        `"192.0.2.10"`,
+	} {
+		if err := c.AddSlave(net.ParseIP(input), 3030, "SLAVE", ""); nil != err {
+			t.Fatalf("%s: %v", input, err)
+		}
+		r := s.last()
+		if "POST" != r.method || "/v1/slaves" != r.path || expected != string(r.body["ip"]) || "3030" != string(r.body["port"]) {
+			t.Errorf("%s: unexpected request %s %s %s", input, r.method, r.path, r.body)
+		}
+	}
+
+	if err := c.AddSlave(nil, 3030, "SLAVE", ""); nil == err {
+		t.Fatal("expected error for nil ip")
+	}
+}
+
+func TestGetSlavesSourcesAll(t *testing.T) {
+	var calls int
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// raw json with missing and empty source fields as sent by older slaves
+		w.Write([]byte(`{
+			"DUAL": {"host": "10.0.0.1", "status": "online", "source": "192.0.2.1", "source6": "2001:db8::1"},
+			"V4ONLY": {"host": "10.0.0.2", "status": "online", "source": "192.0.2.2"},
+			"V6ONLY": {"host": "10.0.0.3", "status": "online", "source": "", "source6": "2001:db8::3"},
+			"NONE": {"host": "10.0.0.4", "status": "offline"}
+		}`))
+	})
+
+	ipv4, ipv6, err := c.GetSlavesSourcesAll()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 1 != calls {
+		t.Fatalf("expected one request, got %d", calls)
+	}
+
+	expected4 := map[string]string{"DUAL": "192.0.2.1", "V4ONLY": "192.0.2.2", "V6ONLY": "", "NONE": ""}
+	expected6 := map[string]string{"DUAL": "2001:db8::1", "V4ONLY": "", "V6ONLY": "2001:db8::3", "NONE": ""}
+	if !reflect.DeepEqual(expected4, ipv4) {
+		t.Errorf("expected ipv4 %v, got %v", expected4, ipv4)
+	}
+	if !reflect.DeepEqual(expected6, ipv6) {
+		t.Errorf("expected ipv6 %v, got %v", expected6, ipv6)
+	}
+}
+
+func TestGetSlavesSourcesAllError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	ipv4, ipv6, err := c.GetSlavesSourcesAll()
+	if nil == err || nil != ipv4 || nil != ipv6 {
+		t.Fatalf("expected error and nil maps, got %v %v %v", ipv4, ipv6, err)
+	}
+}
@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// AuditEntry is one record of API changes log
+type AuditEntry struct {
+	Timestamp time.Time              `json:"timestamp"`
+	UserLogin string                 `json:"user"`
+	SourceIP  string                 `json:"sourceIP"` // address request came from
+	Action    string                 `json:"action"`   // like "add", "delete", "update"
+	Target    string                 `json:"target"`   // IP, URL, slave or group affected
+	Changes   map[string]interface{} `json:"changes"`
+}
+
+// AuditPollInterval is delay between requests of StreamAuditLog
+var AuditPollInterval = 10 * time.Second
+
+// GetAuditLog returns changes made in period from-to ordered by time, empty user/action match everything, zero to means now
+func (c *Client) GetAuditLog(from, to time.Time, user string, action string) ([]AuditEntry, error) {
+	return c.GetAuditLogContext(context.Background(), from, to, user, action)
+}
+
+// GetAuditLogContext is same as GetAuditLog but request is bound to ctx
+func (c *Client) GetAuditLogContext(ctx context.Context, from, to time.Time, user string, action string) ([]AuditEntry, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if to.Before(from) {
+		return nil, errors.New("invalid period: from " + from.Format(time.RFC3339) + " is after to " + to.Format(time.RFC3339))
+	}
+
+	query := url.Values{
+		"from": []string{strconv.FormatInt(from.Unix(), 10)},
+		"to":   []string{strconv.FormatInt(to.Unix(), 10)},
+	}
+	if "" != user {
+		query.Set("user", user)
+	}
+	if "" != action {
+		query.Set("action", action)
+	}
+
+	var entries []AuditEntry
+	err := c.GetContext(ctx, c.url+"/v1/audit?"+query.Encode(), &entries)
+	if nil != err {
+		return nil, err
+	}
+
+	// server filters by second precision and may ignore user/action on older versions
+	result := make([]AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Timestamp.Before(from) || entry.Timestamp.After(to) ||
+			("" != user && entry.UserLogin != user) || ("" != action && entry.Action != action) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Timestamp.Before(result[j].Timestamp) })
+
+	return result, nil
+}
+
+// StreamAuditLog sends entries logged since from to ch and then polls for new ones every AuditPollInterval
+// until ctx is done (ctx.Err() is returned) or request fails; ch isn't closed
+func (c *Client) StreamAuditLog(ctx context.Context, from time.Time, ch chan<- AuditEntry) error {
+	// entries with timestamp equal to from were already sent, server period is inclusive
+	seen := map[string]bool{}
+
+	for {
+		entries, err := c.GetAuditLogContext(ctx, from, time.Time{}, "", "")
+		if nil != err {
+			if nil != ctx.Err() {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		for _, entry := range entries {
+			key := auditKey(entry)
+			if entry.Timestamp.Equal(from) && seen[key] {
+				continue
+			}
+			if entry.Timestamp.After(from) {
+				from = entry.Timestamp
+				seen = map[string]bool{}
+			}
+			seen[key] = true
+
+			select {
+			case ch <- entry:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err := sleepContext(ctx, AuditPollInterval); nil != err {
+			return err
+		}
+	}
+}
+
+// auditKey identifies entry among others with same timestamp
+func auditKey(entry AuditEntry) string {
+	return entry.UserLogin + "\x00" + entry.SourceIP + "\x00" + entry.Action + "\x00" + entry.Target
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// auditServer answers GET /v1/audit with entries in period, with filtering=false it ignores user and action like old versions
+type auditServer struct {
+	mu        sync.Mutex
+	entries   []AuditEntry
+	filtering bool
+	queries   []url.Values
+}
+
+func (s *auditServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if "/v1/audit" != r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+	query := r.URL.Query()
+	s.queries = append(s.queries, query)
+
+	from, _ := strconv.ParseInt(query.Get("from"), 10, 64)
+	to, _ := strconv.ParseInt(query.Get("to"), 10, 64)
+
+	result := []AuditEntry{}
+	for _, entry := range s.entries {
+		if entry.Timestamp.Unix() < from || entry.Timestamp.Unix() > to {
+			continue
+		}
+		if s.filtering && (("" != query.Get("user") && query.Get("user") != entry.UserLogin) ||
+			("" != query.Get("action") && query.Get("action") != entry.Action)) {
+			continue
+		}
+		result = append(result, entry)
+	}
+	writeTestJSON(w, result)
+}
+
+func auditFixture(base time.Time) []AuditEntry {
+	return []AuditEntry{
+		{Timestamp: base.Add(3 * time.Minute), UserLogin: "alice", Action: "delete", Target: "8.8.8.8"},
+		{Timestamp: base.Add(time.Minute), UserLogin: "alice", Action: "add", Target: "1.1.1.1"},
+		{Timestamp: base.Add(2 * time.Minute), UserLogin: "bob", Action: "add", Target: "9.9.9.9"},
+		{Timestamp: base.Add(4 * time.Minute), UserLogin: "bob", Action: "update", Target: "1.1.1.1"},
+		{Timestamp: base.Add(5 * time.Minute), UserLogin: "alice", Action: "add", Target: "PRAGUE"},
+	}
+}
+
+func TestGetAuditLogFilters(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, filtering := range []bool{true, false} {
+		s := &auditServer{entries: auditFixture(base), filtering: filtering}
+		c := newTestClient(t, s.ServeHTTP)
+
+		for _, tc := range []struct {
+			user     string
+			action   string
+			expected []string
+		}{
+			{"", "", []string{"1.1.1.1", "9.9.9.9", "8.8.8.8", "1.1.1.1", "PRAGUE"}},
+			{"alice", "", []string{"1.1.1.1", "8.8.8.8", "PRAGUE"}},
+			{"", "add", []string{"1.1.1.1", "9.9.9.9", "PRAGUE"}},
+			{"alice", "add", []string{"1.1.1.1", "PRAGUE"}},
+			{"bob", "delete", []string{}},
+			{"carol", "", []string{}},
+		} {
+			entries, err := c.GetAuditLog(base, base.Add(time.Hour), tc.user, tc.action)
+			if nil != err {
+				t.Fatal(err)
+			}
+			targets := []string{}
+			for _, entry := range entries {
+				targets = append(targets, entry.Target)
+			}
+			if !reflect.DeepEqual(tc.expected, targets) {
+				t.Errorf("filtering=%v user=%q action=%q: expected %v, got %v", filtering, tc.user, tc.action, tc.expected, targets)
+			}
+
+			query := s.queries[len(s.queries)-1]
+			if tc.user != query.Get("user") || tc.action != query.Get("action") {
+				t.Errorf("user=%q action=%q: unexpected query %v", tc.user, tc.action, query)
+			}
+		}
+	}
+}
+
+func TestGetAuditLogPeriod(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := newTestClient(t, (&auditServer{entries: auditFixture(base)}).ServeHTTP)
+
+	entries, err := c.GetAuditLog(base.Add(2*time.Minute), base.Add(4*time.Minute), "bob", "")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 2 != len(entries) || "9.9.9.9" != entries[0].Target || "1.1.1.1" != entries[1].Target {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+
+	if _, err := c.GetAuditLog(base.Add(time.Hour), base, "", ""); nil == err {
+		t.Fatal("expected error for inverted period")
+	}
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AvailabilityRecord is SLA-style availability of one IP
+type AvailabilityRecord struct {
+	UptimePercent float64   // part of successful probes in percent, computed only over period with data
+	TotalProbes   int       // count of probes from all slaves
+	LostProbes    int       // count of lost probes
+	FirstSeen     time.Time // start of first day with data, zero if there is no data
+	DataComplete  bool      // false if some days of period have no data
+}
+
+// GetAvailabilitySummary returns availability of ips during last periodDays (whole days in UTC) aggregated from
+// daily historical stats of all slaves, IPs which aren't monitored are reported by returned MultiError
+func (c *Client) GetAvailabilitySummary(ips []string, periodDays int) (map[string]AvailabilityRecord, error) {
+	return c.GetAvailabilitySummaryContext(context.Background(), ips, periodDays)
+}
+
+// GetAvailabilitySummaryContext is same as GetAvailabilitySummary but requests are bound to ctx
+func (c *Client) GetAvailabilitySummaryContext(ctx context.Context, ips []string, periodDays int) (map[string]AvailabilityRecord, error) {
+	if periodDays <= 0 {
+		return nil, errors.New("periodDays must be positive")
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	// historical stats are available only per group so every ip is queried by its first group
+	errs := MultiError{}
+	byGroup := map[string][]string{}
+	for _, ip := range ips {
+		ip = normalizeIP(ip)
+		desc, ok := config.Ping.IPs[ip]
+		if !ok || 0 == len(desc.Groups) {
+			errs[ip] = ErrIPNotFound
+			continue
+		}
+		group := strings.TrimSuffix(desc.Groups[0], groupSuffix)
+		byGroup[group] = append(byGroup[group], ip)
+	}
+
+	groups := make([]string, 0, len(byGroup))
+	for group := range byGroup {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	from := to.Add(-time.Duration(periodDays) * 24 * time.Hour)
+
+	var mu sync.Mutex
+	result := make(map[string]AvailabilityRecord, len(ips))
+
+	c.forEach(groups, func(group string) {
+		data, err := c.historicalStats(ctx, group, "", from, to, 24*time.Hour)
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, ip := range byGroup[group] {
+			if nil != err {
+				errs[ip] = err
+				continue
+			}
+			result[ip] = calcAvailability(data.Ping[ip], periodDays)
+		}
+	})
+
+	return result, errs.errOrNil()
+}
+
+// calcAvailability sums daily stats (unix timestamp -> chunk) of period
+func calcAvailability(days map[int64]*AvgChunk, periodDays int) AvailabilityRecord {
+	var record AvailabilityRecord
+	daysWithData := 0
+	for ts, chunk := range days {
+		if nil == chunk || 0 == chunk.Count {
+			continue
+		}
+		daysWithData++
+		record.TotalProbes += chunk.Count
+		record.LostProbes += chunk.Loss
+		if t := time.Unix(ts, 0).UTC(); record.FirstSeen.IsZero() || t.Before(record.FirstSeen) {
+			record.FirstSeen = t
+		}
+	}
+
+	if 0 != record.TotalProbes {
+		record.UptimePercent = float64(record.TotalProbes-record.LostProbes) / float64(record.TotalProbes) * 100
+	}
+	record.DataComplete = daysWithData >= periodDays
+	return record
+}
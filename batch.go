@@ -0,0 +1,273 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strconv"
+)
+
+// AddProgress is reported by AddIPsWithProgress after every sent batch
+type AddProgress struct {
+	BatchIndex int   // index of finished batch starting from 0
+	Total      int   // total count of batches
+	Err        error // result of batch
+}
+
+// AddIPsWithProgress is same as AddIPsRaw but sends ips in batches of batchSize (ordered by ip), failed batch doesn't stop
+// next ones; progress (if not nil) receives result of every batch and is closed on return, errors are collected into MultiError
+func (c *Client) AddIPsWithProgress(ips map[string]TestDesc, batchSize int, progress chan<- AddProgress) error {
+	return c.AddIPsWithProgressContext(context.Background(), ips, batchSize, progress)
+}
+
+// AddIPsWithProgressContext is same as AddIPsWithProgress but requests are bound to ctx
+func (c *Client) AddIPsWithProgressContext(ctx context.Context, ips map[string]TestDesc, batchSize int, progress chan<- AddProgress) error {
+	if nil != progress {
+		defer close(progress)
+	}
+
+	keys := make([]string, 0, len(ips))
+	for ip := range ips {
+		keys = append(keys, ip)
+	}
+	sort.Strings(keys)
+
+	parts := batches(keys, batchSize)
+	errs := MultiError{}
+
+	for i, part := range parts {
+		payload := make(map[string]TestDesc, len(part))
+		for _, ip := range part {
+			payload[ip] = ips[ip]
+		}
+
+		err := c.AddIPsRawContext(ctx, payload)
+		if nil != err {
+			errs["batch "+strconv.Itoa(i)] = err
+		}
+
+		if nil != progress {
+			select {
+			case progress <- AddProgress{BatchIndex: i, Total: len(parts), Err: err}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	return errs.errOrNil()
+}
+
+// batches splits list into parts of size elements (only one part if size < 1)
+func batches(list []string, size int) [][]string {
+	if size < 1 {
+		size = len(list)
+	}
+
+	parts := [][]string{}
+	for len(list) > 0 {
+		n := size
+		if n > len(list) {
+			n = len(list)
+		}
+		parts = append(parts, list[:n])
+		list = list[n:]
+	}
+	return parts
+}
+
+// DeleteAllConfirmation has to be passed to DeleteAllIPs
+const DeleteAllConfirmation = "CONFIRM"
+
+// DefaultBulkBatchSize is count of IPs sent in one request by bulk helpers unless changed by WithBulkBatchSize
+const DefaultBulkBatchSize = 500
+
+// WithBulkBatchSize sets count of IPs sent in one request by bulk helpers like DeleteAllIPs, DeleteIPsSafe or
+// BulkUpdateDescriptions, n < 1 means DefaultBulkBatchSize
+func WithBulkBatchSize(n int) Option {
+	return func(c *Client) {
+		c.optionsMu.Lock()
+		c.bulkBatchSize = n
+		c.optionsMu.Unlock()
+	}
+}
+
+// batchSize returns count of IPs sent in one request by bulk helpers
+func (c *Client) batchSize() int {
+	c.optionsMu.RLock()
+	defer c.optionsMu.RUnlock()
+	if c.bulkBatchSize < 1 {
+		return DefaultBulkBatchSize
+	}
+	return c.bulkBatchSize
+}
+
+// DeleteAllIPs removes all monitored IPs (URL tests are untouched) in batches, confirm must be "CONFIRM" otherwise
+// ErrNotConfirmed is returned; failed batch doesn't stop next ones, errors are collected into MultiError
+func (c *Client) DeleteAllIPs(confirm string) error {
+	return c.DeleteAllIPsContext(context.Background(), confirm)
+}
+
+// DeleteAllIPsContext is same as DeleteAllIPs but requests are bound to ctx
+func (c *Client) DeleteAllIPsContext(ctx context.Context, confirm string) error {
+	if DeleteAllConfirmation != confirm {
+		return ErrNotConfirmed
+	}
+
+	ips, err := c.DryRunDeleteAllIPsContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	errs := MultiError{}
+	for i, part := range batches(ips, c.batchSize()) {
+		if err := c.DeleteIPsContext(ctx, part); nil != err {
+			errs["batch "+strconv.Itoa(i)] = err
+		}
+		if nil != ctx.Err() {
+			return ctx.Err()
+		}
+	}
+
+	return errs.errOrNil()
+}
+
+// DryRunDeleteAllIPs returns sorted list of IPs which would be removed by DeleteAllIPs
+func (c *Client) DryRunDeleteAllIPs() ([]string, error) {
+	return c.DryRunDeleteAllIPsContext(context.Background())
+}
+
+// DryRunDeleteAllIPsContext is same as DryRunDeleteAllIPs but request is bound to ctx
+func (c *Client) DryRunDeleteAllIPsContext(ctx context.Context) ([]string, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	ips := make([]string, 0, len(config.Ping.IPs))
+	for ip := range config.Ping.IPs {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+
+	return ips, nil
+}
+
+// BulkUpdateReport is result of BulkUpdateDescriptions
+type BulkUpdateReport struct {
+	Updated  []string
+	NotFound []string
+	Failed   map[string]error
+}
+
+// BulkUpdateDescriptions replaces descriptions of monitored IPs (ip -> new description) keeping rest of their configuration,
+// IPs are sent in batches (see WithBulkBatchSize); failed batches are returned as MultiError and their IPs listed in report.Failed
+func (c *Client) BulkUpdateDescriptions(updates map[string]string) (BulkUpdateReport, error) {
+	return c.BulkUpdateDescriptionsContext(context.Background(), updates)
+}
+
+// BulkUpdateDescriptionsContext is same as BulkUpdateDescriptions but requests are bound to ctx
+func (c *Client) BulkUpdateDescriptionsContext(ctx context.Context, updates map[string]string) (BulkUpdateReport, error) {
+	report := BulkUpdateReport{
+		Updated:  []string{},
+		NotFound: []string{},
+		Failed:   map[string]error{},
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return report, err
+	}
+
+	ips := make([]string, 0, len(updates))
+	for ip := range updates {
+		if _, ok := config.Ping.IPs[ip]; !ok {
+			report.NotFound = append(report.NotFound, ip)
+			continue
+		}
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	sort.Strings(report.NotFound)
+
+	errs := MultiError{}
+	for i, part := range batches(ips, c.batchSize()) {
+		payload := make(map[string]TestDesc, len(part))
+		for _, ip := range part {
+			desc := config.Ping.IPs[ip]
+			desc.Description = updates[ip]
+			payload[ip] = desc
+		}
+
+		if err := c.AddIPsRawContext(ctx, payload); nil != err {
+			errs["batch "+strconv.Itoa(i)] = err
+			for _, ip := range part {
+				report.Failed[ip] = err
+			}
+			continue
+		}
+		report.Updated = append(report.Updated, part...)
+	}
+
+	return report, errs.errOrNil()
+}
+
+// ErrorStrategy selects behaviour of DeleteIPsSafe on failure
+type ErrorStrategy int
+
+// supported error strategies
+const (
+	ContinueOnError ErrorStrategy = iota // retry IPs of failed batch one by one and continue with next batches
+	AbortOnError                         // stop on first failed batch
+)
+
+// DeleteReport is result of DeleteIPsSafe
+type DeleteReport struct {
+	Deleted      []string
+	Failed       map[string]error
+	NotAttempted []string // IPs skipped after abort
+}
+
+// DeleteIPsSafe deletes ips in batches (see WithBulkBatchSize) so one invalid IP doesn't block removal of all others,
+// returned error is MultiError of failed IPs (same as report.Failed)
+func (c *Client) DeleteIPsSafe(ips []string, strategy ErrorStrategy) (DeleteReport, error) {
+	return c.DeleteIPsSafeContext(context.Background(), ips, strategy)
+}
+
+// DeleteIPsSafeContext is same as DeleteIPsSafe but requests are bound to ctx
+func (c *Client) DeleteIPsSafeContext(ctx context.Context, ips []string, strategy ErrorStrategy) (DeleteReport, error) {
+	report := DeleteReport{
+		Deleted:      []string{},
+		Failed:       map[string]error{},
+		NotAttempted: []string{},
+	}
+
+	parts := batches(ips, c.batchSize())
+	for i, part := range parts {
+		err := c.DeleteIPsContext(ctx, part)
+		if nil == err {
+			report.Deleted = append(report.Deleted, part...)
+			continue
+		}
+
+		if AbortOnError == strategy || nil != ctx.Err() {
+			for _, ip := range part {
+				report.Failed[ip] = err
+			}
+			for _, rest := range parts[i+1:] {
+				report.NotAttempted = append(report.NotAttempted, rest...)
+			}
+			break
+		}
+
+		// find out which IPs caused batch failure
+		for _, ip := range part {
+			if err := c.DeleteIPContext(ctx, ip); nil != err {
+				report.Failed[ip] = err
+			} else {
+				report.Deleted = append(report.Deleted, ip)
+			}
+		}
+	}
+
+	return report, MultiError(report.Failed).errOrNil()
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// batchServer records IPs of every bulk request (PUT /v1/mconfig/add or delete) and single IP deletes,
+// requests containing rejected IPs fail
+type batchServer struct {
+	mu      sync.Mutex
+	reject  map[string]bool
+	batches [][]string
+}
+
+func (s *batchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var payload map[string]json.RawMessage
+	if "DELETE" != r.Method {
+		if err := json.NewDecoder(r.Body).Decode(&payload); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	var ips []string
+	switch {
+	case "DELETE" == r.Method && strings.HasPrefix(r.URL.Path, "/v1/config/ping/"):
+		ips = []string{strings.TrimPrefix(r.URL.Path, "/v1/config/ping/")}
+	case "/v1/mconfig/add" == r.URL.Path:
+		var added map[string]TestDesc
+		json.Unmarshal(payload["ips"], &added)
+		for ip := range added {
+			ips = append(ips, ip)
+		}
+	case "/v1/mconfig/delete" == r.URL.Path:
+		json.Unmarshal(payload["ips"], &ips)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	sort.Strings(ips)
+
+	s.mu.Lock()
+	s.batches = append(s.batches, ips)
+	s.mu.Unlock()
+
+	for _, ip := range ips {
+		if s.reject[ip] {
+			w.WriteHeader(http.StatusBadRequest)
+			writeTestJSON(w, result{Result: "error", Error: "invalid ip " + ip})
+			return
+		}
+	}
+	writeTestJSON(w, result{Result: "OK"})
+}
+
+// sent returns copy of recorded batches
+func (s *batchServer) sent() [][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([][]string{}, s.batches...)
+}
+
+func TestAddIPsWithProgress(t *testing.T) {
+	s := &batchServer{reject: map[string]bool{"10.0.0.3": true}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	ips := map[string]TestDesc{}
+	for i := 1; i <= 7; i++ {
+		ips[fmt.Sprintf("10.0.0.%d", i)] = TestDesc{Slaves: []string{"PRAGUE"}}
+	}
+
+	progress := make(chan AddProgress)
+	var reported []AddProgress
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for p := range progress {
+			reported = append(reported, p)
+		}
+	}()
+
+	err := c.AddIPsWithProgress(ips, 2, progress)
+	<-done
+
+	// second batch fails but third and fourth are still sent
+	expected := [][]string{{"10.0.0.1", "10.0.0.2"}, {"10.0.0.3", "10.0.0.4"}, {"10.0.0.5", "10.0.0.6"}, {"10.0.0.7"}}
+	if sent := s.sent(); !reflect.DeepEqual(expected, sent) {
+		t.Fatalf("expected batches %v, got %v", expected, sent)
+	}
+
+	if 4 != len(reported) {
+		t.Fatalf("expected 4 progress reports, got %v", reported)
+	}
+	for i, p := range reported {
+		if i != p.BatchIndex || 4 != p.Total || (1 == i) != (nil != p.Err) {
+			t.Errorf("unexpected progress %d: %+v", i, p)
+		}
+	}
+
+	multi, ok := err.(MultiError)
+	if !ok || 1 != len(multi) || nil == multi["batch 1"] {
+		t.Fatalf("expected MultiError with batch 1, got %v", err)
+	}
+}
+
+func TestAddIPsWithProgressNilChannel(t *testing.T) {
+	s := &batchServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	if err := c.AddIPsWithProgress(map[string]TestDesc{"10.0.0.1": {}, "10.0.0.2": {}}, 0, nil); nil != err {
+		t.Fatal(err)
+	}
+	if sent := s.sent(); 1 != len(sent) || 2 != len(sent[0]) {
+		t.Fatalf("batchSize < 1 should send everything at once, got %v", sent)
+	}
+}
+
+func TestDeleteIPsSafeContinue(t *testing.T) {
+	s := &batchServer{reject: map[string]bool{"10.0.0.2": true, "10.0.0.6": true}}
+	c := newTestClient(t, s.ServeHTTP, WithBulkBatchSize(3))
+
+	ips := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7"}
+	report, err := c.DeleteIPsSafe(ips, ContinueOnError)
+
+	// failed batches are retried one by one to find rejected IPs
+	expected := [][]string{
+		{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, {"10.0.0.1"}, {"10.0.0.2"}, {"10.0.0.3"},
+		{"10.0.0.4", "10.0.0.5", "10.0.0.6"}, {"10.0.0.4"}, {"10.0.0.5"}, {"10.0.0.6"},
+		{"10.0.0.7"},
+	}
+	if sent := s.sent(); !reflect.DeepEqual(expected, sent) {
+		t.Fatalf("expected requests %v, got %v", expected, sent)
+	}
+
+	if deleted := []string{"10.0.0.1", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.7"}; !reflect.DeepEqual(deleted, report.Deleted) {
+		t.Fatalf("expected deleted %v, got %v", deleted, report.Deleted)
+	}
+	if 2 != len(report.Failed) || nil == report.Failed["10.0.0.2"] || nil == report.Failed["10.0.0.6"] || 0 != len(report.NotAttempted) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if multi, ok := err.(MultiError); !ok || 2 != len(multi) {
+		t.Fatalf("expected MultiError with 2 IPs, got %v", err)
+	}
+}
+
+func TestDeleteIPsSafeAbort(t *testing.T) {
+	s := &batchServer{reject: map[string]bool{"10.0.0.4": true}}
+	c := newTestClient(t, s.ServeHTTP, WithBulkBatchSize(2))
+
+	ips := []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7"}
+	report, err := c.DeleteIPsSafe(ips, AbortOnError)
+
+	expected := [][]string{{"10.0.0.1", "10.0.0.2"}, {"10.0.0.3", "10.0.0.4"}}
+	if sent := s.sent(); !reflect.DeepEqual(expected, sent) {
+		t.Fatalf("expected requests %v, got %v", expected, sent)
+	}
+
+	// whole failed batch is reported as failed, nothing after it is attempted
+	if !reflect.DeepEqual([]string{"10.0.0.1", "10.0.0.2"}, report.Deleted) ||
+		2 != len(report.Failed) || nil == report.Failed["10.0.0.3"] || nil == report.Failed["10.0.0.4"] ||
+		!reflect.DeepEqual([]string{"10.0.0.5", "10.0.0.6", "10.0.0.7"}, report.NotAttempted) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if nil == err {
+		t.Fatal("expected error")
+	}
+}
+
+func TestDeleteIPsSafeBatchSize(t *testing.T) {
+	ips := make([]string, 1200)
+	for i := range ips {
+		ips[i] = fmt.Sprintf("10.0.%d.%d", i/256, i%256)
+	}
+
+	for _, tc := range []struct {
+		opts     []Option
+		expected []int
+	}{
+		{nil, []int{500, 500, 200}},
+		{[]Option{WithBulkBatchSize(0)}, []int{500, 500, 200}},
+		{[]Option{WithBulkBatchSize(1000)}, []int{1000, 200}},
+	} {
+		s := &batchServer{}
+		c := newTestClient(t, s.ServeHTTP, tc.opts...)
+		if _, err := c.DeleteIPsSafe(ips, AbortOnError); nil != err {
+			t.Fatal(err)
+		}
+		sizes := []int{}
+		for _, batch := range s.sent() {
+			sizes = append(sizes, len(batch))
+		}
+		if !reflect.DeepEqual(tc.expected, sizes) {
+			t.Errorf("expected batch sizes %v, got %v", tc.expected, sizes)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package api
+
+import (
+	"errors"
+	"strings"
+)
+
+// TestDescBuilder builds TestDesc with chained calls:
+//
+//	desc, err := api.NewTestDesc().IP("1.2.3.4").AddGroup("prod").Favorite(true).Build()
+type TestDescBuilder struct {
+	ip   string
+	desc TestDesc
+	err  error
+}
+
+// NewTestDesc returns empty builder
+func NewTestDesc() *TestDescBuilder {
+	return &TestDescBuilder{}
+}
+
+// IP sets address (or hostname) the test is for
+func (b *TestDescBuilder) IP(ip string) *TestDescBuilder {
+	b.ip = normalizeIP(strings.TrimSpace(ip))
+	return b
+}
+
+// Description sets description
+func (b *TestDescBuilder) Description(description string) *TestDescBuilder {
+	b.desc.Description = description
+	return b
+}
+
+// AddSlave adds slave testing IP
+func (b *TestDescBuilder) AddSlave(slave string) *TestDescBuilder {
+	if "" == slave {
+		b.fail(ErrEmptySlaveName)
+		return b
+	}
+	b.desc.Slaves = appendUnique(b.desc.Slaves, slave)
+	return b
+}
+
+// RemoveSlave removes slave added before
+func (b *TestDescBuilder) RemoveSlave(slave string) *TestDescBuilder {
+	b.desc.Slaves = removeString(b.desc.Slaves, slave)
+	return b
+}
+
+// AddGroup adds IP to group (without trailing "->")
+func (b *TestDescBuilder) AddGroup(group string) *TestDescBuilder {
+	if "" == strings.TrimSuffix(group, groupSuffix) {
+		b.fail(ErrInvalidGroupName)
+		return b
+	}
+	b.desc.Groups = appendUnique(b.desc.Groups, strings.TrimSuffix(group, groupSuffix)+groupSuffix)
+	return b
+}
+
+// RemoveGroup removes group added before
+func (b *TestDescBuilder) RemoveGroup(group string) *TestDescBuilder {
+	b.desc.Groups = removeString(b.desc.Groups, strings.TrimSuffix(group, groupSuffix)+groupSuffix)
+	return b
+}
+
+// Favorite sets favorite flag
+func (b *TestDescBuilder) Favorite(favorite bool) *TestDescBuilder {
+	b.desc.Favorite = favorite
+	return b
+}
+
+// PacketCount sets count of ICMP packets per test, 0 means slave default
+func (b *TestDescBuilder) PacketCount(count int) *TestDescBuilder {
+	if count < 0 {
+		b.fail(errors.New("negative packet count"))
+		return b
+	}
+	b.desc.PacketCount = count
+	return b
+}
+
+// Build validates collected values and returns TestDesc, first error of chained calls is returned if any
+func (b *TestDescBuilder) Build() (TestDesc, error) {
+	if nil != b.err {
+		return TestDesc{}, b.err
+	}
+	if "" == b.ip {
+		return TestDesc{}, errors.New("test without ip")
+	}
+	return b.desc, nil
+}
+
+// BuildEntry is same as Build but returns IP together with TestDesc
+func (b *TestDescBuilder) BuildEntry() (IPEntry, error) {
+	desc, err := b.Build()
+	if nil != err {
+		return IPEntry{}, err
+	}
+	return IPEntry{IP: b.ip, TestDesc: desc}, nil
+}
+
+// fail remembers first error
+func (b *TestDescBuilder) fail(err error) {
+	if nil == b.err {
+		b.err = err
+	}
+}
+
+// removeString returns list without s
+func removeString(list []string, s string) []string {
+	result := list[:0:0]
+	for _, item := range list {
+		if item != s {
+			result = append(result, item)
+		}
+	}
+	return result
+}
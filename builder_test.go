@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTestDescBuilderEmptyIP(t *testing.T) {
+	for name, b := range map[string]*TestDescBuilder{
+		"no ip":         NewTestDesc().AddGroup("prod").Favorite(true),
+		"empty ip":      NewTestDesc().IP("").AddSlave("PRAGUE"),
+		"whitespace ip": NewTestDesc().IP("  \t").Description("x"),
+	} {
+		if _, err := b.Build(); nil == err {
+			t.Errorf("%s: expected error", name)
+		}
+		if _, err := b.BuildEntry(); nil == err {
+			t.Errorf("%s: expected error from BuildEntry", name)
+		}
+	}
+}
+
+func TestTestDescBuilderChain(t *testing.T) {
+	entry, err := NewTestDesc().
+		IP(" 2001:DB8::1 ").
+		Description("db").
+		AddSlave("PRAGUE").AddSlave("LONDON").AddSlave("PRAGUE").RemoveSlave("LONDON").
+		AddGroup("prod").AddGroup("db->").AddGroup("prod->").RemoveGroup("db").
+		Favorite(true).
+		PacketCount(5).
+		BuildEntry()
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := IPEntry{IP: "2001:db8::1", TestDesc: TestDesc{
+		Description: "db",
+		Slaves:      []string{"PRAGUE"},
+		Groups:      []string{"prod->"},
+		Favorite:    true,
+		PacketCount: 5,
+	}}
+	if !reflect.DeepEqual(expected, entry) {
+		t.Fatalf("expected %+v, got %+v", expected, entry)
+	}
+}
+
+func TestTestDescBuilderFirstError(t *testing.T) {
+	_, err := NewTestDesc().IP("1.2.3.4").AddSlave("").AddGroup("->").PacketCount(-1).Build()
+	if !errors.Is(err, ErrEmptySlaveName) {
+		t.Fatalf("expected first error ErrEmptySlaveName, got %v", err)
+	}
+
+	if _, err := NewTestDesc().IP("1.2.3.4").AddGroup("").Build(); !errors.Is(err, ErrInvalidGroupName) {
+		t.Fatalf("expected ErrInvalidGroupName, got %v", err)
+	}
+	if _, err := NewTestDesc().IP("1.2.3.4").PacketCount(-1).Build(); nil == err {
+		t.Fatal("expected error for negative packet count")
+	}
+}
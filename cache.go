@@ -0,0 +1,129 @@
+package api
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CachingOptions configures response cache of CachingClient
+type CachingOptions struct {
+	DefaultTTL     time.Duration            // TTL of endpoints not listed in PerEndpointTTL, 0 means no caching
+	PerEndpointTTL map[string]time.Duration // path prefix (like "/v1/config") -> TTL, longest matching prefix wins
+	MaxEntries     int                      // 0 means unlimited
+}
+
+// CachingClient is Client which caches successful GET responses by URL, so repeated GetConfigInfo, GetSlavesStatus...
+// calls are served from memory until TTL expires; every successful non-GET request clears whole cache
+type CachingClient struct {
+	*Client
+}
+
+// NewCachingClient creates client for master on url (see New) with response cache
+func NewCachingClient(url string, username string, password string, cacheOpts CachingOptions, opts ...Option) *CachingClient {
+	c := New(url, username, password, opts...)
+	c.cache = &responseCache{
+		options: cacheOpts,
+		entries: map[string]cacheEntry{},
+	}
+	return &CachingClient{Client: c}
+}
+
+// Invalidate removes cached responses with url (without master base url, like "/v1/config") matching pattern,
+// "*" in pattern matches any sequence of characters
+func (cc *CachingClient) Invalidate(urlPattern string) {
+	re := regexp.MustCompile("^" + strings.Replace(regexp.QuoteMeta(urlPattern), `\*`, ".*", -1) + "$")
+
+	cc.cache.mu.Lock()
+	defer cc.cache.mu.Unlock()
+
+	for key := range cc.cache.entries {
+		if re.MatchString(strings.TrimPrefix(key, cc.url)) {
+			delete(cc.cache.entries, key)
+		}
+	}
+}
+
+// InvalidateAll removes all cached responses
+func (cc *CachingClient) InvalidateAll() {
+	cc.cache.clear()
+}
+
+// responseCache keeps raw json responses by url
+type responseCache struct {
+	options CachingOptions
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	raw     []byte
+	expires time.Time
+}
+
+// get returns cached response of url if it's not expired yet
+func (rc *responseCache) get(url string) ([]byte, bool) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	entry, ok := rc.entries[url]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expires) {
+		delete(rc.entries, url)
+		return nil, false
+	}
+	return entry.raw, true
+}
+
+// set stores response of url (path relative to base) if its endpoint has non-zero TTL
+func (rc *responseCache) set(url string, path string, raw []byte) {
+	ttl := rc.ttl(path)
+	if ttl <= 0 {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	now := time.Now()
+	if rc.options.MaxEntries > 0 && len(rc.entries) >= rc.options.MaxEntries {
+		// drop expired entries and if it's not enough then one expiring first
+		oldest := ""
+		for key, entry := range rc.entries {
+			if now.After(entry.expires) {
+				delete(rc.entries, key)
+			} else if "" == oldest || entry.expires.Before(rc.entries[oldest].expires) {
+				oldest = key
+			}
+		}
+		if len(rc.entries) >= rc.options.MaxEntries {
+			delete(rc.entries, oldest)
+		}
+	}
+
+	rc.entries[url] = cacheEntry{raw: raw, expires: now.Add(ttl)}
+}
+
+// ttl returns TTL of endpoint path
+func (rc *responseCache) ttl(path string) time.Duration {
+	ttl := rc.options.DefaultTTL
+	longest := -1
+	for prefix, t := range rc.options.PerEndpointTTL {
+		if strings.HasPrefix(path, prefix) && len(prefix) > longest {
+			ttl = t
+			longest = len(prefix)
+		}
+	}
+	return ttl
+}
+
+// clear removes all entries
+func (rc *responseCache) clear() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	rc.entries = map[string]cacheEntry{}
+}
@@ -0,0 +1,58 @@
+package api
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingServer answers GET /v1/slaves with empty body on first request and with slaves afterwards
+type countingServer struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (s *countingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.calls++
+	first := 1 == s.calls
+	s.mu.Unlock()
+
+	if first {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	writeTestJSON(w, map[string]string{"PRAGUE": "1.1.1.1:3030"})
+}
+
+func (s *countingServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.calls
+}
+
+func TestCachingClientSkipsEmptyBody(t *testing.T) {
+	s := &countingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+	cc := NewCachingClient(c.url, "admin", "secret", CachingOptions{DefaultTTL: time.Minute})
+
+	if _, err := cc.GetSlavesIPs(); nil != err {
+		t.Fatal(err)
+	}
+
+	// empty body must not be cached, otherwise these calls fail to unmarshal cached nil
+	for i := 0; i < 2; i++ {
+		ips, err := cc.GetSlavesIPs()
+		if nil != err {
+			t.Fatal(err)
+		}
+		if "1.1.1.1" != ips["PRAGUE"] {
+			t.Errorf("unexpected slaves %v", ips)
+		}
+	}
+	if 2 != s.count() {
+		t.Errorf("expected empty response to be fetched again and then cached, got %d requests", s.count())
+	}
+}
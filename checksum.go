@@ -0,0 +1,78 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+)
+
+// ConfigChecksum returns hex SHA-256 of canonical JSON of config: map keys and group/slave lists are sorted,
+// Counter and LastIP are ignored as they differ between masters with same tests
+func ConfigChecksum(config ConfigInfo) string {
+	config.Counter = 0
+	config.Ping.LastIP = ""
+	config.Ping.IPs = canonicalTests(config.Ping.IPs)
+	config.HTTP.URLs = canonicalTests(config.HTTP.URLs)
+
+	// json.Marshal sorts map keys so output is deterministic
+	raw, _ := json.Marshal(config)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// canonicalTests returns copy of tests with sorted groups and slaves
+func canonicalTests(tests map[string]TestDesc) map[string]TestDesc {
+	result := make(map[string]TestDesc, len(tests))
+	for target, desc := range tests {
+		desc.Groups = append([]string{}, desc.Groups...)
+		desc.Slaves = append([]string{}, desc.Slaves...)
+		sort.Strings(desc.Groups)
+		sort.Strings(desc.Slaves)
+		result[target] = desc
+	}
+	return result
+}
+
+// GetConfigChecksum returns checksum of current configuration from server or ConfigChecksum of GetConfigInfo
+// if server doesn't provide it; use CompareConfigs to compare masters as server algorithm may differ
+func (c *Client) GetConfigChecksum() (string, error) {
+	return c.GetConfigChecksumContext(context.Background())
+}
+
+// GetConfigChecksumContext is same as GetConfigChecksum but requests are bound to ctx
+func (c *Client) GetConfigChecksumContext(ctx context.Context) (string, error) {
+	var result struct {
+		Checksum string `json:"checksum"`
+	}
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/config/checksum", nil, "", nil), &result)
+	if http.StatusNotFound != status && http.StatusMethodNotAllowed != status {
+		return result.Checksum, err
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return "", err
+	}
+	return ConfigChecksum(config), nil
+}
+
+// CompareConfigs checks if both masters have identical configuration using ConfigChecksum
+func CompareConfigs(a, b *Client) (bool, error) {
+	return CompareConfigsContext(context.Background(), a, b)
+}
+
+// CompareConfigsContext is same as CompareConfigs but requests are bound to ctx
+func CompareConfigsContext(ctx context.Context, a, b *Client) (bool, error) {
+	configA, err := a.GetConfigInfoContext(ctx)
+	if nil != err {
+		return false, err
+	}
+	configB, err := b.GetConfigInfoContext(ctx)
+	if nil != err {
+		return false, err
+	}
+	return ConfigChecksum(configA) == ConfigChecksum(configB), nil
+}
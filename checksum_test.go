@@ -0,0 +1,112 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+func fixtureConfig(t *testing.T) ConfigInfo {
+	var config ConfigInfo
+	if err := json.Unmarshal([]byte(configFixture), &config); nil != err {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestConfigChecksumAddDelete(t *testing.T) {
+	base := ConfigChecksum(fixtureConfig(t))
+
+	added := fixtureConfig(t)
+	added.Ping.IPs["9.9.9.9"] = TestDesc{Groups: []string{"DNS->"}, Slaves: []string{"PRAGUE"}}
+	withIP := ConfigChecksum(added)
+	if base == withIP {
+		t.Fatal("adding IP didn't change checksum")
+	}
+
+	deleted := fixtureConfig(t)
+	delete(deleted.Ping.IPs, "8.8.8.8")
+	if base == ConfigChecksum(deleted) || withIP == ConfigChecksum(deleted) {
+		t.Fatal("deleting IP didn't change checksum")
+	}
+
+	// adding and deleting same IP gives original checksum
+	delete(added.Ping.IPs, "9.9.9.9")
+	if base != ConfigChecksum(added) {
+		t.Fatal("checksum differs after add and delete of same IP")
+	}
+
+	urlAdded := fixtureConfig(t)
+	urlAdded.HTTP.URLs["https://example.org/"] = TestDesc{}
+	if base == ConfigChecksum(urlAdded) {
+		t.Fatal("adding URL didn't change checksum")
+	}
+}
+
+func TestConfigChecksumCanonical(t *testing.T) {
+	base := ConfigChecksum(fixtureConfig(t))
+
+	config := fixtureConfig(t)
+	config.Counter = 1000
+	config.Ping.LastIP = "1.1.1.1"
+	desc := config.Ping.IPs["1.1.1.1"]
+	desc.Groups = []string{"DNS->CLOUDFLARE->", "DNS->"}
+	desc.Slaves = []string{"LONDON", "PRAGUE"}
+	config.Ping.IPs["1.1.1.1"] = desc
+	if base != ConfigChecksum(config) {
+		t.Fatal("order of lists, counter or lastIP changed checksum")
+	}
+
+	// canonicalisation doesn't modify caller's config
+	if "DNS->CLOUDFLARE->" != config.Ping.IPs["1.1.1.1"].Groups[0] {
+		t.Fatal("caller's config was modified")
+	}
+
+	desc.Description = "changed"
+	config.Ping.IPs["1.1.1.1"] = desc
+	if base == ConfigChecksum(config) {
+		t.Fatal("description change didn't change checksum")
+	}
+}
+
+func TestGetConfigChecksum(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if "/v1/config/checksum" == r.URL.Path {
+			writeTestJSON(w, map[string]string{"checksum": "abc"})
+			return
+		}
+		t.Errorf("unexpected request %s", r.URL.Path)
+	})
+	if sum, err := c.GetConfigChecksum(); nil != err || "abc" != sum {
+		t.Fatalf("expected server checksum, got %q %v", sum, err)
+	}
+
+	// without checksum endpoint it's computed locally
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if "/v1/config" != r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(configFixture))
+	})
+	if sum, err := c.GetConfigChecksum(); nil != err || ConfigChecksum(fixtureConfig(t)) != sum {
+		t.Fatalf("expected local checksum, got %q %v", sum, err)
+	}
+}
+
+func TestCompareConfigs(t *testing.T) {
+	a := newConfigClient(t, configFixture)
+
+	same, err := CompareConfigs(a, newConfigClient(t, configFixture))
+	if nil != err || !same {
+		t.Fatalf("expected same configs, got %v %v", same, err)
+	}
+
+	config := fixtureConfig(t)
+	delete(config.Ping.IPs, "10.0.0.1")
+	raw, _ := json.Marshal(config)
+	same, err = CompareConfigs(a, newConfigClient(t, string(raw)))
+	if nil != err || same {
+		t.Fatalf("expected different configs, got %v %v", same, err)
+	}
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"crypto/tls"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Client represents connection to one cocopacket master instance
+type Client struct {
+	url                  string
+	authMu               sync.RWMutex // guards authHeader, tokenRefresher, headers, compactJSON, gzipEncoding, logger, naming and defaultGroups
+	authHeader           string
+	headers              http.Header
+	tokenRefresher       func() (string, error)
+	compactJSON          bool
+	gzipEncoding         bool
+	logger               Logger
+	naming               NamingConvention
+	defaultGroups        []string     // added to groups of new IPs by AddIP/AddIPs
+	transportMu          sync.RWMutex // guards httpClient, tlsConfig, transportOptions, transportWrapper and wrappedClient
+	httpClient           *http.Client
+	transportWrapper     func(http.RoundTripper) http.RoundTripper
+	wrappedClient        *http.Client // httpClient with transport wrapped by transportWrapper
+	tlsConfig            *tls.Config
+	transportOptions     *TransportOptions
+	optionsMu            sync.RWMutex // guards retry, concurrency, bulkBatchSize, overloadPollInterval and limiter
+	retry                *RetryConfig
+	concurrency          int
+	bulkBatchSize        int           // see WithBulkBatchSize
+	overloadPollInterval time.Duration // see WithOverloadPollInterval
+	limiter              RateLimiter
+	versionMu            sync.Mutex     // guards version
+	version              *APIVersion    // cached by GetAPIVersion
+	cache                *responseCache // set only by NewCachingClient
+	dryRunMu             sync.Mutex     // guards dryRun and dryRunLog
+	dryRun               bool
+	dryRunLog            []DryRunEntry
+}
+
+// Option changes behaviour of Client
+type Option func(*Client)
+
+// New creates Client for master instance on url with authorization parameters
+func New(url string, username string, password string, opts ...Option) *Client {
+	c := &Client{
+		url:        url,
+		httpClient: &http.Client{},
+	}
+	c.SetBasicAuth(username, password)
+	c.Configure(opts...)
+	return c
+}
+
+// Configure applies options to all future requests of client, it is safe to call while requests are running
+func (c *Client) Configure(opts ...Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithHTTPClient makes client use hc for all requests instead of default one
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.transportMu.Lock()
+		defer c.transportMu.Unlock()
+
+		c.httpClient = hc
+		c.wrapClient()
+	}
+}
+
+// client returns http client to use for next request
+func (c *Client) client() *http.Client {
+	c.transportMu.RLock()
+	defer c.transportMu.RUnlock()
+
+	if nil != c.wrappedClient {
+		return c.wrappedClient
+	}
+	return c.httpClient
+}
+
+// WithConcurrency limits count of parallel requests made by fan-out helpers like GroupLastStatsAll, 0 means unlimited
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		c.optionsMu.Lock()
+		c.concurrency = n
+		c.optionsMu.Unlock()
+	}
+}
+
+// concurrencyLimit returns count of parallel requests allowed for fan-out helpers
+func (c *Client) concurrencyLimit() int {
+	c.optionsMu.RLock()
+	defer c.optionsMu.RUnlock()
+	return c.concurrency
+}
+
+// WithBasicAuth sets username and password used for authorization
+func WithBasicAuth(username string, password string) Option {
+	return func(c *Client) {
+		c.SetBasicAuth(username, password)
+	}
+}
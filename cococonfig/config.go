@@ -0,0 +1,246 @@
+// Package cococonfig exports cocopacket configuration to JSON/YAML files, imports it back and initializes client from settings files
+package cococonfig
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+
+	api "github.com/kanocz/cocopacket-go-api"
+	"gopkg.in/yaml.v3"
+)
+
+// Export is content of configuration file
+type Export struct {
+	Config api.ConfigInfo    `json:"config"`
+	Slaves map[string]string `json:"slaves"` // slave name -> ip:port
+	Users  map[string]bool   `json:"users"`  // login -> admin, informational only as passwords can't be exported
+}
+
+// ImportReport lists entries changed by ImportConfig (or that would be changed in dry-run mode)
+type ImportReport struct {
+	DryRun  bool
+	Created []string
+	Updated []string
+	Deleted []string
+}
+
+// ExportConfig serialises current configuration, slaves and users of master to "json" or "yaml" format
+func ExportConfig(c *api.Client, format string) ([]byte, error) {
+	var export Export
+	var err error
+
+	export.Config, err = c.GetConfigInfo()
+	if nil != err {
+		return nil, err
+	}
+
+	export.Slaves, err = c.GetSlavesAddrs()
+	if nil != err {
+		return nil, err
+	}
+
+	export.Users, err = c.ListUsers()
+	if nil != err {
+		return nil, err
+	}
+
+	return encode(export, format)
+}
+
+// ImportConfig parses data exported by ExportConfig, compares it with live configuration and applies differences:
+// missing slaves are added, changed group settings replaced, IPs/URLs added, updated and deleted; users are not touched
+func ImportConfig(c *api.Client, data []byte, format string, dryRun bool) (ImportReport, error) {
+	report := ImportReport{DryRun: dryRun}
+
+	var export Export
+	if err := decode(data, format, &export); nil != err {
+		return report, err
+	}
+
+	live, err := c.GetConfigInfo()
+	if nil != err {
+		return report, err
+	}
+
+	liveSlaves, err := c.GetSlavesAddrs()
+	if nil != err {
+		return report, err
+	}
+
+	errs := api.MultiError{}
+
+	// slaves first as new IPs may use them
+	for _, slave := range sortedNames(export.Slaves) {
+		if _, ok := liveSlaves[slave]; ok {
+			continue
+		}
+		report.Created = append(report.Created, "slave "+slave)
+		if dryRun {
+			continue
+		}
+		if err := addSlave(c, slave, export.Slaves[slave]); nil != err {
+			errs["slave "+slave] = err
+		}
+	}
+
+	groups := make([]string, 0, len(export.Config.Groups))
+	for group := range export.Config.Groups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		config := export.Config.Groups[group]
+		current, ok := live.Groups[group]
+		if ok && reflect.DeepEqual(current, config) {
+			continue
+		}
+		if ok {
+			report.Updated = append(report.Updated, "group "+group)
+		} else {
+			report.Created = append(report.Created, "group "+group)
+		}
+		if dryRun {
+			continue
+		}
+		if err := c.SetGroupConfig(group, config); nil != err {
+			errs["group "+group] = err
+		}
+	}
+
+	diff := api.ConfigDiff(live, export.Config)
+
+	ips := make(map[string]api.TestDesc, len(diff.AddedIPs)+len(diff.ModifiedIPs))
+	for ip, desc := range diff.AddedIPs {
+		ips[ip] = desc
+		report.Created = append(report.Created, "ip "+ip)
+	}
+	for ip, change := range diff.ModifiedIPs {
+		ips[ip] = change.New
+		report.Updated = append(report.Updated, "ip "+ip)
+	}
+	deleteIPs := make([]string, 0, len(diff.RemovedIPs))
+	for ip := range diff.RemovedIPs {
+		deleteIPs = append(deleteIPs, ip)
+		report.Deleted = append(report.Deleted, "ip "+ip)
+	}
+
+	for u, desc := range diff.AddedURLs {
+		report.Created = append(report.Created, "url "+u)
+		if !dryRun {
+			if err := c.AddURLRaw(u, desc); nil != err {
+				errs["url "+u] = err
+			}
+		}
+	}
+	for u, change := range diff.ModifiedURLs {
+		report.Updated = append(report.Updated, "url "+u)
+		if !dryRun {
+			if err := c.AddURLRaw(u, change.New); nil != err {
+				errs["url "+u] = err
+			}
+		}
+	}
+	for u := range diff.RemovedURLs {
+		report.Deleted = append(report.Deleted, "url "+u)
+		if !dryRun {
+			if err := c.DeleteURL(u); nil != err {
+				errs["url "+u] = err
+			}
+		}
+	}
+
+	if !dryRun && 0 != len(ips) {
+		if err := c.AddIPsRaw(ips); nil != err {
+			errs["add ips"] = err
+		}
+	}
+	if !dryRun && 0 != len(deleteIPs) {
+		if err := c.DeleteIPs(deleteIPs); nil != err {
+			errs["delete ips"] = err
+		}
+	}
+
+	sort.Strings(report.Created)
+	sort.Strings(report.Updated)
+	sort.Strings(report.Deleted)
+
+	if 0 != len(errs) {
+		return report, errs
+	}
+	return report, nil
+}
+
+// addSlave adds slave from its ip:port address
+func addSlave(c *api.Client, name string, addr string) error {
+	host, portStr, err := net.SplitHostPort(addr)
+	if nil != err {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if nil == ip {
+		return errors.New("invalid slave ip " + host)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if nil != err {
+		return err
+	}
+
+	return c.AddSlave(ip, uint16(port), name, "")
+}
+
+// encode serialises value to json or yaml, yaml keys are same as json ones
+func encode(value interface{}, format string) ([]byte, error) {
+	raw, err := json.MarshalIndent(value, "", "  ")
+	if nil != err {
+		return nil, err
+	}
+
+	switch format {
+	case "json":
+		return raw, nil
+	case "yaml", "yml":
+		var generic interface{}
+		if err := json.Unmarshal(raw, &generic); nil != err {
+			return nil, err
+		}
+		return yaml.Marshal(generic)
+	}
+
+	return nil, errors.New("unsupported format " + format)
+}
+
+// decode parses json or yaml data to value
+func decode(data []byte, format string, value interface{}) error {
+	switch format {
+	case "json":
+		return json.Unmarshal(data, value)
+	case "yaml", "yml":
+		var generic interface{}
+		if err := yaml.Unmarshal(data, &generic); nil != err {
+			return err
+		}
+		raw, err := json.Marshal(generic)
+		if nil != err {
+			return err
+		}
+		return json.Unmarshal(raw, value)
+	}
+
+	return errors.New("unsupported format " + format)
+}
+
+// sortedNames returns sorted keys of map
+func sortedNames(m map[string]string) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,174 @@
+package cococonfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	api "github.com/kanocz/cocopacket-go-api"
+	"gopkg.in/yaml.v3"
+)
+
+// Settings is content of client settings file read by InitFromFile
+type Settings struct {
+	URL      string         `json:"url" yaml:"url" toml:"url"`
+	Username string         `json:"username" yaml:"username" toml:"username"`
+	Password string         `json:"password" yaml:"password" toml:"password"`
+	TLS      *TLSSettings   `json:"tls" yaml:"tls" toml:"tls"`
+	Retry    *RetrySettings `json:"retry" yaml:"retry" toml:"retry"`
+}
+
+// TLSSettings are optional TLS parameters of Settings
+type TLSSettings struct {
+	CAFile             string `json:"caFile" yaml:"caFile" toml:"caFile"`
+	CertFile           string `json:"certFile" yaml:"certFile" toml:"certFile"`
+	KeyFile            string `json:"keyFile" yaml:"keyFile" toml:"keyFile"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify" yaml:"insecureSkipVerify" toml:"insecureSkipVerify"`
+}
+
+// RetrySettings are optional retry parameters of Settings, see api.RetryConfig
+type RetrySettings struct {
+	MaxAttempts       int      `json:"maxAttempts" yaml:"maxAttempts" toml:"maxAttempts"`
+	InitialDelay      Duration `json:"initialDelay" yaml:"initialDelay" toml:"initialDelay"`
+	MaxDelay          Duration `json:"maxDelay" yaml:"maxDelay" toml:"maxDelay"`
+	Multiplier        float64  `json:"multiplier" yaml:"multiplier" toml:"multiplier"`
+	RetryableStatuses []int    `json:"retryableStatuses" yaml:"retryableStatuses" toml:"retryableStatuses"`
+}
+
+// Duration is time.Duration written as string like "500ms" or "2s" in settings files
+type Duration time.Duration
+
+// UnmarshalText parses duration string
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if nil != err {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// LoadSettings reads and validates settings file, format is detected by extension (.json, .yaml/.yml or .toml)
+func LoadSettings(path string) (Settings, error) {
+	var settings Settings
+
+	data, err := ioutil.ReadFile(path)
+	if nil != err {
+		return settings, err
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = decodeJSON(data, &settings)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &settings)
+	case ".toml":
+		err = decodeTOML(data, &settings)
+	default:
+		return settings, errors.New(path + ": unsupported settings file extension")
+	}
+	if nil != err {
+		return settings, errors.New(path + ": " + err.Error())
+	}
+
+	if "" == settings.URL {
+		return settings, errors.New(path + ": url is required")
+	}
+
+	return settings, nil
+}
+
+// InitFromFile initializes default api client from settings file (see LoadSettings)
+func InitFromFile(path string) error {
+	settings, err := LoadSettings(path)
+	if nil != err {
+		return err
+	}
+
+	api.Init(settings.URL, settings.Username, settings.Password)
+
+	if nil != settings.Retry {
+		api.Configure(api.WithRetry(settings.Retry.config()))
+	}
+
+	if nil != settings.TLS {
+		if "" != settings.TLS.CAFile {
+			if err := api.SetTLSRootCA(settings.TLS.CAFile); nil != err {
+				return err
+			}
+		}
+		if "" != settings.TLS.CertFile {
+			if err := api.SetTLSClientCert(settings.TLS.CertFile, settings.TLS.KeyFile); nil != err {
+				return err
+			}
+		}
+		api.SetTLSInsecureSkipVerify(settings.TLS.InsecureSkipVerify)
+	}
+
+	return nil
+}
+
+// config converts settings to api.RetryConfig
+func (r *RetrySettings) config() api.RetryConfig {
+	return api.RetryConfig{
+		MaxAttempts:       r.MaxAttempts,
+		InitialDelay:      time.Duration(r.InitialDelay),
+		MaxDelay:          time.Duration(r.MaxDelay),
+		Multiplier:        r.Multiplier,
+		RetryableStatuses: r.RetryableStatuses,
+	}
+}
+
+// decodeJSON is json.Unmarshal with line and column of error position
+func decodeJSON(data []byte, v interface{}) error {
+	err := json.Unmarshal(data, v)
+
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		offset = syntaxErr.Offset
+	case errors.As(err, &typeErr):
+		offset = typeErr.Offset
+	default:
+		return err
+	}
+
+	line, column := position(data, int(offset))
+	return errors.New("line " + strconv.Itoa(line) + ", column " + strconv.Itoa(column) + ": " + err.Error())
+}
+
+// decodeTOML is toml.Unmarshal with line and column of error position
+func decodeTOML(data []byte, v interface{}) error {
+	err := toml.Unmarshal(data, v)
+
+	var parseErr toml.ParseError
+	if !errors.As(err, &parseErr) {
+		return err
+	}
+
+	line, column := position(data, parseErr.Position.Start)
+	return errors.New("line " + strconv.Itoa(line) + ", column " + strconv.Itoa(column) + ": " + parseErr.Message)
+}
+
+// position converts byte offset in data to 1-based line and column
+func position(data []byte, offset int) (int, int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := offset - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
@@ -0,0 +1,57 @@
+package cococonfig
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadSettingsFormats(t *testing.T) {
+	expected := Settings{
+		URL:      "https://cocopacket.example.com",
+		Username: "admin",
+		Password: "secret",
+		TLS:      &TLSSettings{InsecureSkipVerify: true},
+		Retry: &RetrySettings{
+			MaxAttempts:       5,
+			InitialDelay:      Duration(500 * time.Millisecond),
+			MaxDelay:          Duration(10 * time.Second),
+			Multiplier:        1.5,
+			RetryableStatuses: []int{502, 503},
+		},
+	}
+
+	for _, name := range []string{"settings.json", "settings.yaml", "settings.toml"} {
+		settings, err := LoadSettings(filepath.Join("testdata", name))
+		if nil != err {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(expected, settings) {
+			t.Errorf("%s: expected %+v, got %+v", name, expected, settings)
+		}
+	}
+}
+
+func TestLoadSettingsErrors(t *testing.T) {
+	for name, parts := range map[string][]string{
+		"broken.json":  {"broken.json", "line 3"},
+		"broken.yaml":  {"broken.yaml", "line"},
+		"broken.toml":  {"broken.toml", "line 2"},
+		"nourl.toml":   {"nourl.toml", "url is required"},
+		"settings.ini": {"settings.ini", "unsupported"},
+		"missing.json": {"missing.json"},
+	} {
+		_, err := LoadSettings(filepath.Join("testdata", name))
+		if nil == err {
+			t.Errorf("%s: expected error", name)
+			continue
+		}
+		for _, part := range parts {
+			if !strings.Contains(err.Error(), part) {
+				t.Errorf("%s: error %q doesn't contain %q", name, err, part)
+			}
+		}
+	}
+}
@@ -0,0 +1,103 @@
+// Package cocoexport exposes cocopacket statistics as prometheus metrics
+//
+// Exporter implements prometheus.Collector, so it can be used standalone via ServeMetrics
+// or registered in custom registry along with other collectors:
+//
+//	reg := prometheus.NewRegistry()
+//	reg.MustRegister(cocoexport.NewExporter(client, []cocoexport.Target{{Group: "DNS", Slave: "PRAGUE"}}))
+//	http.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+package cocoexport
+
+import (
+	"net/http"
+
+	api "github.com/kanocz/cocopacket-go-api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Target is one group/slave combination to export
+type Target struct {
+	Group string
+	Slave string
+}
+
+// Exporter collects last-minute stats from cocopacket master on every scrape
+type Exporter struct {
+	client  *api.Client
+	targets []Target
+
+	rtt          *prometheus.Desc
+	loss         *prometheus.Desc
+	availability *prometheus.Desc
+	scrapeErrors *prometheus.Desc
+}
+
+var labels = []string{"group", "slave", "target"}
+
+// NewExporter creates exporter for targets using client
+func NewExporter(client *api.Client, targets []Target) *Exporter {
+	return &Exporter{
+		client:  client,
+		targets: targets,
+
+		rtt: prometheus.NewDesc("cocopacket_rtt_ms",
+			"Average round-trip time in ms for last minute", labels, nil),
+		loss: prometheus.NewDesc("cocopacket_loss_ratio",
+			"Part of lost tests for last minute in range 0..1", labels, nil),
+		availability: prometheus.NewDesc("cocopacket_availability",
+			"1 if target answered at least once during last minute, 0 otherwise", labels, nil),
+		scrapeErrors: prometheus.NewDesc("cocopacket_scrape_error",
+			"1 if stats for group/slave can't be loaded from master", []string{"group", "slave"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.rtt
+	ch <- e.loss
+	ch <- e.availability
+	ch <- e.scrapeErrors
+}
+
+// Collect implements prometheus.Collector
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	for _, t := range e.targets {
+		ips, urls, err := e.client.GroupLastStats(t.Group, t.Slave)
+		if nil != err {
+			ch <- prometheus.MustNewConstMetric(e.scrapeErrors, prometheus.GaugeValue, 1, t.Group, t.Slave)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(e.scrapeErrors, prometheus.GaugeValue, 0, t.Group, t.Slave)
+
+		for _, stats := range []map[string]*api.AvgChunk{ips, urls} {
+			for target, data := range stats {
+				if nil == data || 0 == data.Count {
+					continue
+				}
+
+				available := 0.0
+				if data.Loss < data.Count {
+					available = 1
+				}
+
+				ch <- prometheus.MustNewConstMetric(e.rtt, prometheus.GaugeValue, data.AvgLatency(), t.Group, t.Slave, target)
+				ch <- prometheus.MustNewConstMetric(e.loss, prometheus.GaugeValue, data.LossRatio(), t.Group, t.Slave, target)
+				ch <- prometheus.MustNewConstMetric(e.availability, prometheus.GaugeValue, available, t.Group, t.Slave, target)
+			}
+		}
+	}
+}
+
+// ServeMetrics registers exporter in own registry and serves it on addr/metrics, blocks like http.ListenAndServe
+func (e *Exporter) ServeMetrics(addr string) error {
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(e); nil != err {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	return http.ListenAndServe(addr, mux)
+}
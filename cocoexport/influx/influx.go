@@ -0,0 +1,211 @@
+// Package influx writes cocopacket last-minute statistics to InfluxDB using line protocol
+package influx
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/kanocz/cocopacket-go-api"
+)
+
+// Point is one InfluxDB point
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]interface{} // int, int64, float32, float64, bool or string values
+	Time        time.Time
+}
+
+// Client is abstraction over InfluxDB versions, see NewV1Client and NewV2Client
+type Client interface {
+	WritePoints(points []Point) error
+}
+
+// WriteGroupStatsToInflux loads last-minute stats of every group/slave pair and writes them to influx in single batch
+// with tags slave, group, target and protocol (ping or http)
+func WriteGroupStatsToInflux(c *api.Client, influxClient Client, groups []string, slaves []string, measurement string) error {
+	now := time.Now()
+	points := []Point{}
+
+	for _, group := range groups {
+		for _, slave := range slaves {
+			ips, urls, err := c.GroupLastStats(group, slave)
+			if nil != err {
+				return err
+			}
+
+			for protocol, stats := range map[string]map[string]*api.AvgChunk{"ping": ips, "http": urls} {
+				for target, data := range stats {
+					if nil == data {
+						continue
+					}
+					points = append(points, Point{
+						Measurement: measurement,
+						Tags: map[string]string{
+							"slave":    slave,
+							"group":    group,
+							"target":   target,
+							"protocol": protocol,
+						},
+						Fields: map[string]interface{}{
+							"count":      data.Count,
+							"loss":       data.Loss,
+							"latency":    data.AvgLatency(),
+							"loss_ratio": data.LossRatio(),
+						},
+						Time: now,
+					})
+				}
+			}
+		}
+	}
+
+	if 0 == len(points) {
+		return nil
+	}
+	return influxClient.WritePoints(points)
+}
+
+// httpClient writes line protocol to InfluxDB HTTP API
+type httpClient struct {
+	url           string
+	authorization string
+}
+
+// NewV1Client creates client for InfluxDB 1.x /write endpoint, username may be empty if auth is disabled
+func NewV1Client(serverURL string, database string, username string, password string) Client {
+	query := url.Values{
+		"db":        []string{database},
+		"precision": []string{"s"},
+	}
+	if "" != username {
+		query.Set("u", username)
+		query.Set("p", password)
+	}
+	return &httpClient{
+		url: strings.TrimSuffix(serverURL, "/") + "/write?" + query.Encode(),
+	}
+}
+
+// NewV2Client creates client for InfluxDB 2.x /api/v2/write endpoint
+func NewV2Client(serverURL string, org string, bucket string, token string) Client {
+	query := url.Values{
+		"org":       []string{org},
+		"bucket":    []string{bucket},
+		"precision": []string{"s"},
+	}
+	return &httpClient{
+		url:           strings.TrimSuffix(serverURL, "/") + "/api/v2/write?" + query.Encode(),
+		authorization: "Token " + token,
+	}
+}
+
+// WritePoints implements Client
+func (h *httpClient) WritePoints(points []Point) error {
+	var body bytes.Buffer
+	for _, p := range points {
+		body.WriteString(p.Line())
+		body.WriteByte('\n')
+	}
+
+	req, err := http.NewRequest("POST", h.url, &body)
+	if nil != err {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if "" != h.authorization {
+		req.Header.Set("Authorization", h.authorization)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if nil != err {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(resp.Body)
+		return errors.New(resp.Status + ": " + strings.TrimSpace(string(msg)))
+	}
+
+	return nil
+}
+
+var (
+	measurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	tagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+	stringEscaper      = strings.NewReplacer(`"`, `\"`, `\`, `\\`)
+)
+
+// Line returns point encoded in line protocol with second precision
+func (p Point) Line() string {
+	var b strings.Builder
+	b.WriteString(measurementEscaper.Replace(p.Measurement))
+
+	for _, key := range sortedKeys(p.Tags) {
+		if "" == p.Tags[key] {
+			continue
+		}
+		b.WriteByte(',')
+		b.WriteString(tagEscaper.Replace(key))
+		b.WriteByte('=')
+		b.WriteString(tagEscaper.Replace(p.Tags[key]))
+	}
+
+	fields := make([]string, 0, len(p.Fields))
+	for key := range p.Fields {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	for i, key := range fields {
+		if 0 == i {
+			b.WriteByte(' ')
+		} else {
+			b.WriteByte(',')
+		}
+		b.WriteString(tagEscaper.Replace(key))
+		b.WriteByte('=')
+		b.WriteString(fieldValue(p.Fields[key]))
+	}
+
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatInt(p.Time.Unix(), 10))
+
+	return b.String()
+}
+
+// fieldValue encodes field value in line protocol
+func fieldValue(v interface{}) string {
+	switch value := v.(type) {
+	case int:
+		return strconv.Itoa(value) + "i"
+	case int64:
+		return strconv.FormatInt(value, 10) + "i"
+	case float32:
+		return strconv.FormatFloat(float64(value), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case string:
+		return `"` + stringEscaper.Replace(value) + `"`
+	}
+	return `""`
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,250 @@
+// Package zabbix exposes cocopacket last-minute statistics to Zabbix: low-level discovery JSON and sender protocol items
+package zabbix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	api "github.com/kanocz/cocopacket-go-api"
+)
+
+// item keys used for sent values, {#SLAVE} and {#TARGET} macros of Discovery are parameters
+const (
+	KeyRTT  = "cocopacket.rtt"
+	KeyLoss = "cocopacket.loss"
+)
+
+// header starts every sender protocol packet
+var header = []byte("ZBXD\x01")
+
+// Item is one value delivered by zabbix sender protocol
+type Item struct {
+	Host  string `json:"host"`
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Clock int64  `json:"clock"`
+}
+
+// Option changes behaviour of SendToZabbix
+type Option func(*settings)
+
+type settings struct {
+	dryRun  io.Writer
+	timeout time.Duration
+}
+
+// DryRun makes SendToZabbix print payload to w instead of sending it
+func DryRun(w io.Writer) Option {
+	return func(s *settings) {
+		s.dryRun = w
+	}
+}
+
+// WithTimeout limits time of connection to zabbix server (10 seconds by default)
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *settings) {
+		s.timeout = timeout
+	}
+}
+
+// Discovery returns low-level discovery JSON with {#SLAVE}, {#TARGET} and {#GROUP} macros
+// for every monitored IP/URL of groups and every existing slave testing it
+func Discovery(c *api.Client, groups []string) ([]byte, error) {
+	config, err := c.GetConfigInfo()
+	if nil != err {
+		return nil, err
+	}
+
+	slaves, err := c.GetSlaveList()
+	if nil != err {
+		return nil, err
+	}
+	known := make(map[string]bool, len(slaves))
+	for _, slave := range slaves {
+		known[slave] = true
+	}
+
+	data := []map[string]string{}
+	for _, group := range groups {
+		name := strings.TrimSuffix(group, "->") + "->"
+		for _, tests := range []map[string]api.TestDesc{config.Ping.IPs, config.HTTP.URLs} {
+			for _, target := range sortedTargets(tests) {
+				if !contains(tests[target].Groups, name) {
+					continue
+				}
+				for _, slave := range tests[target].Slaves {
+					if !known[slave] {
+						continue
+					}
+					data = append(data, map[string]string{
+						"{#SLAVE}":  slave,
+						"{#TARGET}": target,
+						"{#GROUP}":  strings.TrimSuffix(group, "->"),
+					})
+				}
+			}
+		}
+	}
+
+	return json.Marshal(map[string]interface{}{"data": data})
+}
+
+// Items loads last-minute stats of every group/slave pair and converts them to items of host
+// with keys cocopacket.rtt[slave,target] (ms) and cocopacket.loss[slave,target] (percent)
+func Items(c *api.Client, hostname string, groups []string) ([]Item, error) {
+	slaves, err := c.GetSlaveList()
+	if nil != err {
+		return nil, err
+	}
+
+	clock := time.Now().Unix()
+	items := []Item{}
+	seen := map[string]bool{}
+
+	for _, group := range groups {
+		for _, slave := range slaves {
+			ips, urls, err := c.GroupLastStats(group, slave)
+			if nil != err {
+				return nil, err
+			}
+
+			for _, stats := range []map[string]*api.AvgChunk{ips, urls} {
+				for target, data := range stats {
+					// target may be in several groups
+					if nil == data || seen[slave+" "+target] {
+						continue
+					}
+					seen[slave+" "+target] = true
+
+					params := "[" + quote(slave) + "," + quote(target) + "]"
+					items = append(items,
+						Item{Host: hostname, Key: KeyRTT + params, Value: formatFloat(data.AvgLatency()), Clock: clock},
+						Item{Host: hostname, Key: KeyLoss + params, Value: formatFloat(data.LossRatio() * 100), Clock: clock},
+					)
+				}
+			}
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Key < items[j].Key })
+	return items, nil
+}
+
+// Payload encodes items as sender protocol packet
+func Payload(items []Item) ([]byte, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"request": "sender data",
+		"data":    items,
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	var packet bytes.Buffer
+	packet.Write(header)
+	binary.Write(&packet, binary.LittleEndian, uint64(len(body)))
+	packet.Write(body)
+	return packet.Bytes(), nil
+}
+
+// SendToZabbix sends last-minute stats of groups (see Items) to zabbix server (trapper port is usually 10051)
+func SendToZabbix(c *api.Client, zabbixServer string, zabbixPort int, hostname string, groups []string, opts ...Option) error {
+	s := settings{timeout: 10 * time.Second}
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	items, err := Items(c, hostname, groups)
+	if nil != err {
+		return err
+	}
+	if 0 == len(items) {
+		return nil
+	}
+
+	if nil != s.dryRun {
+		body, err := json.MarshalIndent(map[string]interface{}{
+			"request": "sender data",
+			"data":    items,
+		}, "", "  ")
+		if nil != err {
+			return err
+		}
+		_, err = fmt.Fprintln(s.dryRun, string(body))
+		return err
+	}
+
+	packet, err := Payload(items)
+	if nil != err {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(zabbixServer, strconv.Itoa(zabbixPort)), s.timeout)
+	if nil != err {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	if _, err := conn.Write(packet); nil != err {
+		return err
+	}
+
+	raw, err := ioutil.ReadAll(conn)
+	if nil != err {
+		return err
+	}
+	if len(raw) < len(header)+8 || !bytes.Equal(raw[:len(header)], header) {
+		return errors.New("invalid response from zabbix server")
+	}
+
+	var response struct {
+		Response string `json:"response"`
+		Info     string `json:"info"`
+	}
+	if err := json.Unmarshal(raw[len(header)+8:], &response); nil != err {
+		return err
+	}
+	if "success" != response.Response {
+		return errors.New("zabbix server: " + response.Response + " " + response.Info)
+	}
+
+	return nil
+}
+
+// quote escapes item key parameter
+func quote(s string) string {
+	return `"` + strings.Replace(s, `"`, `\"`, -1) + `"`
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 3, 64)
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedTargets(tests map[string]api.TestDesc) []string {
+	keys := make([]string, 0, len(tests))
+	for key := range tests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,55 @@
+// Package cocogeo resolves geographic location of cocopacket slaves using local MaxMind GeoLite2 database
+package cocogeo
+
+import (
+	"net"
+	"strings"
+
+	api "github.com/kanocz/cocopacket-go-api"
+	"github.com/oschwald/geoip2-golang"
+)
+
+// LocalGeoEnrich looks up source IPs of slaves (Source, then Source6, then Host) in GeoLite2/GeoIP2 City database at dbPath,
+// slaves without usable IP or not found in database are missing in result
+func LocalGeoEnrich(slaves map[string]api.SlaveStatus, dbPath string) (map[string]api.SlaveGeoInfo, error) {
+	db, err := geoip2.Open(dbPath)
+	if nil != err {
+		return nil, err
+	}
+	defer db.Close()
+
+	result := make(map[string]api.SlaveGeoInfo, len(slaves))
+	for slave, status := range slaves {
+		ip := sourceIP(status)
+		if nil == ip {
+			continue
+		}
+
+		record, err := db.City(ip)
+		if nil != err || nil == record {
+			continue
+		}
+
+		result[slave] = api.SlaveGeoInfo{
+			Country:   record.Country.IsoCode,
+			City:      record.City.Names["en"],
+			Latitude:  record.Location.Latitude,
+			Longitude: record.Location.Longitude,
+		}
+	}
+
+	return result, nil
+}
+
+// sourceIP returns first parseable IP of slave
+func sourceIP(status api.SlaveStatus) net.IP {
+	for _, addr := range []string{status.Source, status.Source6, status.Host} {
+		if host, _, err := net.SplitHostPort(addr); nil == err {
+			addr = host
+		}
+		if ip := net.ParseIP(strings.Trim(addr, "[]")); nil != ip {
+			return ip
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package cocogeo
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	api "github.com/kanocz/cocopacket-go-api"
+)
+
+//go:generate go run ./testdata/gen
+
+// testDB is generated by testdata/gen and contains only 81.2.69.0/24, 89.160.20.0/24 and 216.160.83.56/29
+var testDB = filepath.Join("testdata", "GeoLite2-City-Test.mmdb")
+
+func TestLocalGeoEnrich(t *testing.T) {
+	slaves := map[string]api.SlaveStatus{
+		"LONDON":    {Source: "81.2.69.142", Host: "10.0.0.1"},
+		"LINKOPING": {Source: "", Source6: "", Host: "89.160.20.1:3030"},
+		"MILTON":    {Source: "216.160.83.58:0"},
+		"UNKNOWN":   {Source: "1.1.1.1"},
+		"NOADDR":    {Host: "slave.example.com"},
+		"IPV6ONLY":  {Source6: "[2001:db8::1]:3030"},
+	}
+
+	geo, err := LocalGeoEnrich(slaves, testDB)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := map[string]api.SlaveGeoInfo{
+		"LONDON":    {Country: "GB", City: "London", Latitude: 51.5142, Longitude: -0.0931},
+		"LINKOPING": {Country: "SE", City: "Linköping", Latitude: 58.4167, Longitude: 15.6167},
+		"MILTON":    {Country: "US", City: "Milton", Latitude: 47.2513, Longitude: -122.3149},
+	}
+	if !reflect.DeepEqual(expected, geo) {
+		t.Fatalf("expected %v, got %v", expected, geo)
+	}
+}
+
+func TestLocalGeoEnrichMissingDB(t *testing.T) {
+	if _, err := LocalGeoEnrich(nil, filepath.Join("testdata", "missing.mmdb")); nil == err {
+		t.Fatal("expected error")
+	}
+}
+
+func TestSourceIP(t *testing.T) {
+	for _, tc := range []struct {
+		status   api.SlaveStatus
+		expected string
+	}{
+		{api.SlaveStatus{Source: "192.0.2.1", Source6: "2001:db8::1", Host: "10.0.0.1"}, "192.0.2.1"},
+		{api.SlaveStatus{Source: "192.0.2.1:3030"}, "192.0.2.1"},
+		{api.SlaveStatus{Source6: "[2001:db8::1]:3030", Host: "10.0.0.1"}, "2001:db8::1"},
+		{api.SlaveStatus{Source6: "[2001:db8::1]"}, "2001:db8::1"},
+		{api.SlaveStatus{Source: "invalid", Host: "10.0.0.1"}, "10.0.0.1"},
+		{api.SlaveStatus{Host: "slave.example.com:3030"}, "<nil>"},
+	} {
+		if ip := sourceIP(tc.status); tc.expected != ip.String() {
+			t.Errorf("%+v: expected %s, got %s", tc.status, tc.expected, ip)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Command gen writes testdata/GeoLite2-City-Test.mmdb, minimal IPv4 City database used by cocogeo tests;
+// run it from cocogeo directory (go generate does so)
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log"
+	"math"
+	"net"
+	"os"
+	"sort"
+)
+
+// network is one database entry
+type network struct {
+	cidr      string
+	country   string
+	city      string
+	latitude  float64
+	longitude float64
+}
+
+var networks = []network{
+	{"81.2.69.0/24", "GB", "London", 51.5142, -0.0931},
+	{"89.160.20.0/24", "SE", "Linköping", 58.4167, 15.6167},
+	{"216.160.83.56/29", "US", "Milton", 47.2513, -122.3149},
+}
+
+// node is search tree node, record < 0 means empty, data records are encoded later
+type node struct {
+	records [2]int
+	data    [2]int
+}
+
+func main() {
+	data := &bytes.Buffer{}
+	nodes := []node{{records: [2]int{-1, -1}, data: [2]int{-1, -1}}}
+
+	for _, n := range networks {
+		_, ipnet, err := net.ParseCIDR(n.cidr)
+		if nil != err {
+			log.Fatal(err)
+		}
+		offset := data.Len()
+		encodeMap(data, []interface{}{
+			"city", []interface{}{"names", []interface{}{"en", n.city}},
+			"country", []interface{}{"iso_code", n.country, "names", []interface{}{"en", n.country}},
+			"location", []interface{}{"latitude", n.latitude, "longitude", n.longitude},
+		})
+
+		ones, _ := ipnet.Mask.Size()
+		ip := ipnet.IP.To4()
+		current := 0
+		for i := 0; i < ones; i++ {
+			bit := int(ip[i/8]>>(7-uint(i%8))) & 1
+			if ones-1 == i {
+				nodes[current].data[bit] = offset
+				break
+			}
+			if nodes[current].records[bit] < 0 {
+				nodes = append(nodes, node{records: [2]int{-1, -1}, data: [2]int{-1, -1}})
+				nodes[current].records[bit] = len(nodes) - 1
+			}
+			current = nodes[current].records[bit]
+		}
+	}
+
+	out := &bytes.Buffer{}
+	count := len(nodes)
+	for _, n := range nodes {
+		for bit := 0; bit < 2; bit++ {
+			value := count // not found
+			switch {
+			case n.data[bit] >= 0:
+				value = count + 16 + n.data[bit]
+			case n.records[bit] >= 0:
+				value = n.records[bit]
+			}
+			out.Write([]byte{byte(value >> 16), byte(value >> 8), byte(value)})
+		}
+	}
+	out.Write(make([]byte, 16))
+	out.Write(data.Bytes())
+	out.WriteString("\xab\xcd\xefMaxMind.com")
+	encodeMap(out, []interface{}{
+		"binary_format_major_version", uint16(2),
+		"binary_format_minor_version", uint16(0),
+		"build_epoch", uint64(1700000000),
+		"database_type", "GeoLite2-City",
+		"description", []interface{}{"en", "cocogeo test database"},
+		"ip_version", uint16(4),
+		"languages", []string{"en"},
+		"node_count", uint32(count),
+		"record_size", uint16(24),
+	})
+
+	if err := os.WriteFile("testdata/GeoLite2-City-Test.mmdb", out.Bytes(), 0644); nil != err {
+		log.Fatal(err)
+	}
+}
+
+// control writes control byte(s) of type t with size
+func control(w *bytes.Buffer, t int, size int) {
+	first := 0
+	if t <= 7 {
+		first = t << 5
+	}
+	switch {
+	case size < 29:
+		w.WriteByte(byte(first | size))
+		if t > 7 {
+			w.WriteByte(byte(t - 7))
+		}
+	case size < 285:
+		w.WriteByte(byte(first | 29))
+		if t > 7 {
+			w.WriteByte(byte(t - 7))
+		}
+		w.WriteByte(byte(size - 29))
+	default:
+		log.Fatal("value too large")
+	}
+}
+
+// encodeMap writes map given as key, value list
+func encodeMap(w *bytes.Buffer, kv []interface{}) {
+	control(w, 7, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		encode(w, kv[i])
+		encode(w, kv[i+1])
+	}
+}
+
+func encode(w *bytes.Buffer, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		control(w, 2, len(v))
+		w.WriteString(v)
+	case float64:
+		control(w, 3, 8)
+		binary.Write(w, binary.BigEndian, math.Float64bits(v))
+	case uint16:
+		control(w, 5, 2)
+		binary.Write(w, binary.BigEndian, v)
+	case uint32:
+		control(w, 6, 4)
+		binary.Write(w, binary.BigEndian, v)
+	case uint64:
+		control(w, 9, 8)
+		binary.Write(w, binary.BigEndian, v)
+	case []string:
+		sort.Strings(v)
+		control(w, 11, len(v))
+		for _, s := range v {
+			encode(w, s)
+		}
+	case []interface{}:
+		encodeMap(w, v)
+	default:
+		log.Fatalf("unsupported type %T", value)
+	}
+}
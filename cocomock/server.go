@@ -0,0 +1,544 @@
+// Package cocomock provides fake cocopacket master for testing tools built on top of api package
+//
+//	mock := cocomock.NewServer()
+//	defer mock.Close()
+//	mock.SetSlaves(map[string]string{"PRAGUE": "1.1.1.1:3030"})
+//	client := api.New(mock.URL(), "admin", "admin")
+package cocomock
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	api "github.com/kanocz/cocopacket-go-api"
+)
+
+// MockServer is in-memory implementation of cocopacket master API
+type MockServer struct {
+	server *httptest.Server
+
+	mu          sync.Mutex
+	config      api.ConfigInfo
+	slaves      map[string]string
+	status      map[string]api.SlaveStatus
+	users       map[string]bool
+	groupStats  map[string]api.GroupStatsData
+	lastStats   map[string]map[string]lastStats // group -> slave -> stats
+	errors      map[string]int                  // "METHOD endpoint" -> status code
+	requestLog  []string
+	maintenance map[string]api.MaintenanceStatus
+	version     string
+}
+
+type lastStats struct {
+	Ping map[string]*api.AvgChunk `json:"Ping"`
+	HTTP map[string]*api.AvgChunk `json:"HTTP"`
+}
+
+// NewServer starts mock server, call Close when done
+func NewServer() *MockServer {
+	m := &MockServer{
+		slaves:      map[string]string{},
+		status:      map[string]api.SlaveStatus{},
+		users:       map[string]bool{},
+		groupStats:  map[string]api.GroupStatsData{},
+		lastStats:   map[string]map[string]lastStats{},
+		errors:      map[string]int{},
+		maintenance: map[string]api.MaintenanceStatus{},
+		version:     "1.0.4-6",
+	}
+	m.config.Ping.IPs = map[string]api.TestDesc{}
+	m.config.HTTP.URLs = map[string]api.TestDesc{}
+	m.config.Groups = map[string]api.GroupConfig{}
+
+	m.server = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	return m
+}
+
+// URL returns base url of mock server usable for api.New / api.Init
+func (m *MockServer) URL() string {
+	return m.server.URL
+}
+
+// Client returns api client connected to mock server
+func (m *MockServer) Client(opts ...api.Option) *api.Client {
+	return api.New(m.server.URL, "", "", opts...)
+}
+
+// Close shuts mock server down
+func (m *MockServer) Close() {
+	m.server.Close()
+}
+
+// SetSlaves replaces list of slaves (name -> ip:port)
+func (m *MockServer) SetSlaves(slaves map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.slaves = copyMap(slaves)
+}
+
+// SetSlavesStatus replaces result of /v1/status/slaves
+func (m *MockServer) SetSlavesStatus(status map[string]api.SlaveStatus) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.status = map[string]api.SlaveStatus{}
+	for slave, s := range status {
+		m.status[slave] = s
+	}
+}
+
+// SetIPs replaces all monitored IPs
+func (m *MockServer) SetIPs(ips map[string]api.TestDesc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.config.Ping.IPs = map[string]api.TestDesc{}
+	for ip, desc := range ips {
+		m.config.Ping.IPs[ip] = desc
+	}
+}
+
+// SetURLs replaces all monitored URLs
+func (m *MockServer) SetURLs(urls map[string]api.TestDesc) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.config.HTTP.URLs = map[string]api.TestDesc{}
+	for u, desc := range urls {
+		m.config.HTTP.URLs[u] = desc
+	}
+}
+
+// SetGroups replaces all groups settings (group names with trailing "->")
+func (m *MockServer) SetGroups(groups map[string]api.GroupConfig) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.config.Groups = map[string]api.GroupConfig{}
+	for group, config := range groups {
+		m.config.Groups[group] = config
+	}
+}
+
+// SetUsers replaces list of users (login -> admin)
+func (m *MockServer) SetUsers(users map[string]bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.users = map[string]bool{}
+	for login, admin := range users {
+		m.users[login] = admin
+	}
+}
+
+// SetGroupStats sets result of GroupStats for group (without trailing "->")
+func (m *MockServer) SetGroupStats(group string, data api.GroupStatsData) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.groupStats[group+"->"] = data
+}
+
+// SetGroupLastStats sets result of GroupLastStats for group (without trailing "->") and slave
+func (m *MockServer) SetGroupLastStats(group string, slave string, ips map[string]*api.AvgChunk, urls map[string]*api.AvgChunk) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if nil == m.lastStats[group+"->"] {
+		m.lastStats[group+"->"] = map[string]lastStats{}
+	}
+	m.lastStats[group+"->"][slave] = lastStats{Ping: ips, HTTP: urls}
+}
+
+// SetVersion sets version reported by /v1/status/version (like "1.0.4-6")
+func (m *MockServer) SetVersion(version string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.version = version
+}
+
+// SimulateError makes all requests with method to paths starting with endpoint (like "/v1/slaves") fail with code,
+// code 0 removes simulated error
+func (m *MockServer) SimulateError(endpoint string, method string, code int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if 0 == code {
+		delete(m.errors, method+" "+endpoint)
+		return
+	}
+	m.errors[method+" "+endpoint] = code
+}
+
+// Config returns copy of current configuration (useful to check results of tested code)
+func (m *MockServer) Config() api.ConfigInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	raw, _ := json.Marshal(m.config)
+	var config api.ConfigInfo
+	json.Unmarshal(raw, &config)
+	return config
+}
+
+// Requests returns list of "METHOD path" of all requests received so far
+func (m *MockServer) Requests() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]string{}, m.requestLog...)
+}
+
+func (m *MockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requestLog = append(m.requestLog, r.Method+" "+r.URL.Path)
+
+	for key, code := range m.errors {
+		parts := strings.SplitN(key, " ", 2)
+		if parts[0] == r.Method && strings.HasPrefix(r.URL.Path, parts[1]) {
+			w.WriteHeader(code)
+			return
+		}
+	}
+
+	path := r.URL.Path
+	switch {
+	case "/v1/config" == path && "GET" == r.Method:
+		writeJSON(w, m.config)
+
+	case strings.HasPrefix(path, "/v1/config/ping/"):
+		m.handleTest(w, r, m.config.Ping.IPs, strings.TrimPrefix(path, "/v1/config/ping/"))
+
+	case strings.HasPrefix(path, "/v1/config/http/"):
+		m.handleTest(w, r, m.config.HTTP.URLs, strings.TrimPrefix(path, "/v1/config/http/"))
+
+	case "/v1/mconfig/add" == path && "PUT" == r.Method:
+		var payload struct {
+			IPs  map[string]api.TestDesc `json:"ips"`
+			URLs map[string]api.TestDesc `json:"urls"`
+		}
+		if !readJSON(w, r, &payload) {
+			return
+		}
+		for ip, desc := range payload.IPs {
+			m.config.Ping.IPs[ip] = desc
+		}
+		for u, desc := range payload.URLs {
+			m.config.HTTP.URLs[u] = desc
+		}
+		writeOK(w)
+
+	case "/v1/mconfig/delete" == path && "PUT" == r.Method:
+		var payload struct {
+			IPs []string `json:"ips"`
+		}
+		if !readJSON(w, r, &payload) {
+			return
+		}
+		for _, ip := range payload.IPs {
+			delete(m.config.Ping.IPs, ip)
+		}
+		writeOK(w)
+
+	case "/v1/mconfig/slaves" == path && "PUT" == r.Method:
+		var payload struct {
+			IPs    []string        `json:"ips"`
+			Slaves map[string]bool `json:"slaves"`
+		}
+		if !readJSON(w, r, &payload) {
+			return
+		}
+		for _, ip := range payload.IPs {
+			if desc, ok := m.config.Ping.IPs[ip]; ok {
+				desc.Slaves = applySlaves(desc.Slaves, payload.Slaves)
+				m.config.Ping.IPs[ip] = desc
+			}
+		}
+		writeOK(w)
+
+	case strings.HasPrefix(path, "/v1/groupslaves/") && "PUT" == r.Method:
+		var payload struct {
+			Slaves    map[string]bool `json:"slaves"`
+			Recursive bool            `json:"recursive"`
+		}
+		if !readJSON(w, r, &payload) {
+			return
+		}
+		group := groupFromPath(r, "/v1/groupslaves/")
+		for _, tests := range []map[string]api.TestDesc{m.config.Ping.IPs, m.config.HTTP.URLs} {
+			for target, desc := range tests {
+				if hasGroup(desc.Groups, group, payload.Recursive) {
+					desc.Slaves = applySlaves(desc.Slaves, payload.Slaves)
+					tests[target] = desc
+				}
+			}
+		}
+		writeOK(w)
+
+	case strings.HasPrefix(path, "/v1/group/"):
+		group := groupFromPath(r, "/v1/group/")
+		switch r.Method {
+		case "GET":
+			writeJSON(w, m.config.Groups[group])
+		case "POST":
+			var config api.GroupConfig
+			if !readJSON(w, r, &config) {
+				return
+			}
+			m.config.Groups[group] = config
+			writeOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	case "/v1/slaves" == path:
+		m.handleSlaves(w, r)
+
+	case strings.HasPrefix(path, "/v1/slaves/") && strings.HasSuffix(path, "/maintenance"):
+		slave := strings.TrimSuffix(strings.TrimPrefix(path, "/v1/slaves/"), "/maintenance")
+		switch r.Method {
+		case "GET":
+			writeJSON(w, m.maintenance[slave])
+		case "PUT":
+			var status api.MaintenanceStatus
+			if !readJSON(w, r, &status) {
+				return
+			}
+			m.maintenance[slave] = status
+			writeOK(w)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	case "/v1/status/slaves" == path && "GET" == r.Method:
+		writeJSON(w, m.status)
+
+	case "/v1/status/version" == path && "GET" == r.Method:
+		writeJSON(w, map[string]string{"version": m.version})
+
+	case "/v1/users" == path:
+		m.handleUsers(w, r)
+
+	case strings.HasPrefix(path, "/v1/catstats/") && "GET" == r.Method:
+		writeJSON(w, m.groupStats[groupFromPath(r, "/v1/catstats/")])
+
+	case strings.HasPrefix(path, "/v1/minute/") && "GET" == r.Method:
+		stats, ok := m.lastStats[groupFromPath(r, "/v1/minute/")][r.URL.Query().Get("slave")]
+		if !ok {
+			writeJSON(w, map[string]string{"result": "error", "error": "no data"})
+			return
+		}
+		writeJSON(w, stats)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// handleTest serves one IP/URL test config
+func (m *MockServer) handleTest(w http.ResponseWriter, r *http.Request, tests map[string]api.TestDesc, target string) {
+	switch r.Method {
+	case "GET":
+		desc, ok := tests[target]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeJSON(w, api.IPDetail{TestDesc: desc})
+	case "PUT":
+		var desc api.TestDesc
+		if !readJSON(w, r, &desc) {
+			return
+		}
+		tests[target] = desc
+		writeOK(w)
+	case "DELETE":
+		if _, ok := tests[target]; !ok {
+			writeJSON(w, map[string]string{"result": "error", "error": "not found"})
+			return
+		}
+		delete(tests, target)
+		writeOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// handleSlaves serves slaves list changes, like master it drops IPs/URLs left without slaves when slave is deleted
+func (m *MockServer) handleSlaves(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		writeJSON(w, m.slaves)
+	case "POST":
+		var payload struct {
+			IP     string `json:"ip"`
+			Port   int    `json:"port"`
+			Name   string `json:"name"`
+			Copy   string `json:"copy"`
+			Update bool   `json:"update"`
+		}
+		if !readJSON(w, r, &payload) {
+			return
+		}
+		if _, ok := m.slaves[payload.Name]; payload.Update && !ok {
+			writeJSON(w, map[string]string{"result": "error", "error": "unknown slave"})
+			return
+		}
+		m.slaves[payload.Name] = payload.IP + ":" + strconv.Itoa(payload.Port)
+		writeOK(w)
+	case "PUT":
+		var payload struct {
+			OldName string `json:"oldName"`
+			NewName string `json:"newName"`
+		}
+		if !readJSON(w, r, &payload) {
+			return
+		}
+		addr, ok := m.slaves[payload.OldName]
+		if !ok {
+			writeJSON(w, map[string]string{"result": "error", "error": "unknown slave"})
+			return
+		}
+		if _, exists := m.slaves[payload.NewName]; exists {
+			writeJSON(w, map[string]string{"result": "error", "error": "slave already exists"})
+			return
+		}
+		delete(m.slaves, payload.OldName)
+		m.slaves[payload.NewName] = addr
+		m.replaceSlave(payload.OldName, payload.NewName)
+		writeOK(w)
+	case "DELETE":
+		slave := r.URL.Query().Get("slave")
+		if _, ok := m.slaves[slave]; !ok {
+			writeJSON(w, map[string]string{"result": "error", "error": "unknown slave"})
+			return
+		}
+		delete(m.slaves, slave)
+		m.replaceSlave(slave, "")
+		writeOK(w)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// replaceSlave renames slave in all IPs/URLs, empty newName removes it and drops IPs/URLs left without slaves
+func (m *MockServer) replaceSlave(oldName string, newName string) {
+	for _, tests := range []map[string]api.TestDesc{m.config.Ping.IPs, m.config.HTTP.URLs} {
+		for target, desc := range tests {
+			if !hasSlave(desc.Slaves, oldName) {
+				continue
+			}
+			desc.Slaves = applySlaves(desc.Slaves, map[string]bool{oldName: false, newName: "" != newName})
+			if 0 == len(desc.Slaves) {
+				delete(tests, target)
+				continue
+			}
+			tests[target] = desc
+		}
+	}
+}
+
+// handleUsers serves users list changes
+func (m *MockServer) handleUsers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+	case "PUT":
+		if err := r.ParseForm(); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		m.users[r.PostForm.Get("login")] = "admin" == r.PostForm.Get("type")
+	case "DELETE":
+		delete(m.users, r.URL.Query().Get("login"))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	writeJSON(w, m.users)
+}
+
+// groupFromPath extracts group name (with trailing "->") escaped by url.QueryEscape from request path
+func groupFromPath(r *http.Request, prefix string) string {
+	group, err := url.QueryUnescape(strings.TrimPrefix(r.URL.EscapedPath(), prefix))
+	if nil != err {
+		return strings.TrimPrefix(r.URL.Path, prefix)
+	}
+	return group
+}
+
+// hasGroup checks if groups contains group (or its subgroup in case of recursive)
+func hasGroup(groups []string, group string, recursive bool) bool {
+	for _, g := range groups {
+		if g == group || (recursive && strings.HasPrefix(g, group)) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSlave checks if slaves contains slave
+func hasSlave(slaves []string, slave string) bool {
+	for _, s := range slaves {
+		if s == slave {
+			return true
+		}
+	}
+	return false
+}
+
+// applySlaves adds/removes slaves like server does
+func applySlaves(current []string, changes map[string]bool) []string {
+	set := map[string]bool{}
+	for _, slave := range current {
+		set[slave] = true
+	}
+	for slave, add := range changes {
+		set[slave] = add
+	}
+
+	result := []string{}
+	for slave, ok := range set {
+		if ok {
+			result = append(result, slave)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, object interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(object); nil != err {
+		w.WriteHeader(http.StatusBadRequest)
+		writeJSON(w, map[string]string{"result": "error", "error": err.Error()})
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, object interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(object)
+}
+
+func writeOK(w http.ResponseWriter) {
+	writeJSON(w, map[string]string{"result": "OK"})
+}
+
+func copyMap(m map[string]string) map[string]string {
+	result := make(map[string]string, len(m))
+	for k, v := range m {
+		result[k] = v
+	}
+	return result
+}
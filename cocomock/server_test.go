@@ -0,0 +1,159 @@
+package cocomock
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+
+	api "github.com/kanocz/cocopacket-go-api"
+)
+
+// newSlavesMock returns mock with slaves PRAGUE and BERLIN, 10.0.0.1 measured only by PRAGUE and 10.0.0.2 by both
+func newSlavesMock(t *testing.T) *MockServer {
+	t.Helper()
+
+	m := NewServer()
+	t.Cleanup(m.Close)
+	m.SetSlaves(map[string]string{"PRAGUE": "1.1.1.1:3030", "BERLIN": "2.2.2.2:3030"})
+	m.SetIPs(map[string]api.TestDesc{
+		"10.0.0.1": {Slaves: []string{"PRAGUE"}},
+		"10.0.0.2": {Slaves: []string{"BERLIN", "PRAGUE"}},
+	})
+	return m
+}
+
+func TestDeleteSlaveDropsOrphanedIPs(t *testing.T) {
+	m := newSlavesMock(t)
+
+	if err := m.Client().DeleteSlave("PRAGUE"); nil != err {
+		t.Fatal(err)
+	}
+
+	ips := m.Config().Ping.IPs
+	if _, ok := ips["10.0.0.1"]; ok {
+		t.Error("expected 10.0.0.1 without slaves to be dropped")
+	}
+	if !reflect.DeepEqual([]string{"BERLIN"}, ips["10.0.0.2"].Slaves) {
+		t.Errorf("unexpected slaves of 10.0.0.2: %v", ips["10.0.0.2"].Slaves)
+	}
+}
+
+// slavePatchCase is partial patch of slave PRAGUE with expected name and address after update
+type slavePatchCase struct {
+	name  string
+	patch api.SlavePatch
+	slave string
+	addr  string
+}
+
+// slavePatches returns all combinations of partial patches
+func slavePatches() []slavePatchCase {
+	newName := "VIENNA"
+	newIP := net.ParseIP("3.3.3.3")
+	newPort := uint16(4040)
+
+	result := []slavePatchCase{}
+	for i := 0; i < 8; i++ {
+		tc := slavePatchCase{name: "patch", slave: "PRAGUE"}
+		ip, port := "1.1.1.1", "3030"
+		if 0 != i&1 {
+			tc.patch.NewName = &newName
+			tc.name += " name"
+			tc.slave = newName
+		}
+		if 0 != i&2 {
+			tc.patch.NewIP = &newIP
+			tc.name += " ip"
+			ip = newIP.String()
+		}
+		if 0 != i&4 {
+			tc.patch.NewPort = &newPort
+			tc.name += " port"
+			port = "4040"
+		}
+		tc.addr = net.JoinHostPort(ip, port)
+		result = append(result, tc)
+	}
+	return result
+}
+
+// checkUpdatedSlave checks that PRAGUE was turned into slave with addr keeping all IPs
+func checkUpdatedSlave(t *testing.T, m *MockServer, name string, slave string, addr string) {
+	t.Helper()
+
+	expected := map[string]string{"BERLIN": "2.2.2.2:3030", slave: addr}
+	addrs, err := m.Client().GetSlavesAddrs()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, addrs) {
+		t.Errorf("%s: expected slaves %v, got %v", name, expected, addrs)
+	}
+
+	ips := m.Config().Ping.IPs
+	for ip, slaves := range map[string][]string{
+		"10.0.0.1": {slave},
+		"10.0.0.2": applySlaves([]string{"BERLIN"}, map[string]bool{slave: true}),
+	} {
+		desc, ok := ips[ip]
+		if !ok {
+			t.Errorf("%s: %s was dropped", name, ip)
+			continue
+		}
+		if !reflect.DeepEqual(slaves, desc.Slaves) {
+			t.Errorf("%s: expected slaves of %s %v, got %v", name, ip, slaves, desc.Slaves)
+		}
+	}
+}
+
+func TestUpdateSlaveWithoutPatch(t *testing.T) {
+	for _, tc := range slavePatches() {
+		m := newSlavesMock(t)
+
+		if err := m.Client().UpdateSlave("PRAGUE", tc.patch); nil != err {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		checkUpdatedSlave(t, m, tc.name, tc.slave, tc.addr)
+
+		for _, request := range m.Requests() {
+			if "DELETE /v1/slaves" == request {
+				t.Errorf("%s: slave must not be deleted", tc.name)
+			}
+		}
+	}
+}
+
+func TestUpdateSlaveWithoutRename(t *testing.T) {
+	for _, tc := range slavePatches() {
+		m := newSlavesMock(t)
+		m.SimulateError("/v1/slaves", "PUT", 404)
+
+		// rename falls back to re-creating slave which has to move IPs before old slave is deleted
+		if err := m.Client().UpdateSlave("PRAGUE", tc.patch); nil != err {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		checkUpdatedSlave(t, m, tc.name, tc.slave, tc.addr)
+	}
+}
+
+func TestUpdateSlaveWithoutAddressUpdate(t *testing.T) {
+	for _, tc := range slavePatches() {
+		m := newSlavesMock(t)
+		m.SimulateError("/v1/slaves", "POST", 404)
+
+		err := m.Client().UpdateSlave("PRAGUE", tc.patch)
+		if nil == tc.patch.NewIP && nil == tc.patch.NewPort {
+			if nil != err {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+			checkUpdatedSlave(t, m, tc.name, tc.slave, tc.addr)
+			continue
+		}
+
+		if !errors.Is(err, api.ErrSlaveUpdateUnsupported) {
+			t.Errorf("%s: expected ErrSlaveUpdateUnsupported, got %v", tc.name, err)
+		}
+		checkUpdatedSlave(t, m, tc.name, "PRAGUE", "1.1.1.1:3030")
+	}
+}
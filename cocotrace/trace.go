@@ -0,0 +1,104 @@
+// Package cocotrace instruments cocopacket API client with OpenTelemetry tracing
+//
+//	cocotrace.SetTracerProvider(client, otel.GetTracerProvider())
+package cocotrace
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	api "github.com/kanocz/cocopacket-go-api"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is name of tracer used for spans
+const instrumentationName = "github.com/kanocz/cocopacket-go-api/cocotrace"
+
+// SlaveKey is attribute with name of slave inferred from request url
+const SlaveKey = attribute.Key("cocopacket.slave")
+
+// SetTracerProvider makes client create client span by tp for every http request (including retries),
+// trace context is propagated to master by global propagator; nil tp removes instrumentation
+func SetTracerProvider(c *api.Client, tp trace.TracerProvider) {
+	if nil == tp {
+		c.SetTransportWrapper(nil)
+		return
+	}
+
+	tracer := tp.Tracer(instrumentationName)
+	c.SetTransportWrapper(func(base http.RoundTripper) http.RoundTripper {
+		return &transport{base: base, tracer: tracer}
+	})
+}
+
+// transport creates span for every request sent by base
+type transport struct {
+	base   http.RoundTripper
+	tracer trace.Tracer
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	attrs := []attribute.KeyValue{
+		semconv.HTTPMethodKey.String(req.Method),
+		semconv.HTTPURLKey.String(req.URL.String()),
+	}
+	if slave := slaveFromURL(req.URL); "" != slave {
+		attrs = append(attrs, SlaveKey.String(slave))
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	// RoundTrip must not modify original request
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if nil != err {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// slaveFromURL returns slave name from "slave" query parameter or /v1/slaves/{slave}... and /v1/status/slaves/{slave}/... paths
+func slaveFromURL(u *url.URL) string {
+	if slave := u.Query().Get("slave"); "" != slave {
+		return slave
+	}
+
+	path := u.EscapedPath()
+	switch {
+	case strings.HasPrefix(path, "/v1/slaves/"):
+		path = strings.TrimPrefix(path, "/v1/slaves/")
+	case strings.HasPrefix(path, "/v1/status/slaves/"):
+		path = strings.TrimPrefix(path, "/v1/status/slaves/")
+		// paths without further segment (like /v1/status/slaves/geo) are about all slaves
+		if !strings.Contains(path, "/") {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	slave, err := url.PathUnescape(strings.SplitN(path, "/", 2)[0])
+	if nil != err {
+		return ""
+	}
+	return slave
+}
@@ -0,0 +1,118 @@
+package cocotrace
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	api "github.com/kanocz/cocopacket-go-api"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracedClient returns client of fake master instrumented with tracer provider recording ended spans
+func newTracedClient(t *testing.T) (*api.Client, *tracetest.SpanRecorder, string) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1/status/slaves":
+			json.NewEncoder(w).Encode(map[string]api.SlaveStatus{"PRAGUE": {Status: "online"}})
+		case "/v1/status/slaves/PRAGUE CZ/connections":
+			json.NewEncoder(w).Encode(map[string]int{"connections": 42})
+		case "/v1/minute/DNS->":
+			json.NewEncoder(w).Encode(map[string]interface{}{"Ping": map[string]*api.AvgChunk{}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	c := api.New(server.URL, "", "")
+	SetTracerProvider(c, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	return c, recorder, server.URL
+}
+
+// spanAttributes returns attributes of span by key
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	result := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		result[kv.Key] = kv.Value
+	}
+	return result
+}
+
+func TestSetTracerProviderSpans(t *testing.T) {
+	c, recorder, base := newTracedClient(t)
+
+	if _, err := c.GetSlavesStatus(); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := c.GetSlaveConnectionCount("PRAGUE CZ"); nil != err {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GroupLastStats("DNS", "LONDON"); nil != err {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		url   string
+		slave string
+	}{
+		{base + "/v1/status/slaves", ""},
+		{base + "/v1/status/slaves/PRAGUE%20CZ/connections", "PRAGUE CZ"},
+		{base + "/v1/minute/DNS-%3E?slave=LONDON", "LONDON"},
+	}
+	spans := recorder.Ended()
+	if len(expected) != len(spans) {
+		t.Fatalf("expected %d spans, got %d", len(expected), len(spans))
+	}
+	for i, span := range spans {
+		attrs := spanAttributes(span)
+		if "HTTP GET" != span.Name() || trace.SpanKindClient != span.SpanKind() {
+			t.Errorf("span %d: unexpected name %q or kind %v", i, span.Name(), span.SpanKind())
+		}
+		if "GET" != attrs[semconv.HTTPMethodKey].AsString() || expected[i].url != attrs[semconv.HTTPURLKey].AsString() {
+			t.Errorf("span %d: unexpected method %q or url %q", i, attrs[semconv.HTTPMethodKey].AsString(), attrs[semconv.HTTPURLKey].AsString())
+		}
+		if 200 != attrs[semconv.HTTPStatusCodeKey].AsInt64() || codes.Error == span.Status().Code {
+			t.Errorf("span %d: unexpected status %d %+v", i, attrs[semconv.HTTPStatusCodeKey].AsInt64(), span.Status())
+		}
+		if slave, ok := attrs[SlaveKey]; expected[i].slave != slave.AsString() || ("" != expected[i].slave) != ok {
+			t.Errorf("span %d: expected slave %q, got %q", i, expected[i].slave, slave.AsString())
+		}
+	}
+}
+
+func TestSetTracerProviderErrors(t *testing.T) {
+	c, recorder, base := newTracedClient(t)
+
+	c.DeleteSlave("BERLIN")
+	spans := recorder.Ended()
+	if 1 != len(spans) {
+		t.Fatalf("expected one span, got %d", len(spans))
+	}
+	attrs := spanAttributes(spans[0])
+	if "HTTP DELETE" != spans[0].Name() || base+"/v1/slaves?slave=BERLIN" != attrs[semconv.HTTPURLKey].AsString() || "BERLIN" != attrs[SlaveKey].AsString() {
+		t.Errorf("unexpected span %q %v", spans[0].Name(), attrs)
+	}
+	if 404 != attrs[semconv.HTTPStatusCodeKey].AsInt64() || codes.Error != spans[0].Status().Code {
+		t.Errorf("expected failed span with status 404, got %d %+v", attrs[semconv.HTTPStatusCodeKey].AsInt64(), spans[0].Status())
+	}
+
+	// nil provider removes instrumentation
+	SetTracerProvider(c, nil)
+	if _, err := c.GetSlavesStatus(); nil != err {
+		t.Fatal(err)
+	}
+	if 1 != len(recorder.Ended()) {
+		t.Errorf("expected no new spans, got %d", len(recorder.Ended()))
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ConfigCache keeps result of GetConfigInfo for tools reading configuration many times, it's safe for concurrent use.
+// Data older than maxAge is still returned by Get while fresh one is loaded in background.
+// Returned ConfigInfo is shared by all callers and must not be modified
+type ConfigCache struct {
+	client *Client
+	maxAge time.Duration
+
+	loadMu sync.Mutex // serializes loads
+
+	mu         sync.RWMutex // guards fields below
+	config     ConfigInfo
+	loaded     time.Time
+	refreshing bool
+}
+
+// NewConfigCache creates cache of configuration loaded by c, zero maxAge means data are never stale
+// (only Refresh and AutoRefresh reload them)
+func NewConfigCache(c *Client, maxAge time.Duration) *ConfigCache {
+	return &ConfigCache{
+		client: c,
+		maxAge: maxAge,
+	}
+}
+
+// Get returns cached configuration loading it first time; stale data are returned immediately and refreshed in background
+func (cc *ConfigCache) Get() (ConfigInfo, error) {
+	return cc.GetContext(context.Background())
+}
+
+// GetContext is same as Get but initial load is bound to ctx
+func (cc *ConfigCache) GetContext(ctx context.Context) (ConfigInfo, error) {
+	cc.mu.Lock()
+	config, loaded := cc.config, cc.loaded
+	stale := !loaded.IsZero() && cc.maxAge > 0 && time.Since(loaded) > cc.maxAge && !cc.refreshing
+	if stale {
+		cc.refreshing = true
+	}
+	cc.mu.Unlock()
+
+	if stale {
+		go func() {
+			if err := cc.load(context.Background(), false); nil != err {
+				cc.client.log().Error("config cache refresh failed", "error", err)
+			}
+		}()
+	}
+	if !loaded.IsZero() {
+		return config, nil
+	}
+
+	if err := cc.load(ctx, true); nil != err {
+		return ConfigInfo{}, err
+	}
+
+	cc.mu.RLock()
+	defer cc.mu.RUnlock()
+	return cc.config, nil
+}
+
+// Refresh reloads configuration immediately
+func (cc *ConfigCache) Refresh() error {
+	return cc.RefreshContext(context.Background())
+}
+
+// RefreshContext is same as Refresh but request is bound to ctx
+func (cc *ConfigCache) RefreshContext(ctx context.Context) error {
+	return cc.load(ctx, false)
+}
+
+// AutoRefresh reloads configuration every interval in background until ctx is done, failed reloads keep previous data
+func (cc *ConfigCache) AutoRefresh(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			if err := cc.load(ctx, false); nil != err && nil == ctx.Err() {
+				cc.client.log().Error("config cache refresh failed", "error", err)
+			}
+		}
+	}()
+}
+
+// load fetches configuration, in case of onlyMissing it's skipped when another load already finished meanwhile
+func (cc *ConfigCache) load(ctx context.Context, onlyMissing bool) error {
+	cc.loadMu.Lock()
+	defer cc.loadMu.Unlock()
+
+	if onlyMissing {
+		cc.mu.RLock()
+		loaded := !cc.loaded.IsZero()
+		cc.mu.RUnlock()
+		if loaded {
+			return nil
+		}
+	}
+
+	config, err := cc.client.GetConfigInfoContext(ctx)
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	cc.refreshing = false
+	if nil != err {
+		return err
+	}
+	cc.config = config
+	cc.loaded = time.Now()
+	return nil
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// counterConfigServer answers GET /v1/config with counter equal to number of request, requests wait for gate
+// (if set) and fail with 500 while failing is set
+type counterConfigServer struct {
+	requests int64
+	failing  int32
+	gate     chan struct{}
+}
+
+func (s *counterConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if "/v1/config" != r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+	n := atomic.AddInt64(&s.requests, 1)
+	if nil != s.gate {
+		<-s.gate
+	}
+	if 0 != atomic.LoadInt32(&s.failing) {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writeTestJSON(w, map[string]int64{"counter": n})
+}
+
+func (s *counterConfigServer) count() int64 {
+	return atomic.LoadInt64(&s.requests)
+}
+
+// waitForCounter calls Get until it returns counter or fails after timeout
+func waitForCounter(t *testing.T, cc *ConfigCache, counter int64) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		config, err := cc.Get()
+		if nil != err {
+			t.Fatal(err)
+		}
+		if counter == config.Counter {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("counter %d not reached", counter)
+}
+
+func TestConfigCacheStaleData(t *testing.T) {
+	s := &counterConfigServer{}
+	c := newTestClient(t, s.ServeHTTP)
+	cc := NewConfigCache(c, 10*time.Millisecond)
+
+	config, err := cc.Get()
+	if nil != err || 1 != config.Counter {
+		t.Fatalf("expected first config, got %d %v", config.Counter, err)
+	}
+	if config, _ := cc.Get(); 1 != config.Counter || 1 != s.count() {
+		t.Fatalf("fresh data must be served from cache, got counter %d after %d requests", config.Counter, s.count())
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	gate := make(chan struct{})
+	s.gate = gate
+
+	// stale data are returned immediately while single refresh is running
+	for i := 0; i < 10; i++ {
+		start := time.Now()
+		config, err := cc.Get()
+		if nil != err || 1 != config.Counter {
+			t.Fatalf("expected stale config, got %d %v", config.Counter, err)
+		}
+		if time.Since(start) > time.Second {
+			t.Fatal("Get waited for refresh")
+		}
+	}
+	close(gate)
+	waitForCounter(t, cc, 2)
+
+	if 2 != s.count() {
+		t.Errorf("expected one background refresh, got %d requests", s.count())
+	}
+}
+
+func TestConfigCacheFailedRefreshKeepsData(t *testing.T) {
+	s := &counterConfigServer{}
+	c := newTestClient(t, s.ServeHTTP)
+	cc := NewConfigCache(c, 0)
+
+	if _, err := cc.Get(); nil != err {
+		t.Fatal(err)
+	}
+	if err := cc.Refresh(); nil != err {
+		t.Fatal(err)
+	}
+	if config, _ := cc.Get(); 2 != config.Counter {
+		t.Fatalf("expected refreshed config, got %d", config.Counter)
+	}
+
+	atomic.StoreInt32(&s.failing, 1)
+	if err := cc.Refresh(); nil == err {
+		t.Fatal("expected refresh error")
+	}
+	if config, err := cc.Get(); nil != err || 2 != config.Counter {
+		t.Fatalf("expected previous config after failed refresh, got %d %v", config.Counter, err)
+	}
+	if 3 != s.count() {
+		t.Errorf("zero maxAge must not refresh in background, got %d requests", s.count())
+	}
+}
+
+func TestConfigCacheConcurrentAccess(t *testing.T) {
+	s := &counterConfigServer{gate: make(chan struct{})}
+	c := newTestClient(t, s.ServeHTTP)
+	cc := NewConfigCache(c, time.Millisecond)
+
+	// concurrent first reads share single load
+	var wg sync.WaitGroup
+	counters := make([]int64, 20)
+	for i := range counters {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			config, err := cc.Get()
+			if nil != err {
+				t.Error(err)
+			}
+			counters[i] = config.Counter
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(s.gate)
+	wg.Wait()
+
+	for i, counter := range counters {
+		if 1 != counter {
+			t.Fatalf("reader %d got counter %d", i, counter)
+		}
+	}
+	if 1 != s.count() {
+		t.Fatalf("expected single initial load, got %d requests", s.count())
+	}
+
+	// readers racing with refreshes always see some complete config
+	ctx, cancel := context.WithCancel(context.Background())
+	cc.AutoRefresh(ctx, time.Millisecond)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if config, err := cc.Get(); nil != err || 0 == config.Counter {
+					t.Errorf("unexpected config %d %v", config.Counter, err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if err := cc.Refresh(); nil != err {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	cancel()
+}
+
+func TestConfigCacheAutoRefresh(t *testing.T) {
+	s := &counterConfigServer{}
+	c := newTestClient(t, s.ServeHTTP)
+	cc := NewConfigCache(c, 0)
+
+	if _, err := cc.Get(); nil != err {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cc.AutoRefresh(ctx, 5*time.Millisecond)
+	waitForCounter(t, cc, 3)
+	cancel()
+
+	// loop notices cancel at latest after one more tick
+	time.Sleep(20 * time.Millisecond)
+	stopped := s.count()
+	time.Sleep(30 * time.Millisecond)
+	if stopped != s.count() {
+		t.Errorf("refreshes continued after cancel: %d -> %d", stopped, s.count())
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// Severity is importance of ConsistencyIssue
+type Severity string
+
+// issue severities
+const (
+	SeverityWarning Severity = "warning" // configuration works but is probably not intended
+	SeverityError   Severity = "error"   // configuration is broken
+)
+
+// ConsistencyIssue is one problem found by CheckGroupConsistency
+type ConsistencyIssue struct {
+	Severity    Severity
+	Code        string // machine readable kind of issue like "missing_parent_group"
+	Description string
+}
+
+// CheckGroupConsistency validates groups configuration: every parent of nested group exists, IPs/URLs reference
+// only valid and configured groups, configured groups are not empty and tests/auto groups use only existing slaves.
+// Issues are ordered by severity (errors first), code and description
+func (c *Client) CheckGroupConsistency() ([]ConsistencyIssue, error) {
+	return c.CheckGroupConsistencyContext(context.Background())
+}
+
+// CheckGroupConsistencyContext is same as CheckGroupConsistency but requests are bound to ctx
+func (c *Client) CheckGroupConsistencyContext(ctx context.Context) ([]ConsistencyIssue, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	slaves, err := c.GetSlaveListContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	return checkGroupConsistency(config, slaves), nil
+}
+
+// checkGroupConsistency finds issues of config with list of existing slaves
+func checkGroupConsistency(config ConfigInfo, slaves []string) []ConsistencyIssue {
+	issues := []ConsistencyIssue{}
+	add := func(severity Severity, code string, description string) {
+		issues = append(issues, ConsistencyIssue{Severity: severity, Code: code, Description: description})
+	}
+
+	knownSlaves := make(map[string]bool, len(slaves))
+	for _, slave := range slaves {
+		knownSlaves[slave] = true
+	}
+
+	// groups referenced by tests and count of their members
+	used := map[string]int{}
+	for kind, tests := range map[string]map[string]TestDesc{"IP": config.Ping.IPs, "URL": config.HTTP.URLs} {
+		for target, desc := range tests {
+			for _, group := range desc.Groups {
+				if !strings.HasSuffix(group, groupSuffix) || "" == strings.TrimSuffix(group, groupSuffix) {
+					add(SeverityError, "invalid_group_name", kind+" "+target+" is in invalid group \""+group+"\"")
+					continue
+				}
+				used[strings.TrimSuffix(group, groupSuffix)]++
+			}
+			for _, slave := range desc.Slaves {
+				if !knownSlaves[slave] {
+					add(SeverityError, "unknown_slave", kind+" "+target+" is assigned to unknown slave "+slave)
+				}
+			}
+		}
+	}
+
+	configured := map[string]GroupConfig{}
+	for group, groupConfig := range config.Groups {
+		name := strings.TrimSuffix(group, groupSuffix)
+		configured[name] = groupConfig
+		for _, slave := range groupConfig.AGSlaves {
+			if !knownSlaves[slave] {
+				add(SeverityError, "unknown_slave", "auto group "+name+" uses unknown slave "+slave)
+			}
+		}
+	}
+
+	existing := map[string]bool{}
+	for group := range used {
+		existing[group] = true
+		if _, ok := configured[group]; !ok {
+			add(SeverityWarning, "unconfigured_group", "group "+group+" is used by tests but has no configuration")
+		}
+	}
+	for group := range configured {
+		existing[group] = true
+	}
+
+	for group := range existing {
+		parts := strings.Split(group, groupSuffix)
+		for i := 1; i < len(parts); i++ {
+			parent := strings.Join(parts[:i], groupSuffix)
+			if !existing[parent] {
+				add(SeverityError, "missing_parent_group", "group "+group+" has missing parent group "+parent)
+			}
+		}
+
+		if groupConfig, ok := configured[group]; ok && 0 == used[group] && !groupConfig.IsAutoGroup && !hasSubgroup(existing, group) {
+			add(SeverityWarning, "empty_group", "group "+group+" has no IPs/URLs and no subgroups")
+		}
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].Severity != issues[j].Severity {
+			return SeverityError == issues[i].Severity
+		}
+		if issues[i].Code != issues[j].Code {
+			return issues[i].Code < issues[j].Code
+		}
+		return issues[i].Description < issues[j].Description
+	})
+	return issues
+}
+
+// hasSubgroup reports if any of groups is nested in group
+func hasSubgroup(groups map[string]bool, group string) bool {
+	for g := range groups {
+		if strings.HasPrefix(g, group+groupSuffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// csvHeader is first line of files written by ExportGroupStatsToCSV and ExportHistoricalStatsToCSV,
+// MinRTT and MaxRTT are always "N/A" as master aggregates only sum of latencies
+var csvHeader = []string{"Target", "Protocol", "AvgRTT", "MinRTT", "MaxRTT", "LossPercent", "Timestamp"}
+
+// ExportGroupStatsToCSV writes last minute stats of group measured by slave (see GroupLastStats) to w as csv,
+// RTT of unreachable targets is written as "N/A"
+func (c *Client) ExportGroupStatsToCSV(group string, slave string, w io.Writer) error {
+	return c.ExportGroupStatsToCSVContext(context.Background(), group, slave, w)
+}
+
+// ExportGroupStatsToCSVContext is same as ExportGroupStatsToCSV but request is bound to ctx
+func (c *Client) ExportGroupStatsToCSVContext(ctx context.Context, group string, slave string, w io.Writer) error {
+	ips, urls, err := c.GroupLastStatsContext(ctx, group, slave)
+	if nil != err {
+		return err
+	}
+
+	timestamp := time.Now().Truncate(time.Minute)
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); nil != err {
+		return err
+	}
+	for _, protocol := range []struct {
+		name  string
+		stats map[string]*AvgChunk
+	}{{"ping", ips}, {"http", urls}} {
+		for _, target := range sortedChunkKeys(protocol.stats) {
+			if err := cw.Write(csvRecord(target, protocol.name, protocol.stats[target], timestamp)); nil != err {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// ExportHistoricalStatsToCSV writes stats of group measured by slave for period from-to to w as csv, one line per target and period,
+// resolution is chosen by length of period
+func (c *Client) ExportHistoricalStatsToCSV(group string, slave string, from, to time.Time, w io.Writer) error {
+	return c.ExportHistoricalStatsToCSVContext(context.Background(), group, slave, from, to, w)
+}
+
+// ExportHistoricalStatsToCSVContext is same as ExportHistoricalStatsToCSV but request is bound to ctx
+func (c *Client) ExportHistoricalStatsToCSVContext(ctx context.Context, group string, slave string, from, to time.Time, w io.Writer) error {
+	data, err := c.historicalStats(ctx, group, slave, from, to, csvResolution(to.Sub(from)))
+	if nil != err {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); nil != err {
+		return err
+	}
+	for _, protocol := range []struct {
+		name  string
+		stats map[string]map[int64]*AvgChunk
+	}{{"ping", data.Ping}, {"http", data.HTTP}} {
+		targets := make([]string, 0, len(protocol.stats))
+		for target := range protocol.stats {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+
+		for _, target := range targets {
+			points := protocol.stats[target]
+			timestamps := make([]int64, 0, len(points))
+			for ts := range points {
+				timestamps = append(timestamps, ts)
+			}
+			sort.Slice(timestamps, func(i, j int) bool { return timestamps[i] < timestamps[j] })
+
+			for _, ts := range timestamps {
+				if err := cw.Write(csvRecord(target, protocol.name, points[ts], time.Unix(ts, 0))); nil != err {
+					return err
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvRecord formats one line of csv export
+func csvRecord(target string, protocol string, chunk *AvgChunk, timestamp time.Time) []string {
+	rtt := "N/A"
+	loss := "N/A"
+	if nil != chunk {
+		if avg := chunk.AvgLatency(); 0 != avg {
+			rtt = strconv.FormatFloat(avg, 'f', 2, 64)
+		}
+		if 0 != chunk.Count {
+			loss = strconv.FormatFloat(chunk.LossRatio()*100, 'f', 2, 64)
+		}
+	}
+	return []string{target, protocol, rtt, "N/A", "N/A", loss, timestamp.UTC().Format(time.RFC3339)}
+}
+
+// csvResolution returns finest resolution keeping historical export reasonably small
+func csvResolution(period time.Duration) time.Duration {
+	switch {
+	case period <= 6*time.Hour:
+		return time.Minute
+	case period <= 2*24*time.Hour:
+		return 5 * time.Minute
+	case period <= 60*24*time.Hour:
+		return time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// sortedChunkKeys returns targets of stats map in stable order
+func sortedChunkKeys(stats map[string]*AvgChunk) []string {
+	keys := make([]string, 0, len(stats))
+	for key := range stats {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
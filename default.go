@@ -0,0 +1,1605 @@
+package api
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// package-level functions below are kept for backward compatibility,
+// they all work with default client set up by Init
+
+var (
+	defaultClient = New("", "", "")
+)
+
+// Init sets API url and authorization parameters of default client
+func Init(url string, username string, password string) {
+	defaultClient.url = url
+	defaultClient.SetBasicAuth(username, password)
+}
+
+// Configure applies options to all future requests of default client
+func Configure(opts ...Option) {
+	defaultClient.Configure(opts...)
+}
+
+// SetBasicAuth sets Authorization header for all future requests
+func SetBasicAuth(username string, password string) {
+	defaultClient.SetBasicAuth(username, password)
+}
+
+// SetBearerToken sets bearer Authorization header for all future requests (replaces basic auth if any)
+func SetBearerToken(token string) {
+	defaultClient.SetBearerToken(token)
+}
+
+// SetTokenRefresher sets function called to obtain new bearer token when server responds with 401,
+// request is repeated once with new token and ErrAuthFailed is returned if it's rejected again
+func SetTokenRefresher(fn func() (string, error)) {
+	defaultClient.SetTokenRefresher(fn)
+}
+
+// SetDefaultHeader sets header added to all future requests, empty value removes header
+func SetDefaultHeader(key string, value string) {
+	defaultClient.SetDefaultHeader(key, value)
+}
+
+// Get executes simple request and decodes json response
+func Get(url string, object interface{}, opts ...RequestOption) error {
+	return defaultClient.Get(url, object, opts...)
+}
+
+// GetContext executes simple request bound to ctx and decodes json response
+func GetContext(ctx context.Context, url string, object interface{}, opts ...RequestOption) error {
+	return defaultClient.GetContext(ctx, url, object, opts...)
+}
+
+// Send json-encoded payload to server using specified method and decode response to object
+func Send(method string, url string, payload interface{}, object interface{}, opts ...RequestOption) error {
+	return defaultClient.Send(method, url, payload, object, opts...)
+}
+
+// SendContext is same as Send but request is bound to ctx
+func SendContext(ctx context.Context, method string, url string, payload interface{}, object interface{}, opts ...RequestOption) error {
+	return defaultClient.SendContext(ctx, method, url, payload, object, opts...)
+}
+
+// SendForm form payload to server using specified method and decode response to object
+func SendForm(method string, url string, payload url.Values, object interface{}, opts ...RequestOption) error {
+	return defaultClient.SendForm(method, url, payload, object, opts...)
+}
+
+// SendFormContext is same as SendForm but request is bound to ctx
+func SendFormContext(ctx context.Context, method string, url string, payload url.Values, object interface{}, opts ...RequestOption) error {
+	return defaultClient.SendFormContext(ctx, method, url, payload, object, opts...)
+}
+
+// GetConfigInfo returns current configuration
+func GetConfigInfo() (ConfigInfo, error) {
+	return defaultClient.GetConfigInfo()
+}
+
+// GetConfigInfoContext is same as GetConfigInfo but request is bound to ctx
+func GetConfigInfoContext(ctx context.Context) (ConfigInfo, error) {
+	return defaultClient.GetConfigInfoContext(ctx)
+}
+
+// GetSlaveList returns list of defined slave probes
+func GetSlaveList() ([]string, error) {
+	return defaultClient.GetSlaveList()
+}
+
+// GetSlaveListContext is same as GetSlaveList but request is bound to ctx
+func GetSlaveListContext(ctx context.Context) ([]string, error) {
+	return defaultClient.GetSlaveListContext(ctx)
+}
+
+// GetSlavesIPs returns list of defined slave probes with their ips
+func GetSlavesIPs() (map[string]string, error) {
+	return defaultClient.GetSlavesIPs()
+}
+
+// GetSlavesIPsContext is same as GetSlavesIPs but request is bound to ctx
+func GetSlavesIPsContext(ctx context.Context) (map[string]string, error) {
+	return defaultClient.GetSlavesIPsContext(ctx)
+}
+
+// GetSlavesSources returns list of defined slave probes with IPv4 ips from which ping/traces are initiated
+//
+// Deprecated: use GetSlavesSourcesAll which fetches both IPv4 and IPv6 sources in one request
+func GetSlavesSources() (map[string]string, error) {
+	return defaultClient.GetSlavesSources()
+}
+
+// GetSlavesSourcesContext is same as GetSlavesSources but request is bound to ctx
+//
+// Deprecated: use GetSlavesSourcesAllContext which fetches both IPv4 and IPv6 sources in one request
+func GetSlavesSourcesContext(ctx context.Context) (map[string]string, error) {
+	return defaultClient.GetSlavesSourcesContext(ctx)
+}
+
+// GetSlavesSources6 returns list of defined slave probes with IPv6 ips from which ping/traces are initiated
+//
+// Deprecated: use GetSlavesSourcesAll which fetches both IPv4 and IPv6 sources in one request
+func GetSlavesSources6() (map[string]string, error) {
+	return defaultClient.GetSlavesSources6()
+}
+
+// GetSlavesSources6Context is same as GetSlavesSources6 but request is bound to ctx
+//
+// Deprecated: use GetSlavesSourcesAllContext which fetches both IPv4 and IPv6 sources in one request
+func GetSlavesSources6Context(ctx context.Context) (map[string]string, error) {
+	return defaultClient.GetSlavesSources6Context(ctx)
+}
+
+// GetSlavesSourcesAll returns IPv4 and IPv6 source ips of all defined slave probes,
+// both maps contain every slave with empty string if slave has no source of given family
+func GetSlavesSourcesAll() (ipv4 map[string]string, ipv6 map[string]string, err error) {
+	return defaultClient.GetSlavesSourcesAll()
+}
+
+// GetSlavesSourcesAllContext is same as GetSlavesSourcesAll but request is bound to ctx
+func GetSlavesSourcesAllContext(ctx context.Context) (ipv4 map[string]string, ipv6 map[string]string, err error) {
+	return defaultClient.GetSlavesSourcesAllContext(ctx)
+}
+
+// GetSlavesStatus returns actual slaves status
+func GetSlavesStatus() (map[string]SlaveStatus, error) {
+	return defaultClient.GetSlavesStatus()
+}
+
+// GetSlavesStatusContext is same as GetSlavesStatus but request is bound to ctx
+func GetSlavesStatusContext(ctx context.Context) (map[string]SlaveStatus, error) {
+	return defaultClient.GetSlavesStatusContext(ctx)
+}
+
+// GetSlavesAddrs returns list of defined slave probes with their ip:port
+func GetSlavesAddrs() (map[string]string, error) {
+	return defaultClient.GetSlavesAddrs()
+}
+
+// GetSlavesAddrsContext is same as GetSlavesAddrs but request is bound to ctx
+func GetSlavesAddrsContext(ctx context.Context) (map[string]string, error) {
+	return defaultClient.GetSlavesAddrsContext(ctx)
+}
+
+// AddSlave adds slave to master on ip:port with name
+// and possibly copy list of ips from just existing slave copyFrom
+func AddSlave(ip net.IP, port uint16, name string, copyFrom string) error {
+	return defaultClient.AddSlave(ip, port, name, copyFrom)
+}
+
+// AddSlaveContext is same as AddSlave but request is bound to ctx
+func AddSlaveContext(ctx context.Context, ip net.IP, port uint16, name string, copyFrom string) error {
+	return defaultClient.AddSlaveContext(ctx, ip, port, name, copyFrom)
+}
+
+// DeleteSlave removes slave from master
+func DeleteSlave(slave string) error {
+	return defaultClient.DeleteSlave(slave)
+}
+
+// DeleteSlaveContext is same as DeleteSlave but request is bound to ctx
+func DeleteSlaveContext(ctx context.Context, slave string) error {
+	return defaultClient.DeleteSlaveContext(ctx, slave)
+}
+
+// AddIP is simple interface for single IP adding
+func AddIP(ip string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	return defaultClient.AddIP(ip, slaves, description, groups, favorite, opts...)
+}
+
+// AddIPContext is same as AddIP but request is bound to ctx
+func AddIPContext(ctx context.Context, ip string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	return defaultClient.AddIPContext(ctx, ip, slaves, description, groups, favorite, opts...)
+}
+
+// AddIPs function adds multiply ips using only one API call
+func AddIPs(ips []string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	return defaultClient.AddIPs(ips, slaves, description, groups, favorite, opts...)
+}
+
+// AddIPsContext is same as AddIPs but request is bound to ctx
+func AddIPsContext(ctx context.Context, ips []string, slaves []string, description string, groups []string, favorite bool, opts ...ProbeOption) error {
+	return defaultClient.AddIPsContext(ctx, ips, slaves, description, groups, favorite, opts...)
+}
+
+// AddIPsRaw is extended function adds multiply ips using only one API call
+func AddIPsRaw(ips map[string]TestDesc) error {
+	return defaultClient.AddIPsRaw(ips)
+}
+
+// AddIPsRawContext is same as AddIPsRaw but request is bound to ctx
+func AddIPsRawContext(ctx context.Context, ips map[string]TestDesc) error {
+	return defaultClient.AddIPsRawContext(ctx, ips)
+}
+
+// UpdateIP changes configuration of already monitored IP without re-adding it, ErrIPNotFound if IP isn't monitored
+func UpdateIP(ip string, patch TestDescPatch) error {
+	return defaultClient.UpdateIP(ip, patch)
+}
+
+// UpdateIPContext is same as UpdateIP but requests are bound to ctx
+func UpdateIPContext(ctx context.Context, ip string, patch TestDescPatch) error {
+	return defaultClient.UpdateIPContext(ctx, ip, patch)
+}
+
+// DeleteIP removes one IP from cocopacket instance
+func DeleteIP(ip string) error {
+	return defaultClient.DeleteIP(ip)
+}
+
+// DeleteIPContext is same as DeleteIP but request is bound to ctx
+func DeleteIPContext(ctx context.Context, ip string) error {
+	return defaultClient.DeleteIPContext(ctx, ip)
+}
+
+// DeleteIPs function deletes multiply ips using only one API call
+func DeleteIPs(ips []string) error {
+	return defaultClient.DeleteIPs(ips)
+}
+
+// DeleteIPsContext is same as DeleteIPs but request is bound to ctx
+func DeleteIPsContext(ctx context.Context, ips []string) error {
+	return defaultClient.DeleteIPsContext(ctx, ips)
+}
+
+// GetIPDetails returns configuration and current status of one monitored IP, ErrIPNotFound if IP isn't monitored
+func GetIPDetails(ip string) (IPDetail, error) {
+	return defaultClient.GetIPDetails(ip)
+}
+
+// GetIPDetailsContext is same as GetIPDetails but request is bound to ctx
+func GetIPDetailsContext(ctx context.Context, ip string) (IPDetail, error) {
+	return defaultClient.GetIPDetailsContext(ctx, ip)
+}
+
+// ListUsers return map with logins and associated boolean indicating if user is admin
+func ListUsers() (map[string]bool, error) {
+	return defaultClient.ListUsers()
+}
+
+// ListUsersContext is same as ListUsers but request is bound to ctx
+func ListUsersContext(ctx context.Context) (map[string]bool, error) {
+	return defaultClient.ListUsersContext(ctx)
+}
+
+// AddUser adds new user (or replaces existing)
+func AddUser(login string, password string, admin bool) (map[string]bool, error) {
+	return defaultClient.AddUser(login, password, admin)
+}
+
+// AddUserContext is same as AddUser but request is bound to ctx
+func AddUserContext(ctx context.Context, login string, password string, admin bool) (map[string]bool, error) {
+	return defaultClient.AddUserContext(ctx, login, password, admin)
+}
+
+// DeleteUser removes user from master
+func DeleteUser(login string) (map[string]bool, error) {
+	return defaultClient.DeleteUser(login)
+}
+
+// DeleteUserContext is same as DeleteUser but request is bound to ctx
+func DeleteUserContext(ctx context.Context, login string) (map[string]bool, error) {
+	return defaultClient.DeleteUserContext(ctx, login)
+}
+
+// GroupStats returns stats for all IPs/URLs in group for about last 24 hours with 1-hour aggregation (report -> limit only to ip+slaves selected for report using frontend)
+func GroupStats(group string, report bool) (GroupStatsData, error) {
+	return defaultClient.GroupStats(group, report)
+}
+
+// GroupStatsContext is same as GroupStats but request is bound to ctx
+func GroupStatsContext(ctx context.Context, group string, report bool) (GroupStatsData, error) {
+	return defaultClient.GroupStatsContext(ctx, group, report)
+}
+
+// GroupLastStats returns stats for all IPs/URLs in group on one slave for last minute period (used for exports to other systems)
+func GroupLastStats(group string, slave string) (ips map[string]*AvgChunk, urls map[string]*AvgChunk, err error) {
+	return defaultClient.GroupLastStats(group, slave)
+}
+
+// GroupLastStatsContext is same as GroupLastStats but request is bound to ctx
+func GroupLastStatsContext(ctx context.Context, group string, slave string) (ips map[string]*AvgChunk, urls map[string]*AvgChunk, err error) {
+	return defaultClient.GroupLastStatsContext(ctx, group, slave)
+}
+
+// IPsSetSlaves add/remove slaves for list of ips, in case of "true" slave is added, in case of "false" slave removed, unlisted slaves are untouched
+func IPsSetSlaves(ips []string, slaves map[string]bool) error {
+	return defaultClient.IPsSetSlaves(ips, slaves)
+}
+
+// IPsSetSlavesContext is same as IPsSetSlaves but request is bound to ctx
+func IPsSetSlavesContext(ctx context.Context, ips []string, slaves map[string]bool) error {
+	return defaultClient.IPsSetSlavesContext(ctx, ips, slaves)
+}
+
+// AddIPsToSlave adds slave to all listed ips
+func AddIPsToSlave(ips []string, slave string) error {
+	return defaultClient.AddIPsToSlave(ips, slave)
+}
+
+// AddIPsToSlaveContext is same as AddIPsToSlave but request is bound to ctx
+func AddIPsToSlaveContext(ctx context.Context, ips []string, slave string) error {
+	return defaultClient.AddIPsToSlaveContext(ctx, ips, slave)
+}
+
+// RemoveIPsFromSlave removes slave from all listed ips
+func RemoveIPsFromSlave(ips []string, slave string) error {
+	return defaultClient.RemoveIPsFromSlave(ips, slave)
+}
+
+// RemoveIPsFromSlaveContext is same as RemoveIPsFromSlave but request is bound to ctx
+func RemoveIPsFromSlaveContext(ctx context.Context, ips []string, slave string) error {
+	return defaultClient.RemoveIPsFromSlaveContext(ctx, ips, slave)
+}
+
+// GroupSetSlaves add/remove slaves for all ips in group, in case of "true" slave is added, in case of "false" slave removed, unlisted slaves are untouched; pass recursive=true to include subgroups
+func GroupSetSlaves(group string, slaves map[string]bool, recursive bool) error {
+	return defaultClient.GroupSetSlaves(group, slaves, recursive)
+}
+
+// GroupSetSlavesContext is same as GroupSetSlaves but request is bound to ctx
+func GroupSetSlavesContext(ctx context.Context, group string, slaves map[string]bool, recursive bool) error {
+	return defaultClient.GroupSetSlavesContext(ctx, group, slaves, recursive)
+}
+
+// GetGroupList returns sorted list of all configured groups (without trailing "->")
+func GetGroupList() ([]string, error) {
+	return defaultClient.GetGroupList()
+}
+
+// GetGroupListContext is same as GetGroupList but request is bound to ctx
+func GetGroupListContext(ctx context.Context) ([]string, error) {
+	return defaultClient.GetGroupListContext(ctx)
+}
+
+// GetGroupMembers returns sorted list of IPs/URLs in group, pass recursive=true to include subgroups
+func GetGroupMembers(group string, recursive bool) ([]string, error) {
+	return defaultClient.GetGroupMembers(group, recursive)
+}
+
+// GetGroupMembersContext is same as GetGroupMembers but request is bound to ctx
+func GetGroupMembersContext(ctx context.Context, group string, recursive bool) ([]string, error) {
+	return defaultClient.GetGroupMembersContext(ctx, group, recursive)
+}
+
+// GroupLastStatsAll returns last-minute stats for group from all slaves queried in parallel,
+// failed slaves are present in result with Err set and also reported by returned MultiError
+func GroupLastStatsAll(group string) (map[string]SlaveResult, error) {
+	return defaultClient.GroupLastStatsAll(group)
+}
+
+// GroupLastStatsAllContext is same as GroupLastStatsAll but requests are bound to ctx
+func GroupLastStatsAllContext(ctx context.Context, group string) (map[string]SlaveResult, error) {
+	return defaultClient.GroupLastStatsAllContext(ctx, group)
+}
+
+// AddURL is simple interface for single URL (http test) adding
+func AddURL(rawURL string, slaves []string, description string, groups []string, favorite bool, options URLOptions) error {
+	return defaultClient.AddURL(rawURL, slaves, description, groups, favorite, options)
+}
+
+// AddURLContext is same as AddURL but request is bound to ctx
+func AddURLContext(ctx context.Context, rawURL string, slaves []string, description string, groups []string, favorite bool, options URLOptions) error {
+	return defaultClient.AddURLContext(ctx, rawURL, slaves, description, groups, favorite, options)
+}
+
+// AddURLRaw is extended function adds (or replaces) one URL with full test description
+func AddURLRaw(rawURL string, desc TestDesc) error {
+	return defaultClient.AddURLRaw(rawURL, desc)
+}
+
+// AddURLRawContext is same as AddURLRaw but request is bound to ctx
+func AddURLRawContext(ctx context.Context, rawURL string, desc TestDesc) error {
+	return defaultClient.AddURLRawContext(ctx, rawURL, desc)
+}
+
+// DeleteURL removes one URL (http test) from cocopacket instance
+func DeleteURL(rawURL string) error {
+	return defaultClient.DeleteURL(rawURL)
+}
+
+// DeleteURLContext is same as DeleteURL but request is bound to ctx
+func DeleteURLContext(ctx context.Context, rawURL string) error {
+	return defaultClient.DeleteURLContext(ctx, rawURL)
+}
+
+// GetHistoricalStats returns stats for all IPs/URLs in group for period from-to aggregated by resolution (one of HistoryResolutions)
+func GetHistoricalStats(group string, from, to time.Time, resolution time.Duration) (GroupStatsData, error) {
+	return defaultClient.GetHistoricalStats(group, from, to, resolution)
+}
+
+// GetHistoricalStatsContext is same as GetHistoricalStats but request is bound to ctx
+func GetHistoricalStatsContext(ctx context.Context, group string, from, to time.Time, resolution time.Duration) (GroupStatsData, error) {
+	return defaultClient.GetHistoricalStatsContext(ctx, group, from, to, resolution)
+}
+
+// SlaveSetMaintenance enables/disables maintenance of slave (no alerts generated), zero until means until disabled manually
+func SlaveSetMaintenance(slave string, enable bool, until time.Time) error {
+	return defaultClient.SlaveSetMaintenance(slave, enable, until)
+}
+
+// SlaveSetMaintenanceContext is same as SlaveSetMaintenance but request is bound to ctx
+func SlaveSetMaintenanceContext(ctx context.Context, slave string, enable bool, until time.Time) error {
+	return defaultClient.SlaveSetMaintenanceContext(ctx, slave, enable, until)
+}
+
+// GetSlaveMaintenanceStatus returns current maintenance window of slave
+func GetSlaveMaintenanceStatus(slave string) (MaintenanceStatus, error) {
+	return defaultClient.GetSlaveMaintenanceStatus(slave)
+}
+
+// GetSlaveMaintenanceStatusContext is same as GetSlaveMaintenanceStatus but request is bound to ctx
+func GetSlaveMaintenanceStatusContext(ctx context.Context, slave string) (MaintenanceStatus, error) {
+	return defaultClient.GetSlaveMaintenanceStatusContext(ctx, slave)
+}
+
+// WaitUntilSlaveActive polls maintenance status of slave every pollInterval until maintenance ends or ctx is done
+func WaitUntilSlaveActive(ctx context.Context, slave string, pollInterval time.Duration) error {
+	return defaultClient.WaitUntilSlaveActive(ctx, slave, pollInterval)
+}
+
+// GetIPStats returns latency percentiles and loss of ip measured by slave for period from-to,
+// zero from/to defaults to last 24 hours
+func GetIPStats(ip string, slave string, from, to time.Time) (IPStatsDetail, error) {
+	return defaultClient.GetIPStats(ip, slave, from, to)
+}
+
+// GetIPStatsContext is same as GetIPStats but request is bound to ctx
+func GetIPStatsContext(ctx context.Context, ip string, slave string, from, to time.Time) (IPStatsDetail, error) {
+	return defaultClient.GetIPStatsContext(ctx, ip, slave, from, to)
+}
+
+// GetSlaveLatency measures time of TCP connect to slave's own API (address is taken from GetSlavesAddrs)
+func GetSlaveLatency(slave string) (time.Duration, error) {
+	return defaultClient.GetSlaveLatency(slave)
+}
+
+// GetSlaveLatencyContext is same as GetSlaveLatency but requests are bound to ctx
+func GetSlaveLatencyContext(ctx context.Context, slave string) (time.Duration, error) {
+	return defaultClient.GetSlaveLatencyContext(ctx, slave)
+}
+
+// PingAllSlaves measures latency to all slaves in parallel, unreachable slaves have Err set
+func PingAllSlaves() (map[string]SlaveLatencyResult, error) {
+	return defaultClient.PingAllSlaves()
+}
+
+// PingAllSlavesContext is same as PingAllSlaves but requests are bound to ctx
+func PingAllSlavesContext(ctx context.Context) (map[string]SlaveLatencyResult, error) {
+	return defaultClient.PingAllSlavesContext(ctx)
+}
+
+// SetTLSClientCert loads client certificate and key from PEM files and uses them for all future requests
+func SetTLSClientCert(certFile, keyFile string) error {
+	return defaultClient.SetTLSClientCert(certFile, keyFile)
+}
+
+// SetTLSClientCertPEM uses PEM encoded client certificate and key for all future requests
+func SetTLSClientCertPEM(certPEM, keyPEM []byte) error {
+	return defaultClient.SetTLSClientCertPEM(certPEM, keyPEM)
+}
+
+// SetTLSInsecureSkipVerify disables (or enables back) verification of server certificate
+func SetTLSInsecureSkipVerify(skip bool) {
+	defaultClient.SetTLSInsecureSkipVerify(skip)
+}
+
+// SetTLSRootCA makes client trust only CA certificates from PEM file caFile (usable for self-signed server certs)
+func SetTLSRootCA(caFile string) error {
+	return defaultClient.SetTLSRootCA(caFile)
+}
+
+// GetGroupConfig returns settings of group
+func GetGroupConfig(group string) (GroupConfig, error) {
+	return defaultClient.GetGroupConfig(group)
+}
+
+// GetGroupConfigContext is same as GetGroupConfig but request is bound to ctx
+func GetGroupConfigContext(ctx context.Context, group string) (GroupConfig, error) {
+	return defaultClient.GetGroupConfigContext(ctx, group)
+}
+
+// SetGroupConfig replaces settings of group
+func SetGroupConfig(group string, config GroupConfig) error {
+	return defaultClient.SetGroupConfig(group, config)
+}
+
+// SetGroupConfigContext is same as SetGroupConfig but request is bound to ctx
+func SetGroupConfigContext(ctx context.Context, group string, config GroupConfig) error {
+	return defaultClient.SetGroupConfigContext(ctx, group, config)
+}
+
+// ChangeUserPassword sets new password of existing user keeping its admin status, ErrUserNotFound if there is no such user
+func ChangeUserPassword(login string, newPassword string) error {
+	return defaultClient.ChangeUserPassword(login, newPassword)
+}
+
+// ChangeUserPasswordContext is same as ChangeUserPassword but requests are bound to ctx
+func ChangeUserPasswordContext(ctx context.Context, login string, newPassword string) error {
+	return defaultClient.ChangeUserPasswordContext(ctx, login, newPassword)
+}
+
+// ChangeUserRole sets admin status of existing user (RoleAdmin or RoleOperator), ErrUserNotFound if there is no such user;
+// servers without role support replace whole user record including password on update, so there status can't be
+// changed without password and ErrPasswordRequired is returned (use ChangeUserRoleWithPassword)
+func ChangeUserRole(login string, admin bool) error {
+	return defaultClient.ChangeUserRole(login, admin)
+}
+
+// ChangeUserRoleContext is same as ChangeUserRole but request is bound to ctx
+func ChangeUserRoleContext(ctx context.Context, login string, admin bool) error {
+	return defaultClient.ChangeUserRoleContext(ctx, login, admin)
+}
+
+// ChangeUserRoleWithPassword sets admin status of existing user re-sending its password, ErrUserNotFound if there is no such user
+func ChangeUserRoleWithPassword(login string, admin bool, password string) error {
+	return defaultClient.ChangeUserRoleWithPassword(login, admin, password)
+}
+
+// ChangeUserRoleWithPasswordContext is same as ChangeUserRoleWithPassword but requests are bound to ctx
+func ChangeUserRoleWithPasswordContext(ctx context.Context, login string, admin bool, password string) error {
+	return defaultClient.ChangeUserRoleWithPasswordContext(ctx, login, admin, password)
+}
+
+// GetMultiGroupStats returns GroupStats for several groups queried in parallel,
+// result contains only successful groups and failed ones are reported by returned MultiError
+func GetMultiGroupStats(groups []string, report bool) (map[string]GroupStatsData, error) {
+	return defaultClient.GetMultiGroupStats(groups, report)
+}
+
+// GetMultiGroupStatsContext is same as GetMultiGroupStats but requests are bound to ctx
+func GetMultiGroupStatsContext(ctx context.Context, groups []string, report bool) (map[string]GroupStatsData, error) {
+	return defaultClient.GetMultiGroupStatsContext(ctx, groups, report)
+}
+
+// CloneGroup adds all IPs/URLs of src group also to dst group (src subgroups are cloned as dst subgroups in case of recursive)
+// and then applies slaves changes to dst like GroupSetSlaves does; ErrGroupExists is returned if dst exists and overwrite isn't set
+func CloneGroup(src, dst string, slaves map[string]bool, recursive bool, overwrite bool) error {
+	return defaultClient.CloneGroup(src, dst, slaves, recursive, overwrite)
+}
+
+// CloneGroupContext is same as CloneGroup but requests are bound to ctx
+func CloneGroupContext(ctx context.Context, src, dst string, slaves map[string]bool, recursive bool, overwrite bool) error {
+	return defaultClient.CloneGroupContext(ctx, src, dst, slaves, recursive, overwrite)
+}
+
+// GetAlertRules returns all configured alert rules
+func GetAlertRules() ([]AlertRule, error) {
+	return defaultClient.GetAlertRules()
+}
+
+// GetAlertRulesContext is same as GetAlertRules but request is bound to ctx
+func GetAlertRulesContext(ctx context.Context) ([]AlertRule, error) {
+	return defaultClient.GetAlertRulesContext(ctx)
+}
+
+// SetAlertRule creates new alert rule (empty ID) or replaces existing one
+func SetAlertRule(rule AlertRule) error {
+	return defaultClient.SetAlertRule(rule)
+}
+
+// SetAlertRuleContext is same as SetAlertRule but request is bound to ctx
+func SetAlertRuleContext(ctx context.Context, rule AlertRule) error {
+	return defaultClient.SetAlertRuleContext(ctx, rule)
+}
+
+// DeleteAlertRule removes alert rule
+func DeleteAlertRule(id string) error {
+	return defaultClient.DeleteAlertRule(id)
+}
+
+// DeleteAlertRuleContext is same as DeleteAlertRule but request is bound to ctx
+func DeleteAlertRuleContext(ctx context.Context, id string) error {
+	return defaultClient.DeleteAlertRuleContext(ctx, id)
+}
+
+// TestAlertRule asks server to evaluate alert rule against current data
+func TestAlertRule(id string) error {
+	return defaultClient.TestAlertRule(id)
+}
+
+// TestAlertRuleContext is same as TestAlertRule but request is bound to ctx
+func TestAlertRuleContext(ctx context.Context, id string) error {
+	return defaultClient.TestAlertRuleContext(ctx, id)
+}
+
+// TriggerTraceroute starts traceroute to ip from slave and returns id of job
+func TriggerTraceroute(ip string, slave string) (string, error) {
+	return defaultClient.TriggerTraceroute(ip, slave)
+}
+
+// TriggerTracerouteContext is same as TriggerTraceroute but request is bound to ctx
+func TriggerTracerouteContext(ctx context.Context, ip string, slave string) (string, error) {
+	return defaultClient.TriggerTracerouteContext(ctx, ip, slave)
+}
+
+// GetTracerouteResult returns current state of traceroute job
+func GetTracerouteResult(jobID string) (TracerouteResult, error) {
+	return defaultClient.GetTracerouteResult(jobID)
+}
+
+// GetTracerouteResultContext is same as GetTracerouteResult but request is bound to ctx
+func GetTracerouteResultContext(ctx context.Context, jobID string) (TracerouteResult, error) {
+	return defaultClient.GetTracerouteResultContext(ctx, jobID)
+}
+
+// WaitForTraceroute polls traceroute job every poll interval until it's done or ctx is done
+func WaitForTraceroute(ctx context.Context, jobID string, poll time.Duration) (TracerouteResult, error) {
+	return defaultClient.WaitForTraceroute(ctx, jobID, poll)
+}
+
+// GetSlaveConfig returns probe settings of slave
+func GetSlaveConfig(slave string) (SlaveConfig, error) {
+	return defaultClient.GetSlaveConfig(slave)
+}
+
+// GetSlaveConfigContext is same as GetSlaveConfig but request is bound to ctx
+func GetSlaveConfigContext(ctx context.Context, slave string) (SlaveConfig, error) {
+	return defaultClient.GetSlaveConfigContext(ctx, slave)
+}
+
+// SetSlaveConfig updates probe settings of slave, only non-zero fields of cfg are sent so others keep current values
+func SetSlaveConfig(slave string, cfg SlaveConfig) error {
+	return defaultClient.SetSlaveConfig(slave, cfg)
+}
+
+// SetSlaveConfigContext is same as SetSlaveConfig but request is bound to ctx
+func SetSlaveConfigContext(ctx context.Context, slave string, cfg SlaveConfig) error {
+	return defaultClient.SetSlaveConfigContext(ctx, slave, cfg)
+}
+
+// GetTopWorstIPs returns up to n monitored IPs with highest last-minute "loss" or "rtt" on slave,
+// ties are ordered by IP; stats are collected from all groups so IPs without any group are not included
+func GetTopWorstIPs(slave string, metric string, n int) ([]IPRanking, error) {
+	return defaultClient.GetTopWorstIPs(slave, metric, n)
+}
+
+// GetTopWorstIPsContext is same as GetTopWorstIPs but requests are bound to ctx
+func GetTopWorstIPsContext(ctx context.Context, slave string, metric string, n int) ([]IPRanking, error) {
+	return defaultClient.GetTopWorstIPsContext(ctx, slave, metric, n)
+}
+
+// GetConfigPage returns one page of monitored IPs, pass empty pageToken for first page
+func GetConfigPage(pageToken string, pageSize int) (ConfigPage, error) {
+	return defaultClient.GetConfigPage(pageToken, pageSize)
+}
+
+// GetConfigPageContext is same as GetConfigPage but request is bound to ctx
+func GetConfigPageContext(ctx context.Context, pageToken string, pageSize int) (ConfigPage, error) {
+	return defaultClient.GetConfigPageContext(ctx, pageToken, pageSize)
+}
+
+// IterConfig returns iterator over all monitored IPs loading pageSize entries per request
+func IterConfig(pageSize int) *ConfigIterator {
+	return defaultClient.IterConfig(pageSize)
+}
+
+// IterConfigContext is same as IterConfig but requests are bound to ctx
+func IterConfigContext(ctx context.Context, pageSize int) *ConfigIterator {
+	return defaultClient.IterConfigContext(ctx, pageSize)
+}
+
+// GetSlavesOnline returns sorted list of slaves currently connected to master
+func GetSlavesOnline() ([]string, error) {
+	return defaultClient.GetSlavesOnline()
+}
+
+// GetSlavesOnlineContext is same as GetSlavesOnline but request is bound to ctx
+func GetSlavesOnlineContext(ctx context.Context) ([]string, error) {
+	return defaultClient.GetSlavesOnlineContext(ctx)
+}
+
+// GetSlavesOffline returns sorted list of slaves currently unreachable from master
+func GetSlavesOffline() ([]string, error) {
+	return defaultClient.GetSlavesOffline()
+}
+
+// GetSlavesOfflineContext is same as GetSlavesOffline but request is bound to ctx
+func GetSlavesOfflineContext(ctx context.Context) ([]string, error) {
+	return defaultClient.GetSlavesOfflineContext(ctx)
+}
+
+// LastSeenBefore returns sorted list of slaves that haven't reported to master during last d
+func LastSeenBefore(d time.Duration) ([]string, error) {
+	return defaultClient.LastSeenBefore(d)
+}
+
+// LastSeenBeforeContext is same as LastSeenBefore but request is bound to ctx
+func LastSeenBeforeContext(ctx context.Context, d time.Duration) ([]string, error) {
+	return defaultClient.LastSeenBeforeContext(ctx, d)
+}
+
+// RenameSlave changes name of slave keeping its IPs/tests assignments.
+//
+// Server-side rename (PUT /v1/slaves) is atomic. If server doesn't support it, slave is re-created under new name,
+// all IPs/tests are reassigned from old to new name and old slave is deleted; this fallback is not atomic
+// and on error in the middle both slaves may exist, so it's safe to call RenameSlave again.
+func RenameSlave(oldName string, newName string) error {
+	return defaultClient.RenameSlave(oldName, newName)
+}
+
+// RenameSlaveContext is same as RenameSlave but requests are bound to ctx
+func RenameSlaveContext(ctx context.Context, oldName string, newName string) error {
+	return defaultClient.RenameSlaveContext(ctx, oldName, newName)
+}
+
+// WatchSlavesStatus polls slaves status every interval and sends event to returned channel when status of any slave changes
+// (Last timestamp alone is not a change); polling errors are skipped, channel is closed when ctx is done.
+// Error is returned only if interval isn't positive or initial status can't be loaded.
+func WatchSlavesStatus(ctx context.Context, interval time.Duration) (<-chan SlavesStatusEvent, error) {
+	return defaultClient.WatchSlavesStatus(ctx, interval)
+}
+
+// DeleteGroup removes group (not recursive, subgroups stay untouched); server also removes IPs/tests which are not in any other group.
+// When deleteMembers is true all IPs/tests of group are removed first even if they belong to other groups,
+// failed removals are reported by returned MultiError but group is deleted anyway
+func DeleteGroup(group string, deleteMembers bool) error {
+	return defaultClient.DeleteGroup(group, deleteMembers)
+}
+
+// DeleteGroupContext is same as DeleteGroup but requests are bound to ctx
+func DeleteGroupContext(ctx context.Context, group string, deleteMembers bool) error {
+	return defaultClient.DeleteGroupContext(ctx, group, deleteMembers)
+}
+
+// AddGroup creates group with description, ips and slaves assigned to them, ErrGroupExists is returned if group already exists
+func AddGroup(name string, description string, ips []string, slaves []string) error {
+	return defaultClient.AddGroup(name, description, ips, slaves)
+}
+
+// AddGroupContext is same as AddGroup but request is bound to ctx
+func AddGroupContext(ctx context.Context, name string, description string, ips []string, slaves []string) error {
+	return defaultClient.AddGroupContext(ctx, name, description, ips, slaves)
+}
+
+// CreateOrUpdateGroup is same as AddGroup but replaces description, ips and slaves of group if it already exists
+func CreateOrUpdateGroup(name string, description string, ips []string, slaves []string) error {
+	return defaultClient.CreateOrUpdateGroup(name, description, ips, slaves)
+}
+
+// CreateOrUpdateGroupContext is same as CreateOrUpdateGroup but request is bound to ctx
+func CreateOrUpdateGroupContext(ctx context.Context, name string, description string, ips []string, slaves []string) error {
+	return defaultClient.CreateOrUpdateGroupContext(ctx, name, description, ips, slaves)
+}
+
+// HealthCheck verifies that master is reachable and accepts credentials, it's recommended to call it right after Init/New.
+// Returned error wraps ErrUnreachable, ErrUnauthorized or ErrServerError (with status code) so it can be tested by errors.Is
+func HealthCheck() error {
+	return defaultClient.HealthCheck()
+}
+
+// HealthCheckContext is same as HealthCheck but request is bound to ctx
+func HealthCheckContext(ctx context.Context) error {
+	return defaultClient.HealthCheckContext(ctx)
+}
+
+// GetIPGroups returns sorted list of groups (without trailing "->") ip belongs to, ErrIPNotFound if IP isn't monitored
+func GetIPGroups(ip string) ([]string, error) {
+	return defaultClient.GetIPGroups(ip)
+}
+
+// GetIPGroupsContext is same as GetIPGroups but request is bound to ctx
+func GetIPGroupsContext(ctx context.Context, ip string) ([]string, error) {
+	return defaultClient.GetIPGroupsContext(ctx, ip)
+}
+
+// BulkGetIPGroups is same as GetIPGroups for many ips using only one API call, unmonitored ips are missing in result
+func BulkGetIPGroups(ips []string) (map[string][]string, error) {
+	return defaultClient.BulkGetIPGroups(ips)
+}
+
+// BulkGetIPGroupsContext is same as BulkGetIPGroups but request is bound to ctx
+func BulkGetIPGroupsContext(ctx context.Context, ips []string) (map[string][]string, error) {
+	return defaultClient.BulkGetIPGroupsContext(ctx, ips)
+}
+
+// CopyGroupSlaves assigns slaves used by IPs/URLs of src group to all IPs/URLs of dst group; in case of merge=false
+// slaves used in dst but not in src are removed from dst. Applied changes are logged with debug level
+func CopyGroupSlaves(src, dst string, merge bool) error {
+	return defaultClient.CopyGroupSlaves(src, dst, merge)
+}
+
+// CopyGroupSlavesContext is same as CopyGroupSlaves but requests are bound to ctx
+func CopyGroupSlavesContext(ctx context.Context, src, dst string, merge bool) error {
+	return defaultClient.CopyGroupSlavesContext(ctx, src, dst, merge)
+}
+
+// GetConfigInfoFiltered returns current configuration with only IPs/URLs matching filter; filter is sent to server
+// and applied once more on client side so result is the same even if server doesn't support filtering
+func GetConfigInfoFiltered(filter IPFilter) (ConfigInfo, error) {
+	return defaultClient.GetConfigInfoFiltered(filter)
+}
+
+// GetConfigInfoFilteredContext is same as GetConfigInfoFiltered but request is bound to ctx
+func GetConfigInfoFilteredContext(ctx context.Context, filter IPFilter) (ConfigInfo, error) {
+	return defaultClient.GetConfigInfoFilteredContext(ctx, filter)
+}
+
+// SetSlavePriority sets probe weights of slaves testing ip, slaves with higher priority are picked more often
+// by round-robin; slaves assigned to ip but missing in priorities get priority 0 (lowest weight, still used)
+func SetSlavePriority(ip string, priorities map[string]int) error {
+	return defaultClient.SetSlavePriority(ip, priorities)
+}
+
+// SetSlavePriorityContext is same as SetSlavePriority but request is bound to ctx
+func SetSlavePriorityContext(ctx context.Context, ip string, priorities map[string]int) error {
+	return defaultClient.SetSlavePriorityContext(ctx, ip, priorities)
+}
+
+// GetSlavePriority returns probe weights of all slaves testing ip, slaves without explicit priority are reported with 0
+func GetSlavePriority(ip string) (map[string]int, error) {
+	return defaultClient.GetSlavePriority(ip)
+}
+
+// GetSlavePriorityContext is same as GetSlavePriority but requests are bound to ctx
+func GetSlavePriorityContext(ctx context.Context, ip string) (map[string]int, error) {
+	return defaultClient.GetSlavePriorityContext(ctx, ip)
+}
+
+// AddIPsWithProgress is same as AddIPsRaw but sends ips in batches of batchSize (ordered by ip), failed batch doesn't stop
+// next ones; progress (if not nil) receives result of every batch and is closed on return, errors are collected into MultiError
+func AddIPsWithProgress(ips map[string]TestDesc, batchSize int, progress chan<- AddProgress) error {
+	return defaultClient.AddIPsWithProgress(ips, batchSize, progress)
+}
+
+// AddIPsWithProgressContext is same as AddIPsWithProgress but requests are bound to ctx
+func AddIPsWithProgressContext(ctx context.Context, ips map[string]TestDesc, batchSize int, progress chan<- AddProgress) error {
+	return defaultClient.AddIPsWithProgressContext(ctx, ips, batchSize, progress)
+}
+
+// GetSlavesStatusHistory returns ordered status changes of slave for period from-to. If server has no history endpoint
+// only current minute is reported: slave is "online" if it has some answered tests in last-minute stats, "offline" otherwise
+func GetSlavesStatusHistory(slave string, from, to time.Time) ([]SlaveStatusPoint, error) {
+	return defaultClient.GetSlavesStatusHistory(slave, from, to)
+}
+
+// GetSlavesStatusHistoryContext is same as GetSlavesStatusHistory but requests are bound to ctx
+func GetSlavesStatusHistoryContext(ctx context.Context, slave string, from, to time.Time) ([]SlaveStatusPoint, error) {
+	return defaultClient.GetSlavesStatusHistoryContext(ctx, slave, from, to)
+}
+
+// DeleteAllIPs removes all monitored IPs (URL tests are untouched) in batches, confirm must be "CONFIRM" otherwise
+// ErrNotConfirmed is returned; failed batch doesn't stop next ones, errors are collected into MultiError
+func DeleteAllIPs(confirm string) error {
+	return defaultClient.DeleteAllIPs(confirm)
+}
+
+// DeleteAllIPsContext is same as DeleteAllIPs but requests are bound to ctx
+func DeleteAllIPsContext(ctx context.Context, confirm string) error {
+	return defaultClient.DeleteAllIPsContext(ctx, confirm)
+}
+
+// DryRunDeleteAllIPs returns sorted list of IPs which would be removed by DeleteAllIPs
+func DryRunDeleteAllIPs() ([]string, error) {
+	return defaultClient.DryRunDeleteAllIPs()
+}
+
+// DryRunDeleteAllIPsContext is same as DryRunDeleteAllIPs but request is bound to ctx
+func DryRunDeleteAllIPsContext(ctx context.Context) ([]string, error) {
+	return defaultClient.DryRunDeleteAllIPsContext(ctx)
+}
+
+// GetSlaveStats returns aggregated statistics of slave
+func GetSlaveStats(slave string) (SlaveAggStats, error) {
+	return defaultClient.GetSlaveStats(slave)
+}
+
+// GetSlaveStatsContext is same as GetSlaveStats but request is bound to ctx
+func GetSlaveStatsContext(ctx context.Context, slave string) (SlaveAggStats, error) {
+	return defaultClient.GetSlaveStatsContext(ctx, slave)
+}
+
+// IPExists checks if ip is monitored
+func IPExists(ip string) (bool, error) {
+	return defaultClient.IPExists(ip)
+}
+
+// IPExistsContext is same as IPExists but request is bound to ctx
+func IPExistsContext(ctx context.Context, ip string) (bool, error) {
+	return defaultClient.IPExistsContext(ctx, ip)
+}
+
+// AddIPWithOptions is same as AddIP but checks existence of IP first if requested by opts
+func AddIPWithOptions(ip string, slaves []string, description string, groups []string, favorite bool, opts AddIPOptions) error {
+	return defaultClient.AddIPWithOptions(ip, slaves, description, groups, favorite, opts)
+}
+
+// AddIPWithOptionsContext is same as AddIPWithOptions but requests are bound to ctx
+func AddIPWithOptionsContext(ctx context.Context, ip string, slaves []string, description string, groups []string, favorite bool, opts AddIPOptions) error {
+	return defaultClient.AddIPWithOptionsContext(ctx, ip, slaves, description, groups, favorite, opts)
+}
+
+// DeleteIPWithOptions is same as DeleteIP but checks existence of IP first if requested by opts
+func DeleteIPWithOptions(ip string, opts DeleteIPOptions) error {
+	return defaultClient.DeleteIPWithOptions(ip, opts)
+}
+
+// DeleteIPWithOptionsContext is same as DeleteIPWithOptions but requests are bound to ctx
+func DeleteIPWithOptionsContext(ctx context.Context, ip string, opts DeleteIPOptions) error {
+	return defaultClient.DeleteIPWithOptionsContext(ctx, ip, opts)
+}
+
+// SearchIPs returns monitored IPs matching query ordered by ip, server has no search endpoint so filtering is done locally
+func SearchIPs(query IPQuery) ([]IPResult, error) {
+	return defaultClient.SearchIPs(query)
+}
+
+// SearchIPsContext is same as SearchIPs but request is bound to ctx
+func SearchIPsContext(ctx context.Context, query IPQuery) ([]IPResult, error) {
+	return defaultClient.SearchIPsContext(ctx, query)
+}
+
+// TagIP replaces tags (arbitrary key-value metadata) of monitored ip, empty map removes all tags.
+// Tags are stored as JSON suffix of description, use TestDesc.Tags and TestDesc.PlainDescription to read them
+func TagIP(ip string, tags map[string]string) error {
+	return defaultClient.TagIP(ip, tags)
+}
+
+// TagIPContext is same as TagIP but requests are bound to ctx
+func TagIPContext(ctx context.Context, ip string, tags map[string]string) error {
+	return defaultClient.TagIPContext(ctx, ip, tags)
+}
+
+// GetIPsByTag returns sorted list of monitored IPs having tag key with value
+func GetIPsByTag(key, value string) ([]string, error) {
+	return defaultClient.GetIPsByTag(key, value)
+}
+
+// GetIPsByTagContext is same as GetIPsByTag but request is bound to ctx
+func GetIPsByTagContext(ctx context.Context, key, value string) ([]string, error) {
+	return defaultClient.GetIPsByTagContext(ctx, key, value)
+}
+
+// GetSlavesGeoInfo returns geographic location of all slaves as known by server,
+// see cocogeo.LocalGeoEnrich for lookup in local GeoLite2 database
+func GetSlavesGeoInfo() (map[string]SlaveGeoInfo, error) {
+	return defaultClient.GetSlavesGeoInfo()
+}
+
+// GetSlavesGeoInfoContext is same as GetSlavesGeoInfo but request is bound to ctx
+func GetSlavesGeoInfoContext(ctx context.Context) (map[string]SlaveGeoInfo, error) {
+	return defaultClient.GetSlavesGeoInfoContext(ctx)
+}
+
+// GetAuditLog returns changes made in period from-to ordered by time, empty user/action match everything, zero to means now
+func GetAuditLog(from, to time.Time, user string, action string) ([]AuditEntry, error) {
+	return defaultClient.GetAuditLog(from, to, user, action)
+}
+
+// GetAuditLogContext is same as GetAuditLog but request is bound to ctx
+func GetAuditLogContext(ctx context.Context, from, to time.Time, user string, action string) ([]AuditEntry, error) {
+	return defaultClient.GetAuditLogContext(ctx, from, to, user, action)
+}
+
+// StreamAuditLog sends entries logged since from to ch and then polls for new ones every AuditPollInterval
+// until ctx is done (ctx.Err() is returned) or request fails; ch isn't closed
+func StreamAuditLog(ctx context.Context, from time.Time, ch chan<- AuditEntry) error {
+	return defaultClient.StreamAuditLog(ctx, from, ch)
+}
+
+// BulkUpdateDescriptions replaces descriptions of monitored IPs (ip -> new description) keeping rest of their configuration,
+// IPs are sent in batches (see WithBulkBatchSize); returned error is MultiError of failed batches (same as report.Failed)
+func BulkUpdateDescriptions(updates map[string]string) (BulkUpdateReport, error) {
+	return defaultClient.BulkUpdateDescriptions(updates)
+}
+
+// BulkUpdateDescriptionsContext is same as BulkUpdateDescriptions but requests are bound to ctx
+func BulkUpdateDescriptionsContext(ctx context.Context, updates map[string]string) (BulkUpdateReport, error) {
+	return defaultClient.BulkUpdateDescriptionsContext(ctx, updates)
+}
+
+// GetNotificationChannels returns all notification channels ordered by ID
+func GetNotificationChannels() ([]NotificationChannel, error) {
+	return defaultClient.GetNotificationChannels()
+}
+
+// GetNotificationChannelsContext is same as GetNotificationChannels but request is bound to ctx
+func GetNotificationChannelsContext(ctx context.Context) ([]NotificationChannel, error) {
+	return defaultClient.GetNotificationChannelsContext(ctx)
+}
+
+// AddNotificationChannel adds notification channel and returns its ID, ch.ID is prefixed by type unless it already is
+// ("slack:ops") and empty ch.ID means type with numeric suffix ("slack:1"); existing channel with same ID is replaced
+func AddNotificationChannel(ch NotificationChannel) (string, error) {
+	return defaultClient.AddNotificationChannel(ch)
+}
+
+// AddNotificationChannelContext is same as AddNotificationChannel but requests are bound to ctx
+func AddNotificationChannelContext(ctx context.Context, ch NotificationChannel) (string, error) {
+	return defaultClient.AddNotificationChannelContext(ctx, ch)
+}
+
+// DeleteNotificationChannel removes notification channel, ErrChannelNotFound if it doesn't exist
+func DeleteNotificationChannel(id string) error {
+	return defaultClient.DeleteNotificationChannel(id)
+}
+
+// DeleteNotificationChannelContext is same as DeleteNotificationChannel but requests are bound to ctx
+func DeleteNotificationChannelContext(ctx context.Context, id string) error {
+	return defaultClient.DeleteNotificationChannelContext(ctx, id)
+}
+
+// AssignNotificationChannelToGroup makes channel to notify about problems in group matching conditions; server keeps
+// thresholds per group so conditions are shared by all channels of group and replaced by every call
+func AssignNotificationChannelToGroup(group string, channelID string, conditions NotifyCondition) error {
+	return defaultClient.AssignNotificationChannelToGroup(group, channelID, conditions)
+}
+
+// AssignNotificationChannelToGroupContext is same as AssignNotificationChannelToGroup but requests are bound to ctx
+func AssignNotificationChannelToGroupContext(ctx context.Context, group string, channelID string, conditions NotifyCondition) error {
+	return defaultClient.AssignNotificationChannelToGroupContext(ctx, group, channelID, conditions)
+}
+
+// UnassignNotificationChannel stops channel notifying about group problems, ErrChannelNotFound if it isn't assigned
+func UnassignNotificationChannel(group, channelID string) error {
+	return defaultClient.UnassignNotificationChannel(group, channelID)
+}
+
+// UnassignNotificationChannelContext is same as UnassignNotificationChannel but requests are bound to ctx
+func UnassignNotificationChannelContext(ctx context.Context, group, channelID string) error {
+	return defaultClient.UnassignNotificationChannelContext(ctx, group, channelID)
+}
+
+// ListGroupNotifications returns notification channels assigned to group ordered by channel ID
+func ListGroupNotifications(group string) ([]GroupNotification, error) {
+	return defaultClient.ListGroupNotifications(group)
+}
+
+// ListGroupNotificationsContext is same as ListGroupNotifications but request is bound to ctx
+func ListGroupNotificationsContext(ctx context.Context, group string) ([]GroupNotification, error) {
+	return defaultClient.ListGroupNotificationsContext(ctx, group)
+}
+
+// SetCompactJSON asks server for not indented json in all future GET requests (adds compact=1 query parameter)
+func SetCompactJSON(compact bool) {
+	defaultClient.SetCompactJSON(compact)
+}
+
+// SetGzipEncoding makes all future requests ask for gzip compressed responses and decompress them
+func SetGzipEncoding(enabled bool) {
+	defaultClient.SetGzipEncoding(enabled)
+}
+
+// GetConfigChecksum returns checksum of current configuration from server or ConfigChecksum of GetConfigInfo
+// if server doesn't provide it; use CompareConfigs to compare masters as server algorithm may differ
+func GetConfigChecksum() (string, error) {
+	return defaultClient.GetConfigChecksum()
+}
+
+// GetConfigChecksumContext is same as GetConfigChecksum but requests are bound to ctx
+func GetConfigChecksumContext(ctx context.Context) (string, error) {
+	return defaultClient.GetConfigChecksumContext(ctx)
+}
+
+// GetFavoriteIPs returns sorted list of monitored IPs marked as favorite
+func GetFavoriteIPs() ([]string, error) {
+	return defaultClient.GetFavoriteIPs()
+}
+
+// GetFavoriteIPsContext is same as GetFavoriteIPs but request is bound to ctx
+func GetFavoriteIPsContext(ctx context.Context) ([]string, error) {
+	return defaultClient.GetFavoriteIPsContext(ctx)
+}
+
+// SetFavoriteIPs sets favorite flag of ips keeping rest of their configuration, IPs are sent in batches (see WithBulkBatchSize);
+// unmonitored IPs (ErrIPNotFound) and failed batches are reported by MultiError
+func SetFavoriteIPs(ips []string, favorite bool) error {
+	return defaultClient.SetFavoriteIPs(ips, favorite)
+}
+
+// SetFavoriteIPsContext is same as SetFavoriteIPs but requests are bound to ctx
+func SetFavoriteIPsContext(ctx context.Context, ips []string, favorite bool) error {
+	return defaultClient.SetFavoriteIPsContext(ctx, ips, favorite)
+}
+
+// ToggleFavorite flips favorite flag of monitored ip, ErrIPNotFound if IP isn't monitored
+func ToggleFavorite(ip string) error {
+	return defaultClient.ToggleFavorite(ip)
+}
+
+// ToggleFavoriteContext is same as ToggleFavorite but requests are bound to ctx
+func ToggleFavoriteContext(ctx context.Context, ip string) error {
+	return defaultClient.ToggleFavoriteContext(ctx, ip)
+}
+
+// GetSlavesTopTargets returns up to n IPs tested by slave with most probes during last minute, ties are ordered by IP
+func GetSlavesTopTargets(slave string, n int) ([]TargetProbeCount, error) {
+	return defaultClient.GetSlavesTopTargets(slave, n)
+}
+
+// GetSlavesTopTargetsContext is same as GetSlavesTopTargets but requests are bound to ctx
+func GetSlavesTopTargetsContext(ctx context.Context, slave string, n int) ([]TargetProbeCount, error) {
+	return defaultClient.GetSlavesTopTargetsContext(ctx, slave, n)
+}
+
+// GetSlavesBottomTargets returns up to n IPs tested by slave with least probes during last minute (IPs assigned
+// to slave but missing in stats have 0 probes), ties are ordered by IP
+func GetSlavesBottomTargets(slave string, n int) ([]TargetProbeCount, error) {
+	return defaultClient.GetSlavesBottomTargets(slave, n)
+}
+
+// GetSlavesBottomTargetsContext is same as GetSlavesBottomTargets but requests are bound to ctx
+func GetSlavesBottomTargetsContext(ctx context.Context, slave string, n int) ([]TargetProbeCount, error) {
+	return defaultClient.GetSlavesBottomTargetsContext(ctx, slave, n)
+}
+
+// ListIPsInCIDR returns sorted list of monitored IPs inside network cidr (like "10.0.0.0/8")
+func ListIPsInCIDR(cidr string) ([]string, error) {
+	return defaultClient.ListIPsInCIDR(cidr)
+}
+
+// ListIPsInCIDRContext is same as ListIPsInCIDR but request is bound to ctx
+func ListIPsInCIDRContext(ctx context.Context, cidr string) ([]string, error) {
+	return defaultClient.ListIPsInCIDRContext(ctx, cidr)
+}
+
+// GetCIDRCoverage reports how many addresses of network cidr are monitored
+func GetCIDRCoverage(cidr string) (CIDRCoverageReport, error) {
+	return defaultClient.GetCIDRCoverage(cidr)
+}
+
+// GetCIDRCoverageContext is same as GetCIDRCoverage but request is bound to ctx
+func GetCIDRCoverageContext(ctx context.Context, cidr string) (CIDRCoverageReport, error) {
+	return defaultClient.GetCIDRCoverageContext(ctx, cidr)
+}
+
+// DeleteIPsSafe deletes ips in batches (see WithBulkBatchSize) so one invalid IP doesn't block removal of all others,
+// returned error is MultiError of failed IPs (same as report.Failed)
+func DeleteIPsSafe(ips []string, strategy ErrorStrategy) (DeleteReport, error) {
+	return defaultClient.DeleteIPsSafe(ips, strategy)
+}
+
+// DeleteIPsSafeContext is same as DeleteIPsSafe but requests are bound to ctx
+func DeleteIPsSafeContext(ctx context.Context, ips []string, strategy ErrorStrategy) (DeleteReport, error) {
+	return defaultClient.DeleteIPsSafeContext(ctx, ips, strategy)
+}
+
+// SetLogger sets logger for all future requests, nil means NoopLogger
+func SetLogger(l Logger) {
+	defaultClient.SetLogger(l)
+}
+
+// GetAPIVersion returns version of master, result is cached by client after first successful call
+func GetAPIVersion() (APIVersion, error) {
+	return defaultClient.GetAPIVersion()
+}
+
+// GetAPIVersionContext is same as GetAPIVersion but request is bound to ctx
+func GetAPIVersionContext(ctx context.Context) (APIVersion, error) {
+	return defaultClient.GetAPIVersionContext(ctx)
+}
+
+// RequireVersion returns ErrVersionTooOld if master is older than min
+func RequireVersion(min APIVersion) error {
+	return defaultClient.RequireVersion(min)
+}
+
+// RequireVersionContext is same as RequireVersion but request is bound to ctx
+func RequireVersionContext(ctx context.Context, min APIVersion) error {
+	return defaultClient.RequireVersionContext(ctx, min)
+}
+
+// RebalanceSlaves moves IP tests from slaves outside of targetSlaves to target ones and then between target slaves
+// until count of tested IPs differs by at most one; at most maxReassignments moves are made (negative means no limit)
+func RebalanceSlaves(targetSlaves []string, maxReassignments int) (RebalanceReport, error) {
+	return defaultClient.RebalanceSlaves(targetSlaves, maxReassignments)
+}
+
+// RebalanceSlavesContext is same as RebalanceSlaves but requests are bound to ctx
+func RebalanceSlavesContext(ctx context.Context, targetSlaves []string, maxReassignments int) (RebalanceReport, error) {
+	return defaultClient.RebalanceSlavesContext(ctx, targetSlaves, maxReassignments)
+}
+
+// GetSlavesPacketLoss returns loss percentage of all tests of every slave aggregated over last minutes
+func GetSlavesPacketLoss(minutes int) (map[string]float64, error) {
+	return defaultClient.GetSlavesPacketLoss(minutes)
+}
+
+// GetSlavesPacketLossContext is same as GetSlavesPacketLoss but request is bound to ctx
+func GetSlavesPacketLossContext(ctx context.Context, minutes int) (map[string]float64, error) {
+	return defaultClient.GetSlavesPacketLossContext(ctx, minutes)
+}
+
+// GetSlavesPacketLossTimeSeries returns ordered per-minute loss percentage of all tests of slave for period from-to
+func GetSlavesPacketLossTimeSeries(slave string, from, to time.Time) ([]LossPoint, error) {
+	return defaultClient.GetSlavesPacketLossTimeSeries(slave, from, to)
+}
+
+// GetSlavesPacketLossTimeSeriesContext is same as GetSlavesPacketLossTimeSeries but request is bound to ctx
+func GetSlavesPacketLossTimeSeriesContext(ctx context.Context, slave string, from, to time.Time) ([]LossPoint, error) {
+	return defaultClient.GetSlavesPacketLossTimeSeriesContext(ctx, slave, from, to)
+}
+
+// ApplyConfigSnapshot applies snapshot with as few requests as possible: deletes go first, then adds, updates and group
+// changes are merged per IP and sent in batches (see WithBulkBatchSize); errors are reported per IP in report.Failed and
+// returned as MultiError. It's not atomic on server side, failed IPs have to be fixed by caller
+func ApplyConfigSnapshot(snapshot ConfigSnapshot) (SnapshotReport, error) {
+	return defaultClient.ApplyConfigSnapshot(snapshot)
+}
+
+// ApplyConfigSnapshotContext is same as ApplyConfigSnapshot but requests are bound to ctx
+func ApplyConfigSnapshotContext(ctx context.Context, snapshot ConfigSnapshot) (SnapshotReport, error) {
+	return defaultClient.ApplyConfigSnapshotContext(ctx, snapshot)
+}
+
+// GetSlaveConnectionCount returns count of active probe connections of slave
+func GetSlaveConnectionCount(slave string) (int, error) {
+	return defaultClient.GetSlaveConnectionCount(slave)
+}
+
+// GetSlaveConnectionCountContext is same as GetSlaveConnectionCount but request is bound to ctx
+func GetSlaveConnectionCountContext(ctx context.Context, slave string) (int, error) {
+	return defaultClient.GetSlaveConnectionCountContext(ctx, slave)
+}
+
+// GetAllSlavesConnectionCount returns count of active probe connections of every slave, it's taken from slaves status
+// if server reports it there, otherwise slaves are asked one by one (see WithConcurrency)
+func GetAllSlavesConnectionCount() (map[string]int, error) {
+	return defaultClient.GetAllSlavesConnectionCount()
+}
+
+// GetAllSlavesConnectionCountContext is same as GetAllSlavesConnectionCount but requests are bound to ctx
+func GetAllSlavesConnectionCountContext(ctx context.Context) (map[string]int, error) {
+	return defaultClient.GetAllSlavesConnectionCountContext(ctx)
+}
+
+// AlertIfSlaveOverloaded checks connection count of slave every poll interval (see WithOverloadPollInterval) and
+// sends alert to ch when it rises above threshold (and again when it drops back); it blocks until check fails or
+// forever, see context variant
+func AlertIfSlaveOverloaded(slave string, threshold int, ch chan<- OverloadAlert) error {
+	return defaultClient.AlertIfSlaveOverloaded(slave, threshold, ch)
+}
+
+// AlertIfSlaveOverloadedContext is same as AlertIfSlaveOverloaded but returns ctx.Err() when ctx is done
+func AlertIfSlaveOverloadedContext(ctx context.Context, slave string, threshold int, ch chan<- OverloadAlert) error {
+	return defaultClient.AlertIfSlaveOverloadedContext(ctx, slave, threshold, ch)
+}
+
+// ExportGroupStatsToCSV writes last minute stats of group measured by slave (see GroupLastStats) to w as csv,
+// RTT of unreachable targets is written as "N/A"
+func ExportGroupStatsToCSV(group string, slave string, w io.Writer) error {
+	return defaultClient.ExportGroupStatsToCSV(group, slave, w)
+}
+
+// ExportGroupStatsToCSVContext is same as ExportGroupStatsToCSV but request is bound to ctx
+func ExportGroupStatsToCSVContext(ctx context.Context, group string, slave string, w io.Writer) error {
+	return defaultClient.ExportGroupStatsToCSVContext(ctx, group, slave, w)
+}
+
+// ExportHistoricalStatsToCSV writes stats of group measured by slave for period from-to to w as csv, one line per target and period,
+// resolution is chosen by length of period
+func ExportHistoricalStatsToCSV(group string, slave string, from, to time.Time, w io.Writer) error {
+	return defaultClient.ExportHistoricalStatsToCSV(group, slave, from, to, w)
+}
+
+// ExportHistoricalStatsToCSVContext is same as ExportHistoricalStatsToCSV but request is bound to ctx
+func ExportHistoricalStatsToCSVContext(ctx context.Context, group string, slave string, from, to time.Time, w io.Writer) error {
+	return defaultClient.ExportHistoricalStatsToCSVContext(ctx, group, slave, from, to, w)
+}
+
+// SetDefaultGroups sets groups automatically added to groups of every IP created by AddIP or AddIPs
+// (groups passed by caller are kept, duplicates are skipped)
+func SetDefaultGroups(groups []string) {
+	defaultClient.SetDefaultGroups(groups)
+}
+
+// ClearDefaultGroups stops adding groups set by SetDefaultGroups
+func ClearDefaultGroups() {
+	defaultClient.ClearDefaultGroups()
+}
+
+// GetLatencyHeatmap returns time-of-day RTT distribution of ip measured by slave during last weeks
+func GetLatencyHeatmap(ip string, slave string, weeks int) (LatencyHeatmap, error) {
+	return defaultClient.GetLatencyHeatmap(ip, slave, weeks)
+}
+
+// GetLatencyHeatmapContext is same as GetLatencyHeatmap but requests are bound to ctx
+func GetLatencyHeatmapContext(ctx context.Context, ip string, slave string, weeks int) (LatencyHeatmap, error) {
+	return defaultClient.GetLatencyHeatmapContext(ctx, ip, slave, weeks)
+}
+
+// UpdateSlave changes name and/or address of slave keeping its IPs/tests assignments, only non-nil fields of patch are changed.
+//
+// Server-side update (PATCH /v1/slaves/{name}) is atomic. If server doesn't support it, address is changed in place
+// (POST /v1/slaves with "update": true) and then name by RenameSlave, so changing both isn't atomic. Slave is never
+// deleted to change its address as master drops IPs left without slaves, ErrSlaveUpdateUnsupported is returned
+// instead when server can't update address in place.
+func UpdateSlave(name string, patch SlavePatch) error {
+	return defaultClient.UpdateSlave(name, patch)
+}
+
+// UpdateSlaveContext is same as UpdateSlave but requests are bound to ctx
+func UpdateSlaveContext(ctx context.Context, name string, patch SlavePatch) error {
+	return defaultClient.UpdateSlaveContext(ctx, name, patch)
+}
+
+// GetNetworkPath runs traceroute from srcSlave to dstSlave and waits for its result
+func GetNetworkPath(srcSlave, dstSlave string) ([]PathHop, error) {
+	return defaultClient.GetNetworkPath(srcSlave, dstSlave)
+}
+
+// GetNetworkPathContext is same as GetNetworkPath but requests are bound to ctx
+func GetNetworkPathContext(ctx context.Context, srcSlave, dstSlave string) ([]PathHop, error) {
+	return defaultClient.GetNetworkPathContext(ctx, srcSlave, dstSlave)
+}
+
+// CompareNetworkPaths compares network paths from src to dst slave recorded nearest to moments a and b
+func CompareNetworkPaths(src, dst string, a, b time.Time) (PathDiff, error) {
+	return defaultClient.CompareNetworkPaths(src, dst, a, b)
+}
+
+// CompareNetworkPathsContext is same as CompareNetworkPaths but requests are bound to ctx
+func CompareNetworkPathsContext(ctx context.Context, src, dst string, a, b time.Time) (PathDiff, error) {
+	return defaultClient.CompareNetworkPathsContext(ctx, src, dst, a, b)
+}
+
+// DeleteIPsByGroup removes monitoring of IPs in group. In case of removeFromOtherGroups=false only IPs belonging
+// exclusively to group are deleted and IPs shared with other groups are just removed from group,
+// otherwise all IPs of group are deleted. Returned error is MultiError of failed IPs (same as report.Failed)
+func DeleteIPsByGroup(group string, removeFromOtherGroups bool) (DeleteByGroupReport, error) {
+	return defaultClient.DeleteIPsByGroup(group, removeFromOtherGroups)
+}
+
+// DeleteIPsByGroupContext is same as DeleteIPsByGroup but requests are bound to ctx
+func DeleteIPsByGroupContext(ctx context.Context, group string, removeFromOtherGroups bool) (DeleteByGroupReport, error) {
+	return defaultClient.DeleteIPsByGroupContext(ctx, group, removeFromOtherGroups)
+}
+
+// RegisterWebhook makes master call webhookURL (POST with json body) on events (EventSlaveOnline, EventSlaveOffline, EventIPAlert),
+// calls are signed by secret if it's not empty
+func RegisterWebhook(webhookURL string, events []string, secret string) (string, error) {
+	return defaultClient.RegisterWebhook(webhookURL, events, secret)
+}
+
+// RegisterWebhookContext is same as RegisterWebhook but request is bound to ctx
+func RegisterWebhookContext(ctx context.Context, webhookURL string, events []string, secret string) (string, error) {
+	return defaultClient.RegisterWebhookContext(ctx, webhookURL, events, secret)
+}
+
+// DeregisterWebhook removes webhook registered by RegisterWebhook
+func DeregisterWebhook(webhookID string) error {
+	return defaultClient.DeregisterWebhook(webhookID)
+}
+
+// DeregisterWebhookContext is same as DeregisterWebhook but request is bound to ctx
+func DeregisterWebhookContext(ctx context.Context, webhookID string) error {
+	return defaultClient.DeregisterWebhookContext(ctx, webhookID)
+}
+
+// ListWebhooks returns all registered webhooks (secrets are never returned by server)
+func ListWebhooks() ([]Webhook, error) {
+	return defaultClient.ListWebhooks()
+}
+
+// ListWebhooksContext is same as ListWebhooks but request is bound to ctx
+func ListWebhooksContext(ctx context.Context) ([]Webhook, error) {
+	return defaultClient.ListWebhooksContext(ctx)
+}
+
+// SetDryRun enables/disables dry-run mode: all non-GET requests are not sent but recorded to log (see GetDryRunLog)
+// and treated as successful, GET requests are still executed so changes are computed from real data
+func SetDryRun(enabled bool) {
+	defaultClient.SetDryRun(enabled)
+}
+
+// GetDryRunLog returns copy of requests skipped in dry-run mode ordered by time
+func GetDryRunLog() []DryRunEntry {
+	return defaultClient.GetDryRunLog()
+}
+
+// ClearDryRunLog removes all entries of dry-run log
+func ClearDryRunLog() {
+	defaultClient.ClearDryRunLog()
+}
+
+// GetAvailabilitySummary returns availability of ips during last periodDays (whole days in UTC) aggregated from
+// daily historical stats of all slaves, IPs which aren't monitored are reported by returned MultiError
+func GetAvailabilitySummary(ips []string, periodDays int) (map[string]AvailabilityRecord, error) {
+	return defaultClient.GetAvailabilitySummary(ips, periodDays)
+}
+
+// GetAvailabilitySummaryContext is same as GetAvailabilitySummary but requests are bound to ctx
+func GetAvailabilitySummaryContext(ctx context.Context, ips []string, periodDays int) (map[string]AvailabilityRecord, error) {
+	return defaultClient.GetAvailabilitySummaryContext(ctx, ips, periodDays)
+}
+
+// GetUserDetails returns account details of user, ErrUserNotFound if there is no such user
+func GetUserDetails(login string) (UserDetails, error) {
+	return defaultClient.GetUserDetails(login)
+}
+
+// GetUserDetailsContext is same as GetUserDetails but requests are bound to ctx
+func GetUserDetailsContext(ctx context.Context, login string) (UserDetails, error) {
+	return defaultClient.GetUserDetailsContext(ctx, login)
+}
+
+// SetUserRole changes role of existing user, ErrUserNotFound if there is no such user;
+// servers without role support accept only RoleOperator and RoleAdmin and can't change them without password
+// (ErrPasswordRequired is returned, see ChangeUserRoleWithPassword)
+func SetUserRole(login string, role string) error {
+	return defaultClient.SetUserRole(login, role)
+}
+
+// SetUserRoleContext is same as SetUserRole but requests are bound to ctx
+func SetUserRoleContext(ctx context.Context, login string, role string) error {
+	return defaultClient.SetUserRoleContext(ctx, login, role)
+}
+
+// ListRoles returns definitions of all roles known to server
+func ListRoles() ([]RoleDefinition, error) {
+	return defaultClient.ListRoles()
+}
+
+// ListRolesContext is same as ListRoles but request is bound to ctx
+func ListRolesContext(ctx context.Context) ([]RoleDefinition, error) {
+	return defaultClient.ListRolesContext(ctx)
+}
+
+// AddSlaveWithValidation is same as AddSlave but first checks that slave accepts TCP connections on ip:port within timeout,
+// returned error wraps ErrSlaveUnreachable and dial error if it doesn't (both can be tested by errors.Is/errors.As)
+func AddSlaveWithValidation(ip net.IP, port uint16, name string, copyFrom string, timeout time.Duration) error {
+	return defaultClient.AddSlaveWithValidation(ip, port, name, copyFrom, timeout)
+}
+
+// AddSlaveWithValidationContext is same as AddSlaveWithValidation but connection check and request are bound to ctx
+func AddSlaveWithValidationContext(ctx context.Context, ip net.IP, port uint16, name string, copyFrom string, timeout time.Duration) error {
+	return defaultClient.AddSlaveWithValidationContext(ctx, ip, port, name, copyFrom, timeout)
+}
+
+// GetIPReachabilityMatrix returns last-minute reachability of ips from slaves (all slaves if empty) queried in parallel,
+// every pair is present in result and is Unknown if there is no data; failed slaves are reported by returned MultiError
+func GetIPReachabilityMatrix(ips []string, slaves []string) (ReachabilityMatrix, error) {
+	return defaultClient.GetIPReachabilityMatrix(ips, slaves)
+}
+
+// GetIPReachabilityMatrixContext is same as GetIPReachabilityMatrix but requests are bound to ctx
+func GetIPReachabilityMatrixContext(ctx context.Context, ips []string, slaves []string) (ReachabilityMatrix, error) {
+	return defaultClient.GetIPReachabilityMatrixContext(ctx, ips, slaves)
+}
+
+// GetGroupHierarchy returns tree of all groups with root node named "", children and IPs are sorted;
+// parent groups without own IPs are present even if they are not configured
+func GetGroupHierarchy() (GroupNode, error) {
+	return defaultClient.GetGroupHierarchy()
+}
+
+// GetGroupHierarchyContext is same as GetGroupHierarchy but request is bound to ctx
+func GetGroupHierarchyContext(ctx context.Context) (GroupNode, error) {
+	return defaultClient.GetGroupHierarchyContext(ctx)
+}
+
+// GetSlavesLoadTimeSeries returns probe scheduling load of slave during period from-to ordered by time
+func GetSlavesLoadTimeSeries(slave string, from, to time.Time) ([]LoadPoint, error) {
+	return defaultClient.GetSlavesLoadTimeSeries(slave, from, to)
+}
+
+// GetSlavesLoadTimeSeriesContext is same as GetSlavesLoadTimeSeries but request is bound to ctx
+func GetSlavesLoadTimeSeriesContext(ctx context.Context, slave string, from, to time.Time) ([]LoadPoint, error) {
+	return defaultClient.GetSlavesLoadTimeSeriesContext(ctx, slave, from, to)
+}
+
+// AddIPFromHostname resolves A and AAAA records of hostname with resolver (net.DefaultResolver if nil), adds all
+// resulting IPs same way as AddIPs and returns them; empty description is replaced by hostname.
+// Failure of one record type is ignored if the other one was resolved
+func AddIPFromHostname(hostname string, slaves []string, description string, groups []string, favorite bool, resolver *net.Resolver) ([]string, error) {
+	return defaultClient.AddIPFromHostname(hostname, slaves, description, groups, favorite, resolver)
+}
+
+// AddIPFromHostnameContext is same as AddIPFromHostname but resolution and request are bound to ctx
+func AddIPFromHostnameContext(ctx context.Context, hostname string, slaves []string, description string, groups []string, favorite bool, resolver *net.Resolver) ([]string, error) {
+	return defaultClient.AddIPFromHostnameContext(ctx, hostname, slaves, description, groups, favorite, resolver)
+}
+
+// GetGroupLastStatsAll returns last-minute stats of IPs in group from all slaves queried in parallel together with
+// per-IP aggregates across slaves; failed slaves are skipped by aggregation and reported by returned MultiError
+func GetGroupLastStatsAll(group string) (AggregatedGroupStats, error) {
+	return defaultClient.GetGroupLastStatsAll(group)
+}
+
+// GetGroupLastStatsAllContext is same as GetGroupLastStatsAll but requests are bound to ctx
+func GetGroupLastStatsAllContext(ctx context.Context, group string) (AggregatedGroupStats, error) {
+	return defaultClient.GetGroupLastStatsAllContext(ctx, group)
+}
+
+// GetSlavesCapacity returns load and limits of all slaves
+func GetSlavesCapacity() (map[string]SlaveCapacity, error) {
+	return defaultClient.GetSlavesCapacity()
+}
+
+// GetSlavesCapacityContext is same as GetSlavesCapacity but request is bound to ctx
+func GetSlavesCapacityContext(ctx context.Context) (map[string]SlaveCapacity, error) {
+	return defaultClient.GetSlavesCapacityContext(ctx)
+}
+
+// IsSlaveAtCapacity reports if slave can't handle more IPs (CurrentIPs >= MaxIPs), slave without limit is never at capacity
+func IsSlaveAtCapacity(slave string) (bool, error) {
+	return defaultClient.IsSlaveAtCapacity(slave)
+}
+
+// IsSlaveAtCapacityContext is same as IsSlaveAtCapacity but request is bound to ctx
+func IsSlaveAtCapacityContext(ctx context.Context, slave string) (bool, error) {
+	return defaultClient.IsSlaveAtCapacityContext(ctx, slave)
+}
+
+// AddURLs adds (or replaces) several URLs (http tests) using only one API call, all URLs are validated before sending;
+// invalid URLs or URLs rejected by server are reported by returned MultiError
+func AddURLs(urls map[string]TestDesc) error {
+	return defaultClient.AddURLs(urls)
+}
+
+// AddURLsContext is same as AddURLs but request is bound to ctx
+func AddURLsContext(ctx context.Context, urls map[string]TestDesc) error {
+	return defaultClient.AddURLsContext(ctx, urls)
+}
+
+// GetSlaveDNSResolutionStats returns DNS resolution performance of slave.
+//
+// Stats are read from /v1/status/slaves/{slave}/dns which is not part of documented master API (only /v1/status/slaves
+// is), it's expected next to /v1/status/slaves/{slave}/connections on masters collecting DNS timing of http tests;
+// masters without it answer 404 which is returned as ErrDNSStatsUnsupported
+func GetSlaveDNSResolutionStats(slave string) (DNSStats, error) {
+	return defaultClient.GetSlaveDNSResolutionStats(slave)
+}
+
+// GetSlaveDNSResolutionStatsContext is same as GetSlaveDNSResolutionStats but request is bound to ctx
+func GetSlaveDNSResolutionStatsContext(ctx context.Context, slave string) (DNSStats, error) {
+	return defaultClient.GetSlaveDNSResolutionStatsContext(ctx, slave)
+}
+
+// CompareSlaveDNSStats loads DNS stats of slaves (all slaves if empty) in parallel and reports slaves which average
+// resolution time exceeds DNSOutlierFactor times mean of all compared slaves; failed slaves are skipped and reported
+// by returned MultiError
+func CompareSlaveDNSStats(slaves []string) (DNSComparison, error) {
+	return defaultClient.CompareSlaveDNSStats(slaves)
+}
+
+// CompareSlaveDNSStatsContext is same as CompareSlaveDNSStats but requests are bound to ctx
+func CompareSlaveDNSStatsContext(ctx context.Context, slaves []string) (DNSComparison, error) {
+	return defaultClient.CompareSlaveDNSStatsContext(ctx, slaves)
+}
+
+// ProbeNow makes slave probe ip immediately (outside of regular schedule) and waits for result,
+// ErrProbeTimeout is returned if result doesn't arrive within ProbeNowTimeout
+func ProbeNow(ip string, slave string) (ProbeResult, error) {
+	return defaultClient.ProbeNow(ip, slave)
+}
+
+// ProbeNowContext is same as ProbeNow but requests are bound to ctx
+func ProbeNowContext(ctx context.Context, ip string, slave string) (ProbeResult, error) {
+	return defaultClient.ProbeNowContext(ctx, ip, slave)
+}
+
+// SetTransportWrapper makes client send all requests through transport returned by wrap for its current transport
+// (used for instrumentation like tracing), wrapper is re-applied after transport changes; nil removes wrapper
+func SetTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) {
+	defaultClient.SetTransportWrapper(wrap)
+}
+
+// GetUnmonitoredIPs returns IPs of allKnownIPs (like list of active hosts from IPAM) which are inside any of cidrs
+// but are not monitored, in order of allKnownIPs without duplicates (also if networks overlap)
+func GetUnmonitoredIPs(cidrs []string, allKnownIPs []string) ([]string, error) {
+	return defaultClient.GetUnmonitoredIPs(cidrs, allKnownIPs)
+}
+
+// GetUnmonitoredIPsContext is same as GetUnmonitoredIPs but request is bound to ctx
+func GetUnmonitoredIPsContext(ctx context.Context, cidrs []string, allKnownIPs []string) ([]string, error) {
+	return defaultClient.GetUnmonitoredIPsContext(ctx, cidrs, allKnownIPs)
+}
+
+// CheckGroupConsistency validates groups configuration: every parent of nested group exists, IPs/URLs reference
+// only valid and configured groups, configured groups are not empty and tests/auto groups use only existing slaves.
+// Issues are ordered by severity (errors first), code and description
+func CheckGroupConsistency() ([]ConsistencyIssue, error) {
+	return defaultClient.CheckGroupConsistency()
+}
+
+// CheckGroupConsistencyContext is same as CheckGroupConsistency but requests are bound to ctx
+func CheckGroupConsistencyContext(ctx context.Context) ([]ConsistencyIssue, error) {
+	return defaultClient.CheckGroupConsistencyContext(ctx)
+}
@@ -0,0 +1,399 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// TestChange describes modification of one IP/URL test
+type TestChange struct {
+	Old    TestDesc `json:"old"`
+	New    TestDesc `json:"new"`
+	Fields []string `json:"fields"` // names of changed TestDesc fields
+}
+
+// GroupConfigChange describes modification of one group settings
+type GroupConfigChange struct {
+	Old GroupConfig `json:"old"`
+	New GroupConfig `json:"new"`
+}
+
+// ValueChange describes modification of one setting, slave address or user role
+type ValueChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// ConfigDiffResult is result of ConfigDiff, all entries are from point of view of changing a into b
+type ConfigDiffResult struct {
+	AddedIPs         map[string]TestDesc          `json:"addedIPs,omitempty"`
+	RemovedIPs       map[string]TestDesc          `json:"removedIPs,omitempty"`
+	ModifiedIPs      map[string]TestChange        `json:"modifiedIPs,omitempty"`
+	AddedURLs        map[string]TestDesc          `json:"addedURLs,omitempty"`
+	RemovedURLs      map[string]TestDesc          `json:"removedURLs,omitempty"`
+	ModifiedURLs     map[string]TestChange        `json:"modifiedURLs,omitempty"`
+	AddedGroups      []string                     `json:"addedGroups,omitempty"`
+	RemovedGroups    []string                     `json:"removedGroups,omitempty"`
+	ModifiedGroups   map[string]GroupConfigChange `json:"modifiedGroups,omitempty"`
+	ModifiedSettings map[string]ValueChange       `json:"modifiedSettings,omitempty"` // global ping/http settings like "ping.timeout"
+
+	// slaves and users aren't part of ConfigInfo, so they are filled only by DiffClients and DiffFromURL
+	AddedSlaves    map[string]string      `json:"addedSlaves,omitempty"`   // name -> ip:port
+	RemovedSlaves  map[string]string      `json:"removedSlaves,omitempty"` // name -> ip:port
+	ModifiedSlaves map[string]ValueChange `json:"modifiedSlaves,omitempty"`
+	AddedUsers     map[string]bool        `json:"addedUsers,omitempty"`   // login -> admin
+	RemovedUsers   map[string]bool        `json:"removedUsers,omitempty"` // login -> admin
+	ModifiedUsers  map[string]ValueChange `json:"modifiedUsers,omitempty"`
+}
+
+// Empty returns true if there are no differences
+func (d ConfigDiffResult) Empty() bool {
+	return 0 == len(d.AddedIPs)+len(d.RemovedIPs)+len(d.ModifiedIPs)+
+		len(d.AddedURLs)+len(d.RemovedURLs)+len(d.ModifiedURLs)+
+		len(d.AddedGroups)+len(d.RemovedGroups)+len(d.ModifiedGroups)+len(d.ModifiedSettings)+
+		len(d.AddedSlaves)+len(d.RemovedSlaves)+len(d.ModifiedSlaves)+
+		len(d.AddedUsers)+len(d.RemovedUsers)+len(d.ModifiedUsers)
+}
+
+// Summary returns human-readable multi-line description of differences
+func (d ConfigDiffResult) Summary() string {
+	if d.Empty() {
+		return "no differences\n"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "ips: %d added, %d removed, %d modified\n", len(d.AddedIPs), len(d.RemovedIPs), len(d.ModifiedIPs))
+	fmt.Fprintf(&b, "urls: %d added, %d removed, %d modified\n", len(d.AddedURLs), len(d.RemovedURLs), len(d.ModifiedURLs))
+	fmt.Fprintf(&b, "groups: %d added, %d removed, %d modified\n", len(d.AddedGroups), len(d.RemovedGroups), len(d.ModifiedGroups))
+	fmt.Fprintf(&b, "slaves: %d added, %d removed, %d modified\n", len(d.AddedSlaves), len(d.RemovedSlaves), len(d.ModifiedSlaves))
+	fmt.Fprintf(&b, "users: %d added, %d removed, %d modified\n", len(d.AddedUsers), len(d.RemovedUsers), len(d.ModifiedUsers))
+	fmt.Fprintf(&b, "settings: %d modified\n", len(d.ModifiedSettings))
+
+	for _, target := range sortedKeys(d.AddedIPs) {
+		fmt.Fprintf(&b, "+ %s\n", target)
+	}
+	for _, target := range sortedKeys(d.AddedURLs) {
+		fmt.Fprintf(&b, "+ %s\n", target)
+	}
+	for _, target := range sortedKeys(d.RemovedIPs) {
+		fmt.Fprintf(&b, "- %s\n", target)
+	}
+	for _, target := range sortedKeys(d.RemovedURLs) {
+		fmt.Fprintf(&b, "- %s\n", target)
+	}
+	for _, changes := range []map[string]TestChange{d.ModifiedIPs, d.ModifiedURLs} {
+		targets := make([]string, 0, len(changes))
+		for target := range changes {
+			targets = append(targets, target)
+		}
+		sort.Strings(targets)
+		for _, target := range targets {
+			fmt.Fprintf(&b, "~ %s (%s)\n", target, strings.Join(changes[target].Fields, ", "))
+		}
+	}
+	for _, group := range d.AddedGroups {
+		fmt.Fprintf(&b, "+ group %s\n", group)
+	}
+	for _, group := range d.RemovedGroups {
+		fmt.Fprintf(&b, "- group %s\n", group)
+	}
+	groups := make([]string, 0, len(d.ModifiedGroups))
+	for group := range d.ModifiedGroups {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		fmt.Fprintf(&b, "~ group %s\n", group)
+	}
+	for _, slave := range sortedStringKeys(d.AddedSlaves) {
+		fmt.Fprintf(&b, "+ slave %s (%s)\n", slave, d.AddedSlaves[slave])
+	}
+	for _, slave := range sortedStringKeys(d.RemovedSlaves) {
+		fmt.Fprintf(&b, "- slave %s (%s)\n", slave, d.RemovedSlaves[slave])
+	}
+	for _, slave := range sortedChangeKeys(d.ModifiedSlaves) {
+		fmt.Fprintf(&b, "~ slave %s (%v -> %v)\n", slave, d.ModifiedSlaves[slave].Old, d.ModifiedSlaves[slave].New)
+	}
+	for _, login := range sortedBoolKeys(d.AddedUsers) {
+		fmt.Fprintf(&b, "+ user %s (admin: %v)\n", login, d.AddedUsers[login])
+	}
+	for _, login := range sortedBoolKeys(d.RemovedUsers) {
+		fmt.Fprintf(&b, "- user %s (admin: %v)\n", login, d.RemovedUsers[login])
+	}
+	for _, login := range sortedChangeKeys(d.ModifiedUsers) {
+		fmt.Fprintf(&b, "~ user %s (admin: %v -> %v)\n", login, d.ModifiedUsers[login].Old, d.ModifiedUsers[login].New)
+	}
+	for _, setting := range sortedChangeKeys(d.ModifiedSettings) {
+		fmt.Fprintf(&b, "~ %s (%v -> %v)\n", setting, d.ModifiedSettings[setting].Old, d.ModifiedSettings[setting].New)
+	}
+
+	return b.String()
+}
+
+// ConfigDiff compares two configurations and returns what has to be changed to get b from a,
+// slaves and users aren't part of ConfigInfo so use DiffClients to compare them too
+func ConfigDiff(a, b ConfigInfo) ConfigDiffResult {
+	var result ConfigDiffResult
+
+	result.AddedIPs, result.RemovedIPs, result.ModifiedIPs = diffTests(a.Ping.IPs, b.Ping.IPs)
+	result.AddedURLs, result.RemovedURLs, result.ModifiedURLs = diffTests(a.HTTP.URLs, b.HTTP.URLs)
+
+	result.ModifiedGroups = map[string]GroupConfigChange{}
+	for group, newConfig := range b.Groups {
+		oldConfig, ok := a.Groups[group]
+		if !ok {
+			result.AddedGroups = append(result.AddedGroups, group)
+			continue
+		}
+		if !reflect.DeepEqual(oldConfig, newConfig) {
+			result.ModifiedGroups[group] = GroupConfigChange{Old: oldConfig, New: newConfig}
+		}
+	}
+	for group := range a.Groups {
+		if _, ok := b.Groups[group]; !ok {
+			result.RemovedGroups = append(result.RemovedGroups, group)
+		}
+	}
+	sort.Strings(result.AddedGroups)
+	sort.Strings(result.RemovedGroups)
+
+	result.ModifiedSettings = map[string]ValueChange{}
+	for _, setting := range []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"ping.timeout", a.Ping.Timeout, b.Ping.Timeout},
+		{"ping.interval", a.Ping.Interval, b.Ping.Interval},
+		{"ping.slowdown", a.Ping.Slowdown, b.Ping.Slowdown},
+		{"ping.slowEvery", a.Ping.SlowEvery, b.Ping.SlowEvery},
+		{"http.timeout", a.HTTP.Timeout, b.HTTP.Timeout},
+		{"http.interval", a.HTTP.Interval, b.HTTP.Interval},
+	} {
+		if setting.old != setting.new {
+			result.ModifiedSettings[setting.name] = ValueChange{Old: setting.old, New: setting.new}
+		}
+	}
+
+	return result
+}
+
+// DiffClients loads configurations, slaves and users from two masters and compares them
+func DiffClients(a, b *Client) (ConfigDiffResult, error) {
+	return DiffClientsContext(context.Background(), a, b)
+}
+
+// DiffClientsContext is same as DiffClients but requests are bound to ctx
+func DiffClientsContext(ctx context.Context, a, b *Client) (ConfigDiffResult, error) {
+	configA, err := a.GetConfigInfoContext(ctx)
+	if nil != err {
+		return ConfigDiffResult{}, err
+	}
+	configB, err := b.GetConfigInfoContext(ctx)
+	if nil != err {
+		return ConfigDiffResult{}, err
+	}
+	result := ConfigDiff(configA, configB)
+
+	slavesA, err := a.GetSlavesAddrsContext(ctx)
+	if nil != err {
+		return ConfigDiffResult{}, err
+	}
+	slavesB, err := b.GetSlavesAddrsContext(ctx)
+	if nil != err {
+		return ConfigDiffResult{}, err
+	}
+	result.AddedSlaves, result.RemovedSlaves, result.ModifiedSlaves = diffSlaves(slavesA, slavesB)
+
+	usersA, err := a.ListUsersContext(ctx)
+	if nil != err {
+		return ConfigDiffResult{}, err
+	}
+	usersB, err := b.ListUsersContext(ctx)
+	if nil != err {
+		return ConfigDiffResult{}, err
+	}
+	result.AddedUsers, result.RemovedUsers, result.ModifiedUsers = diffUsers(usersA, usersB)
+
+	return result, nil
+}
+
+// DiffFromURL loads configurations, slaves and users from two masters using separate credentials and compares them,
+// opts are applied to both clients
+func DiffFromURL(urlA, usernameA, passwordA, urlB, usernameB, passwordB string, opts ...Option) (ConfigDiffResult, error) {
+	return DiffClients(New(urlA, usernameA, passwordA, opts...), New(urlB, usernameB, passwordB, opts...))
+}
+
+// diffSlaves compares two name -> ip:port slave lists
+func diffSlaves(a, b map[string]string) (added, removed map[string]string, modified map[string]ValueChange) {
+	added = map[string]string{}
+	removed = map[string]string{}
+	modified = map[string]ValueChange{}
+
+	for slave, newAddr := range b {
+		oldAddr, ok := a[slave]
+		if !ok {
+			added[slave] = newAddr
+		} else if oldAddr != newAddr {
+			modified[slave] = ValueChange{Old: oldAddr, New: newAddr}
+		}
+	}
+	for slave, oldAddr := range a {
+		if _, ok := b[slave]; !ok {
+			removed[slave] = oldAddr
+		}
+	}
+
+	return added, removed, modified
+}
+
+// diffUsers compares two login -> admin user lists
+func diffUsers(a, b map[string]bool) (added, removed map[string]bool, modified map[string]ValueChange) {
+	added = map[string]bool{}
+	removed = map[string]bool{}
+	modified = map[string]ValueChange{}
+
+	for login, newAdmin := range b {
+		oldAdmin, ok := a[login]
+		if !ok {
+			added[login] = newAdmin
+		} else if oldAdmin != newAdmin {
+			modified[login] = ValueChange{Old: oldAdmin, New: newAdmin}
+		}
+	}
+	for login, oldAdmin := range a {
+		if _, ok := b[login]; !ok {
+			removed[login] = oldAdmin
+		}
+	}
+
+	return added, removed, modified
+}
+
+// diffTests compares two sets of tests
+func diffTests(a, b map[string]TestDesc) (added, removed map[string]TestDesc, modified map[string]TestChange) {
+	added = map[string]TestDesc{}
+	removed = map[string]TestDesc{}
+	modified = map[string]TestChange{}
+
+	for target, newDesc := range b {
+		oldDesc, ok := a[target]
+		if !ok {
+			added[target] = newDesc
+			continue
+		}
+		if fields := changedFields(oldDesc, newDesc); 0 != len(fields) {
+			modified[target] = TestChange{Old: oldDesc, New: newDesc, Fields: fields}
+		}
+	}
+
+	for target, oldDesc := range a {
+		if _, ok := b[target]; !ok {
+			removed[target] = oldDesc
+		}
+	}
+
+	return added, removed, modified
+}
+
+// changedFields returns names of TestDesc fields that differs, order of groups and slaves is ignored
+func changedFields(a, b TestDesc) []string {
+	fields := []string{}
+
+	if !sameSet(a.Groups, b.Groups) {
+		fields = append(fields, "Groups")
+	}
+	if a.Description != b.Description {
+		fields = append(fields, "Description")
+	}
+	if a.Favorite != b.Favorite {
+		fields = append(fields, "Favorite")
+	}
+	if !sameSet(a.Slaves, b.Slaves) {
+		fields = append(fields, "Slaves")
+	}
+	if !a.AutoAdded.Equal(b.AutoAdded) {
+		fields = append(fields, "AutoAdded")
+	}
+	if a.AS != b.AS {
+		fields = append(fields, "AS")
+	}
+	if a.PacketCount != b.PacketCount {
+		fields = append(fields, "PacketCount")
+	}
+	if a.PacketSizeBytes != b.PacketSizeBytes {
+		fields = append(fields, "PacketSizeBytes")
+	}
+	if a.ProbeIntervalSec != b.ProbeIntervalSec {
+		fields = append(fields, "ProbeIntervalSec")
+	}
+
+	return fields
+}
+
+// sameSet checks if both lists contains same strings ignoring order and duplicates
+func sameSet(a, b []string) bool {
+	set := make(map[string]bool, len(a))
+	for _, s := range a {
+		set[s] = true
+	}
+	for _, s := range b {
+		if !set[s] {
+			return false
+		}
+	}
+
+	set = make(map[string]bool, len(b))
+	for _, s := range b {
+		set[s] = true
+	}
+	for _, s := range a {
+		if !set[s] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortedKeys returns sorted keys of tests map
+func sortedKeys(tests map[string]TestDesc) []string {
+	keys := make([]string, 0, len(tests))
+	for key := range tests {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedStringKeys returns sorted keys of m
+func sortedStringKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedBoolKeys returns sorted keys of m
+func sortedBoolKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// sortedChangeKeys returns sorted keys of m
+func sortedChangeKeys(m map[string]ValueChange) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
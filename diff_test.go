@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+)
+
+// masterServer serves config, slaves and users of one master protected by basic auth
+func masterServer(t *testing.T, password string, config ConfigInfo, slaves map[string]string, users map[string]bool) *Client {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if _, pass, ok := r.BasicAuth(); !ok || password != pass {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/config":
+			writeTestJSON(w, config)
+		case "/v1/slaves":
+			writeTestJSON(w, slaves)
+		case "/v1/users":
+			writeTestJSON(w, users)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestDiffClients(t *testing.T) {
+	var configA, configB ConfigInfo
+	configA.Ping.IPs = map[string]TestDesc{"1.1.1.1": {Description: "a"}}
+	configA.Ping.Timeout = 1
+	configA.Groups = map[string]GroupConfig{"DNS->": {IsPublic: false}, "OLD->": {}}
+	configB.Ping.IPs = map[string]TestDesc{"1.1.1.1": {Description: "b"}, "8.8.8.8": {}}
+	configB.Ping.Timeout = 2
+	configB.Groups = map[string]GroupConfig{"DNS->": {IsPublic: true}, "NEW->": {}}
+
+	a := masterServer(t, "secret", configA,
+		map[string]string{"PRAGUE": "10.0.0.1:3030", "LONDON": "10.0.0.2:3030"},
+		map[string]bool{"admin": true, "viewer": false, "old": false})
+	b := masterServer(t, "secret", configB,
+		map[string]string{"PRAGUE": "10.0.0.9:3030", "PARIS": "10.0.0.3:3030"},
+		map[string]bool{"admin": true, "viewer": true, "new": false})
+
+	diff, err := DiffClients(a, b)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	if 1 != len(diff.AddedIPs) || 1 != len(diff.ModifiedIPs) || 0 != len(diff.RemovedIPs) {
+		t.Errorf("unexpected ip changes %+v", diff)
+	}
+	if 1 != len(diff.AddedGroups) || 1 != len(diff.RemovedGroups) || !diff.ModifiedGroups["DNS->"].New.IsPublic {
+		t.Errorf("unexpected group changes %+v %+v %+v", diff.AddedGroups, diff.RemovedGroups, diff.ModifiedGroups)
+	}
+	if change := diff.ModifiedSettings["ping.timeout"]; float32(1) != change.Old || float32(2) != change.New || 1 != len(diff.ModifiedSettings) {
+		t.Errorf("unexpected settings changes %+v", diff.ModifiedSettings)
+	}
+	if "10.0.0.3:3030" != diff.AddedSlaves["PARIS"] || "10.0.0.2:3030" != diff.RemovedSlaves["LONDON"] ||
+		"10.0.0.9:3030" != diff.ModifiedSlaves["PRAGUE"].New || 1 != len(diff.ModifiedSlaves) {
+		t.Errorf("unexpected slave changes %+v %+v %+v", diff.AddedSlaves, diff.RemovedSlaves, diff.ModifiedSlaves)
+	}
+	if _, ok := diff.AddedUsers["new"]; !ok || 1 != len(diff.AddedUsers) || 1 != len(diff.RemovedUsers) ||
+		true != diff.ModifiedUsers["viewer"].New || 1 != len(diff.ModifiedUsers) {
+		t.Errorf("unexpected user changes %+v %+v %+v", diff.AddedUsers, diff.RemovedUsers, diff.ModifiedUsers)
+	}
+	if diff.Empty() {
+		t.Error("diff shouldn't be empty")
+	}
+}
+
+func TestDiffClientsAuthFailure(t *testing.T) {
+	var config ConfigInfo
+	a := masterServer(t, "secret", config, nil, nil)
+	b := masterServer(t, "other", config, nil, nil)
+
+	if _, err := DiffClients(a, b); nil == err {
+		t.Fatal("expected error for wrong credentials")
+	}
+}
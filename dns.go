@@ -0,0 +1,121 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// ResolverDNSStats is DNS resolution performance of one resolver used by slave
+type ResolverDNSStats struct {
+	Queries         int     `json:"queries"`
+	AvgResolutionMs float64 `json:"avgResolutionMs"`
+	P95ResolutionMs float64 `json:"p95ResolutionMs"`
+	FailureRate     float64 `json:"failureRate"` // part of failed queries in range 0..1
+}
+
+// DNSStats is DNS resolution performance of slave for http tests
+type DNSStats struct {
+	AvgResolutionMs float64                     `json:"avgResolutionMs"`
+	P95ResolutionMs float64                     `json:"p95ResolutionMs"`
+	FailureRate     float64                     `json:"failureRate"` // part of failed queries in range 0..1
+	Resolvers       map[string]ResolverDNSStats `json:"resolvers"`   // by resolver IP
+}
+
+// DNSOutlierFactor is how many times average resolution time of slave has to exceed mean of all compared slaves
+// (DNSComparison.MeanResolutionMs) to be reported as outlier by CompareSlaveDNSStats
+var DNSOutlierFactor = 2.0
+
+// DNSComparison is result of CompareSlaveDNSStats
+type DNSComparison struct {
+	Stats            map[string]DNSStats // by slave
+	MeanResolutionMs float64             // mean of AvgResolutionMs of all compared slaves
+	Outliers         []string            // slaves with abnormally slow DNS, sorted
+}
+
+// GetSlaveDNSResolutionStats returns DNS resolution performance of slave.
+//
+// Stats are read from /v1/status/slaves/{slave}/dns which is not part of documented master API (only /v1/status/slaves
+// is), it's expected next to /v1/status/slaves/{slave}/connections on masters collecting DNS timing of http tests;
+// masters without it answer 404 which is returned as ErrDNSStatsUnsupported
+func (c *Client) GetSlaveDNSResolutionStats(slave string) (DNSStats, error) {
+	return c.GetSlaveDNSResolutionStatsContext(context.Background(), slave)
+}
+
+// GetSlaveDNSResolutionStatsContext is same as GetSlaveDNSResolutionStats but request is bound to ctx
+func (c *Client) GetSlaveDNSResolutionStatsContext(ctx context.Context, slave string) (DNSStats, error) {
+	if "" == slave {
+		return DNSStats{}, ErrEmptySlaveName
+	}
+
+	var stats DNSStats
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/status/slaves/"+url.PathEscape(slave)+"/dns", nil, "", nil), &stats)
+	if http.StatusNotFound == status || http.StatusMethodNotAllowed == status {
+		return DNSStats{}, ErrDNSStatsUnsupported
+	}
+	return stats, err
+}
+
+// CompareSlaveDNSStats loads DNS stats of slaves (all slaves if empty) in parallel and reports slaves which average
+// resolution time exceeds DNSOutlierFactor times mean of all compared slaves; failed slaves are skipped and reported
+// by returned MultiError
+func (c *Client) CompareSlaveDNSStats(slaves []string) (DNSComparison, error) {
+	return c.CompareSlaveDNSStatsContext(context.Background(), slaves)
+}
+
+// CompareSlaveDNSStatsContext is same as CompareSlaveDNSStats but requests are bound to ctx
+func (c *Client) CompareSlaveDNSStatsContext(ctx context.Context, slaves []string) (DNSComparison, error) {
+	if 0 == len(slaves) {
+		var err error
+		slaves, err = c.GetSlaveListContext(ctx)
+		if nil != err {
+			return DNSComparison{}, err
+		}
+	}
+
+	var mu sync.Mutex
+	stats := make(map[string]DNSStats, len(slaves))
+	errs := MultiError{}
+
+	c.forEach(slaves, func(slave string) {
+		s, err := c.GetSlaveDNSResolutionStatsContext(ctx, slave)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if nil != err {
+			errs[slave] = err
+			return
+		}
+		stats[slave] = s
+	})
+
+	return compareDNSStats(stats), errs.errOrNil()
+}
+
+// compareDNSStats finds outliers comparing every slave with mean of all slaves
+func compareDNSStats(stats map[string]DNSStats) DNSComparison {
+	result := DNSComparison{
+		Stats:    stats,
+		Outliers: []string{},
+	}
+	if 0 == len(stats) {
+		return result
+	}
+
+	sum := 0.0
+	for _, s := range stats {
+		sum += s.AvgResolutionMs
+	}
+	result.MeanResolutionMs = sum / float64(len(stats))
+
+	for slave, s := range stats {
+		if s.AvgResolutionMs > result.MeanResolutionMs*DNSOutlierFactor {
+			result.Outliers = append(result.Outliers, slave)
+		}
+	}
+	sort.Strings(result.Outliers)
+
+	return result
+}
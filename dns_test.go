@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// dnsStatsServer serves slaves list and DNS stats of slaves, slaves without stats answer 404
+type dnsStatsServer struct {
+	stats map[string]DNSStats
+}
+
+func (s *dnsStatsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case "/v1/slaves" == r.URL.Path:
+		slaves := map[string]string{"BROKEN": "10.0.0.99:3030"}
+		for slave := range s.stats {
+			slaves[slave] = "10.0.0.1:3030"
+		}
+		writeTestJSON(w, slaves)
+	case strings.HasPrefix(r.URL.Path, "/v1/status/slaves/") && strings.HasSuffix(r.URL.Path, "/dns"):
+		stats, ok := s.stats[strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/status/slaves/"), "/dns")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeTestJSON(w, stats)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func dnsFixtureStats(avg ...float64) map[string]DNSStats {
+	names := []string{"PRAGUE", "LONDON", "PARIS", "TOKYO"}
+	stats := map[string]DNSStats{}
+	for i, ms := range avg {
+		stats[names[i]] = DNSStats{AvgResolutionMs: ms, P95ResolutionMs: ms * 3}
+	}
+	return stats
+}
+
+func TestGetSlaveDNSResolutionStats(t *testing.T) {
+	expected := DNSStats{
+		AvgResolutionMs: 12.5,
+		P95ResolutionMs: 40,
+		FailureRate:     0.01,
+		Resolvers: map[string]ResolverDNSStats{
+			"1.1.1.1": {Queries: 900, AvgResolutionMs: 10, P95ResolutionMs: 30},
+			"8.8.8.8": {Queries: 100, AvgResolutionMs: 35, P95ResolutionMs: 120, FailureRate: 0.1},
+		},
+	}
+	c := newTestClient(t, (&dnsStatsServer{stats: map[string]DNSStats{"PRAGUE": expected}}).ServeHTTP)
+
+	stats, err := c.GetSlaveDNSResolutionStats("PRAGUE")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(expected, stats) {
+		t.Fatalf("expected %+v, got %+v", expected, stats)
+	}
+
+	if _, err := c.GetSlaveDNSResolutionStats("BROKEN"); !errors.Is(err, ErrDNSStatsUnsupported) {
+		t.Errorf("expected ErrDNSStatsUnsupported, got %v", err)
+	}
+	if _, err := c.GetSlaveDNSResolutionStats(""); !errors.Is(err, ErrEmptySlaveName) {
+		t.Errorf("expected ErrEmptySlaveName, got %v", err)
+	}
+}
+
+func TestCompareSlaveDNSStatsSlowSlave(t *testing.T) {
+	// TOKYO resolves ten times slower than the others
+	s := &dnsStatsServer{stats: dnsFixtureStats(10, 12, 8, 110)}
+	c := newTestClient(t, s.ServeHTTP)
+
+	comparison, err := c.CompareSlaveDNSStats(nil)
+	multi, ok := err.(MultiError)
+	if !ok || 1 != len(multi) || !errors.Is(multi["BROKEN"], ErrDNSStatsUnsupported) {
+		t.Fatalf("expected MultiError for BROKEN, got %v", err)
+	}
+
+	if 35 != comparison.MeanResolutionMs {
+		t.Errorf("expected mean 35, got %v", comparison.MeanResolutionMs)
+	}
+	if !reflect.DeepEqual([]string{"TOKYO"}, comparison.Outliers) {
+		t.Errorf("expected TOKYO to be outlier, got %v", comparison.Outliers)
+	}
+	if 4 != len(comparison.Stats) || 110 != comparison.Stats["TOKYO"].AvgResolutionMs {
+		t.Errorf("unexpected stats %+v", comparison.Stats)
+	}
+}
+
+func TestCompareSlaveDNSStatsGroupMean(t *testing.T) {
+	// PARIS is 2.5 times slower than mean of the others but not 2 times slower than group mean 15
+	c := newTestClient(t, (&dnsStatsServer{stats: dnsFixtureStats(10, 10, 25)}).ServeHTTP)
+
+	comparison, err := c.CompareSlaveDNSStats([]string{"PRAGUE", "LONDON", "PARIS"})
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 15 != comparison.MeanResolutionMs || 0 != len(comparison.Outliers) {
+		t.Errorf("expected mean 15 without outliers, got %v %v", comparison.MeanResolutionMs, comparison.Outliers)
+	}
+
+	// same speed everywhere and single slave are never outliers
+	for _, stats := range []map[string]DNSStats{dnsFixtureStats(20, 20, 20, 20), dnsFixtureStats(500)} {
+		comparison = compareDNSStats(stats)
+		if 0 != len(comparison.Outliers) {
+			t.Errorf("%v: unexpected outliers %v", stats, comparison.Outliers)
+		}
+	}
+}
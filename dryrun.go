@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+)
+
+// DryRunEntry is request skipped because of dry-run mode
+type DryRunEntry struct {
+	Method string
+	URL    string
+	Body   string
+}
+
+// SetDryRun enables/disables dry-run mode: all non-GET requests are not sent but recorded to log (see GetDryRunLog)
+// and treated as successful, GET requests are still executed so changes are computed from real data
+func (c *Client) SetDryRun(enabled bool) {
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+
+	c.dryRun = enabled
+}
+
+// GetDryRunLog returns copy of requests skipped in dry-run mode ordered by time
+func (c *Client) GetDryRunLog() []DryRunEntry {
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+
+	return append([]DryRunEntry{}, c.dryRunLog...)
+}
+
+// ClearDryRunLog removes all entries of dry-run log
+func (c *Client) ClearDryRunLog() {
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+
+	c.dryRunLog = nil
+}
+
+// skipDryRun records non-GET request to dry-run log if dry-run mode is enabled,
+// object is filled with successful result as server would return
+func (c *Client) skipDryRun(r *request, object interface{}) bool {
+	if "GET" == r.method {
+		return false
+	}
+
+	c.dryRunMu.Lock()
+	defer c.dryRunMu.Unlock()
+
+	if !c.dryRun {
+		return false
+	}
+
+	c.dryRunLog = append(c.dryRunLog, DryRunEntry{
+		Method: r.method,
+		URL:    r.url,
+		Body:   string(r.body),
+	})
+	c.log().Debug("request skipped in dry-run mode", "method", r.method, "url", r.url)
+
+	// not every response object is result so error is ignored
+	json.Unmarshal([]byte(`{"result":"OK"}`), object)
+	return true
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestDryRunSkipsChanges(t *testing.T) {
+	s := &configRecordingServer{config: configFixture}
+	c := newTestClient(t, s.ServeHTTP)
+	c.SetDryRun(true)
+
+	if err := c.AddIP("192.0.2.1", []string{"PRAGUE"}, "new", nil, false); nil != err {
+		t.Fatal(err)
+	}
+	if err := c.DeleteIP("1.1.1.1"); nil != err {
+		t.Fatal(err)
+	}
+	if err := c.IPsSetSlaves([]string{"8.8.8.8"}, map[string]bool{"LONDON": false}); nil != err {
+		t.Fatal(err)
+	}
+	if _, err := c.AddUser("bob", "secret", false); nil != err {
+		t.Fatal(err)
+	}
+	// change is computed from real config
+	if err := c.CopyGroupSlaves("INTERNAL", "DNS", false); nil != err {
+		t.Fatal(err)
+	}
+
+	if calls := s.calls(); 0 != len(calls) {
+		t.Fatalf("expected no changing requests in dry-run mode, got %v", calls)
+	}
+
+	entries := c.GetDryRunLog()
+	methods := []string{}
+	for _, entry := range entries {
+		methods = append(methods, entry.Method+" "+entry.URL[len(c.url):])
+	}
+	expected := []string{
+		"PUT /v1/config/ping/192.0.2.1",
+		"DELETE /v1/config/ping/1.1.1.1",
+		"PUT /v1/mconfig/slaves",
+		"PUT /v1/users",
+		"PUT /v1/groupslaves/DNS-%3E",
+	}
+	if !reflect.DeepEqual(expected, methods) {
+		t.Fatalf("expected dry-run log %v, got %v", expected, methods)
+	}
+	var payload struct {
+		Slaves map[string]bool `json:"slaves"`
+	}
+	if err := json.Unmarshal([]byte(entries[4].Body), &payload); nil != err {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(map[string]bool{"LONDON": false}, payload.Slaves) {
+		t.Errorf("unexpected computed payload %s", entries[4].Body)
+	}
+
+	c.ClearDryRunLog()
+	if 0 != len(c.GetDryRunLog()) {
+		t.Error("expected empty log after ClearDryRunLog")
+	}
+
+	c.SetDryRun(false)
+	if err := c.DeleteIP("1.1.1.1"); nil != err {
+		t.Fatal(err)
+	}
+	if calls := s.calls(); !reflect.DeepEqual([]string{"DELETE /v1/config/ping/1.1.1.1"}, calls) {
+		t.Errorf("expected request to be sent after dry-run is disabled, got %v", calls)
+	}
+	if 0 != len(c.GetDryRunLog()) {
+		t.Error("expected no log entries after dry-run is disabled")
+	}
+}
+
+func TestDryRunExecutesGet(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]string{"PRAGUE": "1.1.1.1:3030"})
+	})
+	c.SetDryRun(true)
+
+	slaves, err := c.GetSlavesIPs()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if "1.1.1.1" != slaves["PRAGUE"] {
+		t.Errorf("unexpected slaves %v", slaves)
+	}
+	if 0 != len(c.GetDryRunLog()) {
+		t.Errorf("GET must not be logged, got %v", c.GetDryRunLog())
+	}
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// SetCompactJSON asks server for not indented json in all future GET requests (adds compact=1 query parameter)
+func (c *Client) SetCompactJSON(compact bool) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.compactJSON = compact
+}
+
+// SetGzipEncoding makes all future requests ask for gzip compressed responses and decompress them
+func (c *Client) SetGzipEncoding(enabled bool) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.gzipEncoding = enabled
+}
+
+// encoding returns current SetCompactJSON and SetGzipEncoding values
+func (c *Client) encoding() (bool, bool) {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+
+	return c.compactJSON, c.gzipEncoding
+}
+
+// compactURL adds compact=1 to query of rawURL
+func compactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if nil != err {
+		return rawURL
+	}
+	query := u.Query()
+	query.Set("compact", "1")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// gzipReadCloser decompresses body and closes both gzip reader and body
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (g *gzipReadCloser) Close() error {
+	g.Reader.Close()
+	return g.body.Close()
+}
+
+// decodedBody returns response body decompressed according to Content-Encoding
+func decodedBody(resp *http.Response) (io.ReadCloser, error) {
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		return resp.Body, nil
+	}
+
+	reader, err := gzip.NewReader(resp.Body)
+	if nil != err {
+		return nil, err
+	}
+	return &gzipReadCloser{Reader: reader, body: resp.Body}, nil
+}
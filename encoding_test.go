@@ -0,0 +1,126 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// encodingServer serves config indented like some installations do, compact=1 and Accept-Encoding: gzip are honoured;
+// sent counts bytes of response bodies
+type encodingServer struct {
+	indented   []byte
+	compact    []byte
+	indentedGz []byte
+	compactGz  []byte
+	sent       int64
+}
+
+func newEncodingServer(config ConfigInfo) *encodingServer {
+	s := &encodingServer{}
+	s.indented, _ = json.MarshalIndent(config, "", "    ")
+	s.compact, _ = json.Marshal(config)
+	s.indentedGz = gzipBytes(s.indented)
+	s.compactGz = gzipBytes(s.compact)
+	return s
+}
+
+func gzipBytes(data []byte) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func (s *encodingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	compact := "1" == r.URL.Query().Get("compact")
+	gzipped := strings.Contains(r.Header.Get("Accept-Encoding"), "gzip")
+
+	body := s.indented
+	switch {
+	case compact && gzipped:
+		body = s.compactGz
+	case compact:
+		body = s.compact
+	case gzipped:
+		body = s.indentedGz
+	}
+	if gzipped {
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(body)
+	atomic.AddInt64(&s.sent, int64(len(body)))
+}
+
+// withoutTransportGzip disables transparent gzip of transport so only SetGzipEncoding asks for compression
+func withoutTransportGzip() Option {
+	return WithHTTPClient(&http.Client{Transport: &http.Transport{DisableCompression: true}})
+}
+
+func TestCompactAndGzipEncoding(t *testing.T) {
+	s := newEncodingServer(largeConfig(100))
+	c := newTestClient(t, s.ServeHTTP, withoutTransportGzip())
+
+	for _, tc := range []struct {
+		compact, gzipped bool
+		expected         []byte
+	}{
+		{false, false, s.indented},
+		{true, false, s.compact},
+		{false, true, s.indentedGz},
+		{true, true, s.compactGz},
+	} {
+		c.SetCompactJSON(tc.compact)
+		c.SetGzipEncoding(tc.gzipped)
+		atomic.StoreInt64(&s.sent, 0)
+
+		config, err := c.GetConfigInfo()
+		if nil != err {
+			t.Fatalf("compact=%v gzip=%v: %v", tc.compact, tc.gzipped, err)
+		}
+		if 100 != len(config.Ping.IPs) || "host 42" != config.Ping.IPs["10.0.0.42"].Description {
+			t.Fatalf("compact=%v gzip=%v: unexpected config", tc.compact, tc.gzipped)
+		}
+		if sent := atomic.LoadInt64(&s.sent); int64(len(tc.expected)) != sent {
+			t.Fatalf("compact=%v gzip=%v: expected %d bytes, got %d", tc.compact, tc.gzipped, len(tc.expected), sent)
+		}
+	}
+}
+
+func benchmarkConfigEncoding(b *testing.B, compact, gzipped bool) {
+	s := newEncodingServer(largeConfig(10000))
+	c := newTestClient(b, s.ServeHTTP, withoutTransportGzip())
+	c.SetCompactJSON(compact)
+	c.SetGzipEncoding(gzipped)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.GetConfigInfo(); nil != err {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(atomic.LoadInt64(&s.sent))/float64(b.N), "wire-bytes/op")
+}
+
+func BenchmarkConfigEncodingIndented(b *testing.B) {
+	benchmarkConfigEncoding(b, false, false)
+}
+
+func BenchmarkConfigEncodingCompact(b *testing.B) {
+	benchmarkConfigEncoding(b, true, false)
+}
+
+func BenchmarkConfigEncodingGzip(b *testing.B) {
+	benchmarkConfigEncoding(b, false, true)
+}
+
+func BenchmarkConfigEncodingCompactGzip(b *testing.B) {
+	benchmarkConfigEncoding(b, true, true)
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// environment variables read by InitFromEnv and NewFromEnv (possibly with prefix, see EnvPrefix)
+const (
+	EnvURL      = "COCOPACKET_URL"
+	EnvUser     = "COCOPACKET_USER"
+	EnvPassword = "COCOPACKET_PASSWORD"
+)
+
+// EnvOption changes behaviour of InitFromEnv and NewFromEnv
+type EnvOption func(*envSettings)
+
+type envSettings struct {
+	prefix string
+}
+
+// EnvPrefix namespaces environment variables, EnvPrefix("MYAPP") makes MYAPP_COCOPACKET_URL to be used and so on
+func EnvPrefix(prefix string) EnvOption {
+	return func(s *envSettings) {
+		s.prefix = strings.TrimSuffix(prefix, "_") + "_"
+	}
+}
+
+// envConfig reads and validates url, username and password from environment
+func envConfig(opts []EnvOption) (string, string, string, error) {
+	var s envSettings
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	urlVar := s.prefix + EnvURL
+	userVar := s.prefix + EnvUser
+	passwordVar := s.prefix + EnvPassword
+
+	apiURL := os.Getenv(urlVar)
+	user := os.Getenv(userVar)
+	password := os.Getenv(passwordVar)
+
+	missing := []string{}
+	if "" == apiURL {
+		missing = append(missing, urlVar)
+	}
+	if "" == user {
+		missing = append(missing, userVar)
+	}
+	if 0 != len(missing) {
+		return "", "", "", errors.New("missing environment variables: " + strings.Join(missing, ", "))
+	}
+
+	u, err := url.Parse(apiURL)
+	if nil != err {
+		return "", "", "", errors.New("invalid " + urlVar + ": " + err.Error())
+	}
+	if "" == u.Scheme || "" == u.Host {
+		return "", "", "", errors.New("invalid " + urlVar + ": absolute url expected")
+	}
+
+	return apiURL, user, password, nil
+}
+
+// InitFromEnv initializes default client from COCOPACKET_URL, COCOPACKET_USER and COCOPACKET_PASSWORD environment variables
+func InitFromEnv(opts ...EnvOption) error {
+	apiURL, user, password, err := envConfig(opts)
+	if nil != err {
+		return err
+	}
+
+	Init(apiURL, user, password)
+	return nil
+}
+
+// NewFromEnv creates client from COCOPACKET_URL, COCOPACKET_USER and COCOPACKET_PASSWORD environment variables
+func NewFromEnv(envOpts []EnvOption, opts ...Option) (*Client, error) {
+	apiURL, user, password, err := envConfig(envOpts)
+	if nil != err {
+		return nil, err
+	}
+
+	return New(apiURL, user, password, opts...), nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestEnvConfigCombinations(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		url      string
+		user     string
+		password string
+		errParts []string // empty means success
+	}{
+		{"all set", "https://cocopacket.example.com", "admin", "secret", nil},
+		{"empty password", "https://cocopacket.example.com", "admin", "", nil},
+		{"url with path and port", "http://10.0.0.1:8080/api", "admin", "secret", nil},
+		{"missing url", "", "admin", "secret", []string{"missing", EnvURL}},
+		{"missing user", "https://cocopacket.example.com", "", "secret", []string{"missing", EnvUser}},
+		{"missing url and user", "", "", "secret", []string{"missing", EnvURL + ", " + EnvUser}},
+		{"nothing set", "", "", "", []string{"missing", EnvURL, EnvUser}},
+		{"relative url", "cocopacket.example.com", "admin", "secret", []string{"invalid " + EnvURL, "absolute url"}},
+		{"url without host", "https://", "admin", "secret", []string{"invalid " + EnvURL}},
+		{"unparsable url", "http://[::1", "admin", "secret", []string{"invalid " + EnvURL}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(EnvURL, tc.url)
+			t.Setenv(EnvUser, tc.user)
+			t.Setenv(EnvPassword, tc.password)
+
+			c, err := NewFromEnv(nil)
+			if 0 == len(tc.errParts) {
+				if nil != err {
+					t.Fatal(err)
+				}
+				if tc.url != c.url {
+					t.Fatalf("expected url %s, got %s", tc.url, c.url)
+				}
+				expectedAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte(tc.user+":"+tc.password))
+				if auth, _ := c.authorization(); expectedAuth != auth {
+					t.Fatalf("expected auth %s, got %s", expectedAuth, auth)
+				}
+				return
+			}
+
+			if nil == err {
+				t.Fatal("expected error")
+			}
+			for _, part := range tc.errParts {
+				if !strings.Contains(err.Error(), part) {
+					t.Errorf("error %q doesn't contain %q", err, part)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvPrefix(t *testing.T) {
+	t.Setenv(EnvURL, "https://unprefixed.example.com")
+	t.Setenv(EnvUser, "unprefixed")
+	t.Setenv("MYAPP_"+EnvURL, "https://myapp.example.com")
+	t.Setenv("MYAPP_"+EnvUser, "myapp")
+	t.Setenv("MYAPP_"+EnvPassword, "secret")
+
+	for _, prefix := range []string{"MYAPP", "MYAPP_"} {
+		c, err := NewFromEnv([]EnvOption{EnvPrefix(prefix)})
+		if nil != err {
+			t.Fatal(err)
+		}
+		if "https://myapp.example.com" != c.url {
+			t.Fatalf("prefix %q: unexpected url %s", prefix, c.url)
+		}
+	}
+
+	t.Setenv("OTHER_"+EnvURL, "https://other.example.com")
+	_, err := NewFromEnv([]EnvOption{EnvPrefix("OTHER")})
+	if nil == err || !strings.Contains(err.Error(), "OTHER_"+EnvUser) || strings.Contains(err.Error(), "OTHER_"+EnvURL) {
+		t.Fatalf("expected error about missing OTHER_%s only, got %v", EnvUser, err)
+	}
+}
+
+func TestInitFromEnv(t *testing.T) {
+	saved := defaultClient
+	defer func() { defaultClient = saved }()
+	defaultClient = New("", "", "")
+
+	t.Setenv(EnvURL, "")
+	t.Setenv(EnvUser, "admin")
+	if err := InitFromEnv(); nil == err {
+		t.Fatal("expected error")
+	}
+	if "" != defaultClient.url {
+		t.Fatalf("default client changed on error: %s", defaultClient.url)
+	}
+
+	t.Setenv(EnvURL, "https://cocopacket.example.com")
+	t.Setenv(EnvPassword, "secret")
+	if err := InitFromEnv(); nil != err {
+		t.Fatal(err)
+	}
+	if "https://cocopacket.example.com" != defaultClient.url {
+		t.Fatalf("unexpected default client url %s", defaultClient.url)
+	}
+	if auth, _ := defaultClient.authorization(); "Basic "+base64.StdEncoding.EncodeToString([]byte("admin:secret")) != auth {
+		t.Fatalf("unexpected default client auth %s", auth)
+	}
+}
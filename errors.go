@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrIPNotFound is returned when requested IP is not monitored
+	ErrIPNotFound = errors.New("ip is not monitored")
+
+	// ErrIPAlreadyExists is returned when IP to add is already monitored
+	ErrIPAlreadyExists = errors.New("ip is already monitored")
+
+	// ErrAuthFailed is returned when request is rejected even with refreshed token
+	ErrAuthFailed = errors.New("authorization failed")
+
+	// ErrEmptySlaveName is returned when slave name is required but not specified
+	ErrEmptySlaveName = errors.New("empty slave name")
+
+	// ErrUserNotFound is returned when user with requested login doesn't exist
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrPasswordRequired is returned by ChangeUserRole and SetUserRole when server without role support has to change
+	// role, it replaces whole user record (password included) on update so password must be passed using
+	// ChangeUserRoleWithPassword
+	ErrPasswordRequired = errors.New("changing user role requires password")
+
+	// ErrGroupExists is returned when group to create already exists
+	ErrGroupExists = errors.New("group already exists")
+
+	// ErrInvalidGroupName is returned when group name is empty, too long or contains "->"
+	ErrInvalidGroupName = errors.New("invalid group name")
+
+	// ErrChannelNotFound is returned when notification channel with requested ID doesn't exist
+	ErrChannelNotFound = errors.New("notification channel not found")
+
+	// ErrNotConfirmed is returned by destructive operations called without confirmation
+	ErrNotConfirmed = errors.New("operation is not confirmed")
+
+	// ErrVersionTooOld is returned when master doesn't support requested feature
+	ErrVersionTooOld = errors.New("master version is too old")
+
+	// ErrUnreachable is returned by HealthCheck when master can't be connected
+	ErrUnreachable = errors.New("master is unreachable")
+
+	// ErrUnauthorized is returned by HealthCheck when master rejects credentials
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrServerError is returned by HealthCheck (wrapped together with status code) when master responds unexpectedly
+	ErrServerError = errors.New("server error")
+
+	// ErrProbeTimeout is returned by ProbeNow when result doesn't arrive within ProbeNowTimeout
+	ErrProbeTimeout = errors.New("probe result timeout")
+
+	// ErrSlaveUnreachable is returned by AddSlaveWithValidation (wrapped together with dial error) when slave can't be connected
+	ErrSlaveUnreachable = errors.New("slave is unreachable")
+
+	// ErrSlaveUpdateUnsupported is returned by UpdateSlave when master can change slave address neither by PATCH nor
+	// in place by POST, re-creating slave isn't done as master drops IPs left without slaves
+	ErrSlaveUpdateUnsupported = errors.New("slave address update is not supported by master")
+
+	// ErrDNSStatsUnsupported is returned by GetSlaveDNSResolutionStats when master doesn't provide DNS stats of slaves
+	ErrDNSStatsUnsupported = errors.New("dns stats are not supported by master")
+)
+
+// MultiError collects errors of several independent operations keyed by slave, group, IP...
+type MultiError map[string]error
+
+func (m MultiError) Error() string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+": "+m[key].Error())
+	}
+	return strings.Join(parts, "; ")
+}
+
+// errOrNil returns nil for empty MultiError to avoid non-nil interface with no errors
+func (m MultiError) errOrNil() error {
+	if 0 == len(m) {
+		return nil
+	}
+	return m
+}
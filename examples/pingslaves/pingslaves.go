@@ -35,7 +35,7 @@ func main() {
 
 	api.Init(*url, *user, *passwd)
 
-	slaves, err := api.GetSlavesSources()
+	slaves, _, err := api.GetSlavesSourcesAll()
 	if nil != err {
 		log.Fatalln("Error on slave list get:", err)
 	}
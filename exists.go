@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+// IPExists checks if ip is monitored
+func (c *Client) IPExists(ip string) (bool, error) {
+	return c.IPExistsContext(context.Background(), ip)
+}
+
+// IPExistsContext is same as IPExists but request is bound to ctx
+func (c *Client) IPExistsContext(ctx context.Context, ip string) (bool, error) {
+	var raw json.RawMessage
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/config/ping/"+ipPath(ip), nil, "", nil), &raw)
+	switch status {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	}
+	if nil == err {
+		err = errors.New("unexpected status code " + strconv.Itoa(status))
+	}
+	return false, err
+}
+
+// AddIPOptions changes behaviour of AddIPWithOptions
+type AddIPOptions struct {
+	IfNotExists bool // fail with ErrIPAlreadyExists instead of replacing configuration of already monitored IP
+}
+
+// AddIPWithOptions is same as AddIP but checks existence of IP first if requested by opts
+func (c *Client) AddIPWithOptions(ip string, slaves []string, description string, groups []string, favorite bool, opts AddIPOptions) error {
+	return c.AddIPWithOptionsContext(context.Background(), ip, slaves, description, groups, favorite, opts)
+}
+
+// AddIPWithOptionsContext is same as AddIPWithOptions but requests are bound to ctx
+func (c *Client) AddIPWithOptionsContext(ctx context.Context, ip string, slaves []string, description string, groups []string, favorite bool, opts AddIPOptions) error {
+	if opts.IfNotExists {
+		exists, err := c.IPExistsContext(ctx, ip)
+		if nil != err {
+			return err
+		}
+		if exists {
+			return ErrIPAlreadyExists
+		}
+	}
+
+	return c.AddIPContext(ctx, ip, slaves, description, groups, favorite)
+}
+
+// DeleteIPOptions changes behaviour of DeleteIPWithOptions
+type DeleteIPOptions struct {
+	IfExists bool // fail with ErrIPNotFound instead of calling delete for IP which isn't monitored
+}
+
+// DeleteIPWithOptions is same as DeleteIP but checks existence of IP first if requested by opts
+func (c *Client) DeleteIPWithOptions(ip string, opts DeleteIPOptions) error {
+	return c.DeleteIPWithOptionsContext(context.Background(), ip, opts)
+}
+
+// DeleteIPWithOptionsContext is same as DeleteIPWithOptions but requests are bound to ctx
+func (c *Client) DeleteIPWithOptionsContext(ctx context.Context, ip string, opts DeleteIPOptions) error {
+	if opts.IfExists {
+		exists, err := c.IPExistsContext(ctx, ip)
+		if nil != err {
+			return err
+		}
+		if !exists {
+			return ErrIPNotFound
+		}
+	}
+
+	return c.DeleteIPContext(ctx, ip)
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// SlaveResult is last-minute stats of one slave returned by GroupLastStatsAll
+type SlaveResult struct {
+	IPs  map[string]*AvgChunk
+	URLs map[string]*AvgChunk
+	Err  error
+}
+
+// forEach calls fn for every key in parallel respecting client concurrency limit
+func (c *Client) forEach(keys []string, fn func(key string)) {
+	forEachLimit(keys, c.concurrencyLimit(), fn)
+}
+
+// forEachLimit calls fn for every key in parallel with at most limit calls at once, 0 means unlimited
+func forEachLimit(keys []string, limit int, fn func(key string)) {
+	if limit <= 0 || limit > len(keys) {
+		limit = len(keys)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, limit)
+	for _, key := range keys {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(key string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(key)
+		}(key)
+	}
+	wg.Wait()
+}
+
+// GroupLastStatsAll returns last-minute stats for group from all slaves queried in parallel,
+// failed slaves are present in result with Err set and also reported by returned MultiError
+func (c *Client) GroupLastStatsAll(group string) (map[string]SlaveResult, error) {
+	return c.GroupLastStatsAllContext(context.Background(), group)
+}
+
+// GroupLastStatsAllContext is same as GroupLastStatsAll but requests are bound to ctx
+func (c *Client) GroupLastStatsAllContext(ctx context.Context, group string) (map[string]SlaveResult, error) {
+	slaves, err := c.GetSlaveListContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	var mu sync.Mutex
+	result := make(map[string]SlaveResult, len(slaves))
+	errs := MultiError{}
+
+	c.forEach(slaves, func(slave string) {
+		ips, urls, err := c.GroupLastStatsContext(ctx, group, slave)
+
+		mu.Lock()
+		defer mu.Unlock()
+		result[slave] = SlaveResult{IPs: ips, URLs: urls, Err: err}
+		if nil != err {
+			errs[slave] = err
+		}
+	})
+
+	return result, errs.errOrNil()
+}
+
+// GetMultiGroupStats returns GroupStats for several groups queried in parallel,
+// result contains only successful groups and failed ones are reported by returned MultiError
+func (c *Client) GetMultiGroupStats(groups []string, report bool) (map[string]GroupStatsData, error) {
+	return c.GetMultiGroupStatsContext(context.Background(), groups, report)
+}
+
+// GetMultiGroupStatsContext is same as GetMultiGroupStats but requests are bound to ctx
+func (c *Client) GetMultiGroupStatsContext(ctx context.Context, groups []string, report bool) (map[string]GroupStatsData, error) {
+	var mu sync.Mutex
+	result := make(map[string]GroupStatsData, len(groups))
+	errs := MultiError{}
+
+	c.forEach(groups, func(group string) {
+		data, err := c.GroupStatsContext(ctx, group, report)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if nil != err {
+			errs[group] = err
+			return
+		}
+		result[group] = data
+	})
+
+	return result, errs.errOrNil()
+}
+
+// IPAggregate is last-minute stats of one IP combined from all slaves, RTT values are in ms
+type IPAggregate struct {
+	MinRTT     float64 // lowest per-slave average
+	MaxRTT     float64 // highest per-slave average
+	AvgRTT     float64 // mean of per-slave averages (every slave has same weight)
+	WorstLoss  float64 // highest per-slave loss in percent
+	WorstSlave string  // slave with WorstLoss (first by name in case of tie)
+	Slaves     int     // count of slaves with any tests of IP
+}
+
+// AggregatedGroupStats is result of GetGroupLastStatsAll
+type AggregatedGroupStats struct {
+	PerSlave map[string]SlaveResult // raw data as returned by GroupLastStatsAll
+	IPs      map[string]IPAggregate
+}
+
+// GetGroupLastStatsAll returns last-minute stats of IPs in group from all slaves queried in parallel together with
+// per-IP aggregates across slaves; failed slaves are skipped by aggregation and reported by returned MultiError
+func (c *Client) GetGroupLastStatsAll(group string) (AggregatedGroupStats, error) {
+	return c.GetGroupLastStatsAllContext(context.Background(), group)
+}
+
+// GetGroupLastStatsAllContext is same as GetGroupLastStatsAll but requests are bound to ctx
+func (c *Client) GetGroupLastStatsAllContext(ctx context.Context, group string) (AggregatedGroupStats, error) {
+	perSlave, err := c.GroupLastStatsAllContext(ctx, group)
+	if _, ok := err.(MultiError); nil != err && !ok {
+		return AggregatedGroupStats{}, err
+	}
+
+	return AggregatedGroupStats{
+		PerSlave: perSlave,
+		IPs:      aggregateSlaveStats(perSlave),
+	}, err
+}
+
+// aggregateSlaveStats combines per-slave IP stats, slaves are processed by name so ties are stable
+func aggregateSlaveStats(perSlave map[string]SlaveResult) map[string]IPAggregate {
+	slaves := make([]string, 0, len(perSlave))
+	for slave := range perSlave {
+		slaves = append(slaves, slave)
+	}
+	sort.Strings(slaves)
+
+	result := map[string]IPAggregate{}
+	rttCount := map[string]int{}
+	for _, slave := range slaves {
+		if nil != perSlave[slave].Err {
+			continue
+		}
+		for ip, chunk := range perSlave[slave].IPs {
+			if nil == chunk || 0 == chunk.Count {
+				continue
+			}
+
+			agg, ok := result[ip]
+			loss := chunk.LossRatio() * 100
+			if !ok || loss > agg.WorstLoss {
+				agg.WorstLoss = loss
+				agg.WorstSlave = slave
+			}
+			agg.Slaves++
+
+			// average latency of slave which lost all packets says nothing about RTT
+			if avg := chunk.AvgLatency(); 0 != avg {
+				if 0 == rttCount[ip] || avg < agg.MinRTT {
+					agg.MinRTT = avg
+				}
+				if avg > agg.MaxRTT {
+					agg.MaxRTT = avg
+				}
+				agg.AvgRTT = (agg.AvgRTT*float64(rttCount[ip]) + avg) / float64(rttCount[ip]+1)
+				rttCount[ip]++
+			}
+
+			result[ip] = agg
+		}
+	}
+	return result
+}
@@ -0,0 +1,217 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// minuteStatsServer serves slaves list and /v1/minute/ stats per slave, slaves missing in stats answer with error
+type minuteStatsServer struct {
+	slaves map[string]string
+	stats  map[string]map[string]*AvgChunk // slave -> ip -> stats
+	delay  time.Duration
+
+	mu          sync.Mutex
+	queried     map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func (s *minuteStatsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case "/v1/slaves" == r.URL.Path:
+		writeTestJSON(w, s.slaves)
+
+	case strings.HasPrefix(r.URL.Path, "/v1/minute/"):
+		slave := r.URL.Query().Get("slave")
+
+		s.mu.Lock()
+		s.queried[slave]++
+		s.inFlight++
+		if s.inFlight > s.maxInFlight {
+			s.maxInFlight = s.inFlight
+		}
+		s.mu.Unlock()
+
+		time.Sleep(s.delay)
+
+		s.mu.Lock()
+		s.inFlight--
+		s.mu.Unlock()
+
+		ips, ok := s.stats[slave]
+		if !ok {
+			writeTestJSON(w, map[string]string{"result": "error", "error": "slave is offline"})
+			return
+		}
+		writeTestJSON(w, map[string]interface{}{"Ping": ips, "HTTP": map[string]*AvgChunk{}})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// counts returns copy of per-slave query counts and highest count of parallel requests
+func (s *minuteStatsServer) counts() (map[string]int, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queried := make(map[string]int, len(s.queried))
+	for slave, n := range s.queried {
+		queried[slave] = n
+	}
+	return queried, s.maxInFlight
+}
+
+func newMinuteStatsServer(stats map[string]map[string]*AvgChunk, failing ...string) *minuteStatsServer {
+	s := &minuteStatsServer{
+		slaves:  map[string]string{},
+		stats:   stats,
+		queried: map[string]int{},
+	}
+	for slave := range stats {
+		s.slaves[slave] = "10.0.0.1:3030"
+	}
+	for _, slave := range failing {
+		s.slaves[slave] = "10.0.0.2:3030"
+	}
+	return s
+}
+
+func TestGroupLastStatsAllQueriesAllSlaves(t *testing.T) {
+	s := newMinuteStatsServer(map[string]map[string]*AvgChunk{
+		"PRAGUE": {"1.1.1.1": {Count: 60, Latency: 600}},
+		"LONDON": {"1.1.1.1": {Count: 60, Latency: 1200}},
+		"PARIS":  {"1.1.1.1": {Count: 60, Latency: 900, Loss: 6}},
+	}, "TOKYO")
+	c := newTestClient(t, s.ServeHTTP)
+
+	result, err := c.GroupLastStatsAll("DNS")
+
+	var multi MultiError
+	if !errors.As(err, &multi) {
+		t.Fatalf("expected MultiError, got %v", err)
+	}
+	if 1 != len(multi) || nil == multi["TOKYO"] || !strings.Contains(multi["TOKYO"].Error(), "slave is offline") {
+		t.Fatalf("unexpected errors %v", multi)
+	}
+
+	if 4 != len(result) {
+		t.Fatalf("expected result for 4 slaves, got %v", result)
+	}
+	for slave, r := range result {
+		if "TOKYO" == slave {
+			if nil == r.Err {
+				t.Errorf("TOKYO should have error set")
+			}
+			continue
+		}
+		if nil != r.Err || 1 != len(r.IPs) {
+			t.Errorf("%s: unexpected result %+v", slave, r)
+		}
+	}
+	if 0.1 != result["PARIS"].IPs["1.1.1.1"].LossRatio() {
+		t.Errorf("unexpected PARIS stats %+v", result["PARIS"].IPs["1.1.1.1"])
+	}
+
+	queried, _ := s.counts()
+	for _, slave := range []string{"PRAGUE", "LONDON", "PARIS", "TOKYO"} {
+		if 1 != queried[slave] {
+			t.Errorf("%s was queried %d times", slave, queried[slave])
+		}
+	}
+}
+
+func TestGroupLastStatsAllNoErrors(t *testing.T) {
+	s := newMinuteStatsServer(map[string]map[string]*AvgChunk{
+		"PRAGUE": {"1.1.1.1": {Count: 60, Latency: 600}},
+		"LONDON": {"1.1.1.1": {Count: 60, Latency: 1200}},
+	})
+	c := newTestClient(t, s.ServeHTTP)
+
+	result, err := c.GroupLastStatsAll("DNS")
+	if nil != err {
+		t.Fatalf("expected nil error, got %#v", err)
+	}
+	if 2 != len(result) {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestGroupLastStatsAllConcurrencyLimit(t *testing.T) {
+	stats := map[string]map[string]*AvgChunk{}
+	for _, slave := range []string{"A", "B", "C", "D", "E", "F", "G", "H"} {
+		stats[slave] = map[string]*AvgChunk{}
+	}
+	s := newMinuteStatsServer(stats)
+	s.delay = 20 * time.Millisecond
+	c := newTestClient(t, s.ServeHTTP, WithConcurrency(2))
+
+	if _, err := c.GroupLastStatsAll("DNS"); nil != err {
+		t.Fatal(err)
+	}
+	queried, maxInFlight := s.counts()
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 parallel requests, got %d", maxInFlight)
+	}
+	if len(queried) != len(stats) {
+		t.Fatalf("not all slaves queried: %v", queried)
+	}
+}
+
+func TestGetGroupLastStatsAllAggregation(t *testing.T) {
+	s := newMinuteStatsServer(map[string]map[string]*AvgChunk{
+		"PRAGUE": {
+			"1.1.1.1": {Count: 60, Latency: 600}, // 10 ms
+			"8.8.8.8": nil,
+			"9.9.9.9": {Count: 10, Latency: 50}, // 5 ms
+		},
+		"LONDON": {
+			"1.1.1.1": {Count: 20, Latency: 900},          // 45 ms
+			"8.8.8.8": {Count: 10, Loss: 5, Latency: 800}, // 80 ms, 50 % loss
+			"9.9.9.9": {Count: 10, Latency: 70},           // 7 ms
+		},
+		"PARIS": {
+			"1.1.1.1": {Count: 40, Loss: 10, Latency: 100}, // 2.5 ms, 25 % loss
+			"8.8.8.8": {Count: 0},
+		},
+		"NEWYORK": {
+			"1.1.1.1": {Count: 10, Loss: 10}, // everything lost, no RTT
+		},
+	}, "TOKYO")
+	c := newTestClient(t, s.ServeHTTP)
+
+	stats, err := c.GetGroupLastStatsAll("DNS")
+	var multi MultiError
+	if !errors.As(err, &multi) || 1 != len(multi) || nil == multi["TOKYO"] {
+		t.Fatalf("expected MultiError for TOKYO, got %v", err)
+	}
+	if 5 != len(stats.PerSlave) || nil == stats.PerSlave["TOKYO"].Err {
+		t.Fatalf("unexpected per-slave data %+v", stats.PerSlave)
+	}
+
+	expected := map[string]IPAggregate{
+		"1.1.1.1": {MinRTT: 2.5, MaxRTT: 45, AvgRTT: (10 + 45 + 2.5) / 3, WorstLoss: 100, WorstSlave: "NEWYORK", Slaves: 4},
+		"8.8.8.8": {MinRTT: 80, MaxRTT: 80, AvgRTT: 80, WorstLoss: 50, WorstSlave: "LONDON", Slaves: 1},
+		// no loss anywhere, first slave by name wins the tie
+		"9.9.9.9": {MinRTT: 5, MaxRTT: 7, AvgRTT: 6, WorstLoss: 0, WorstSlave: "LONDON", Slaves: 2},
+	}
+	if len(expected) != len(stats.IPs) {
+		t.Fatalf("expected aggregates of %d IPs, got %+v", len(expected), stats.IPs)
+	}
+	for ip, want := range expected {
+		got := stats.IPs[ip]
+		if math.Abs(want.AvgRTT-got.AvgRTT) > 1e-9 {
+			t.Errorf("%s: expected avg %v, got %v", ip, want.AvgRTT, got.AvgRTT)
+		}
+		got.AvgRTT = want.AvgRTT
+		if want != got {
+			t.Errorf("%s: expected %+v, got %+v", ip, want, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strconv"
+)
+
+// GetFavoriteIPs returns sorted list of monitored IPs marked as favorite
+func (c *Client) GetFavoriteIPs() ([]string, error) {
+	return c.GetFavoriteIPsContext(context.Background())
+}
+
+// GetFavoriteIPsContext is same as GetFavoriteIPs but request is bound to ctx
+func (c *Client) GetFavoriteIPsContext(ctx context.Context) ([]string, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	ips := []string{}
+	for ip, desc := range config.Ping.IPs {
+		if desc.Favorite {
+			ips = append(ips, ip)
+		}
+	}
+	sort.Strings(ips)
+
+	return ips, nil
+}
+
+// SetFavoriteIPs sets favorite flag of ips keeping rest of their configuration, IPs are sent in batches (see WithBulkBatchSize);
+// unmonitored IPs (ErrIPNotFound) and failed batches are reported by MultiError
+func (c *Client) SetFavoriteIPs(ips []string, favorite bool) error {
+	return c.SetFavoriteIPsContext(context.Background(), ips, favorite)
+}
+
+// SetFavoriteIPsContext is same as SetFavoriteIPs but requests are bound to ctx
+func (c *Client) SetFavoriteIPsContext(ctx context.Context, ips []string, favorite bool) error {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	errs := MultiError{}
+	found := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		if _, ok := config.Ping.IPs[ip]; !ok {
+			errs[ip] = ErrIPNotFound
+			continue
+		}
+		found = append(found, ip)
+	}
+	sort.Strings(found)
+
+	for i, part := range batches(found, c.batchSize()) {
+		payload := make(map[string]TestDesc, len(part))
+		for _, ip := range part {
+			desc := config.Ping.IPs[ip]
+			desc.Favorite = favorite
+			payload[ip] = desc
+		}
+		if err := c.AddIPsRawContext(ctx, payload); nil != err {
+			errs["batch "+strconv.Itoa(i)] = err
+		}
+	}
+
+	return errs.errOrNil()
+}
+
+// ToggleFavorite flips favorite flag of monitored ip, ErrIPNotFound if IP isn't monitored
+func (c *Client) ToggleFavorite(ip string) error {
+	return c.ToggleFavoriteContext(context.Background(), ip)
+}
+
+// ToggleFavoriteContext is same as ToggleFavorite but requests are bound to ctx
+func (c *Client) ToggleFavoriteContext(ctx context.Context, ip string) error {
+	detail, err := c.GetIPDetailsContext(ctx, ip)
+	if nil != err {
+		return err
+	}
+
+	desc := detail.TestDesc
+	desc.Favorite = !desc.Favorite
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/ping/"+ipPath(ip), desc)
+}
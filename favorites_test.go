@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestGetFavoriteIPs(t *testing.T) {
+	c := newConfigClient(t, configFixture)
+
+	ips, err := c.GetFavoriteIPs()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"1.1.1.1"}, ips) {
+		t.Fatalf("unexpected favorites %v", ips)
+	}
+}
+
+func TestSetFavoriteIPsKeepsFields(t *testing.T) {
+	s := &configRecordingServer{config: configFixture}
+	c := newTestClient(t, s.ServeHTTP)
+	config := fixtureConfig(t)
+
+	err := c.SetFavoriteIPs([]string{"8.8.8.8", "2001:4860:4860::8888", "9.9.9.9"}, true)
+	multi, ok := err.(MultiError)
+	if !ok || 1 != len(multi) || !errors.Is(multi["9.9.9.9"], ErrIPNotFound) {
+		t.Fatalf("expected ErrIPNotFound for 9.9.9.9 only, got %v", err)
+	}
+
+	if calls := s.calls(); !reflect.DeepEqual([]string{"PUT /v1/mconfig/add"}, calls) {
+		t.Fatalf("unexpected requests %v", calls)
+	}
+	var sent map[string]TestDesc
+	json.Unmarshal(s.last().body["ips"], &sent)
+	if 2 != len(sent) {
+		t.Fatalf("unexpected payload %v", sent)
+	}
+	for ip, desc := range sent {
+		expected := config.Ping.IPs[ip]
+		expected.Favorite = true
+		if !reflect.DeepEqual(expected, desc) {
+			t.Errorf("%s: expected %+v, got %+v", ip, expected, desc)
+		}
+	}
+}
+
+func TestToggleFavoriteKeepsFields(t *testing.T) {
+	original := TestDesc{
+		Groups:           []string{"DNS->", "DNS->CLOUDFLARE->"},
+		Description:      "cloudflare",
+		Slaves:           []string{"PRAGUE", "LONDON"},
+		AS:               13335,
+		PacketCount:      5,
+		ProbeIntervalSec: 30,
+	}
+	s := &ipConfigServer{ips: map[string]TestDesc{"1.1.1.1": original}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	expected := original
+	for _, favorite := range []bool{true, false} {
+		if err := c.ToggleFavorite("1.1.1.1"); nil != err {
+			t.Fatal(err)
+		}
+		expected.Favorite = favorite
+		if got := s.get("1.1.1.1"); !reflect.DeepEqual(expected, got) {
+			t.Fatalf("expected %+v, got %+v", expected, got)
+		}
+	}
+
+	if err := c.ToggleFavorite("9.9.9.9"); !errors.Is(err, ErrIPNotFound) {
+		t.Fatalf("expected ErrIPNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,270 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// IPFilter selects IPs/URLs returned by GetConfigInfoFiltered, empty fields match everything
+type IPFilter struct {
+	Group               string   // only members of group (without subgroups)
+	SlaveNames          []string // only IPs/URLs tested by at least one of slaves
+	DescriptionContains string   // only IPs/URLs with description containing substring (case-insensitive)
+	FavoritesOnly       bool     // only favorite IPs/URLs
+}
+
+// query returns filter encoded as query parameters of /v1/config
+func (f IPFilter) query() url.Values {
+	query := url.Values{}
+	if "" != f.Group {
+		query.Set("group", strings.TrimSuffix(f.Group, groupSuffix)+groupSuffix)
+	}
+	for _, slave := range f.SlaveNames {
+		query.Add("slave", slave)
+	}
+	if "" != f.DescriptionContains {
+		query.Set("description", f.DescriptionContains)
+	}
+	if f.FavoritesOnly {
+		query.Set("favorite", "true")
+	}
+	return query
+}
+
+// Match reports if desc passes filter
+func (f IPFilter) Match(desc TestDesc) bool {
+	if "" != f.Group && !inGroup(desc.Groups, f.Group, false) {
+		return false
+	}
+	if f.FavoritesOnly && !desc.Favorite {
+		return false
+	}
+	if "" != f.DescriptionContains && !strings.Contains(strings.ToLower(desc.Description), strings.ToLower(f.DescriptionContains)) {
+		return false
+	}
+	if 0 != len(f.SlaveNames) {
+		for _, slave := range f.SlaveNames {
+			for _, s := range desc.Slaves {
+				if s == slave {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// GetConfigInfoFiltered returns current configuration with only IPs/URLs matching filter; filter is sent to server
+// and applied once more on client side so result is the same even if server doesn't support filtering
+func (c *Client) GetConfigInfoFiltered(filter IPFilter) (ConfigInfo, error) {
+	return c.GetConfigInfoFilteredContext(context.Background(), filter)
+}
+
+// GetConfigInfoFilteredContext is same as GetConfigInfoFiltered but request is bound to ctx
+func (c *Client) GetConfigInfoFilteredContext(ctx context.Context, filter IPFilter) (ConfigInfo, error) {
+	var result ConfigInfo
+
+	u := c.url + "/v1/config"
+	if query := filter.query(); 0 != len(query) {
+		u += "?" + query.Encode()
+	}
+
+	err := c.GetContext(ctx, u, &result)
+	if nil != err {
+		return result, err
+	}
+
+	for ip, desc := range result.Ping.IPs {
+		if !filter.Match(desc) {
+			delete(result.Ping.IPs, ip)
+		}
+	}
+	for u, desc := range result.HTTP.URLs {
+		if !filter.Match(desc) {
+			delete(result.HTTP.URLs, u)
+		}
+	}
+
+	return result, nil
+}
+
+// IPQuery selects IPs returned by SearchIPs, empty fields match everything
+type IPQuery struct {
+	DescriptionContains string    // case-insensitive substring of description
+	CIDR                net.IPNet // only IPs inside network (hostnames never match)
+	Groups              []string  // only members of at least one of groups (without subgroups)
+	SlaveNames          []string  // only IPs tested by at least one of slaves
+}
+
+// IPResult is one monitored IP found by SearchIPs
+type IPResult struct {
+	IP string
+	TestDesc
+}
+
+// match reports if ip with desc passes query
+func (q IPQuery) match(ip string, desc TestDesc) bool {
+	if nil != q.CIDR.IP {
+		parsed := net.ParseIP(normalizeIP(ip))
+		if nil == parsed || !q.CIDR.Contains(parsed) {
+			return false
+		}
+	}
+
+	if 0 != len(q.Groups) {
+		found := false
+		for _, group := range q.Groups {
+			if inGroup(desc.Groups, group, false) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return IPFilter{DescriptionContains: q.DescriptionContains, SlaveNames: q.SlaveNames}.Match(desc)
+}
+
+// SearchIPs returns monitored IPs matching query ordered by ip, server has no search endpoint so filtering is done locally
+func (c *Client) SearchIPs(query IPQuery) ([]IPResult, error) {
+	return c.SearchIPsContext(context.Background(), query)
+}
+
+// SearchIPsContext is same as SearchIPs but request is bound to ctx
+func (c *Client) SearchIPsContext(ctx context.Context, query IPQuery) ([]IPResult, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	result := []IPResult{}
+	for ip, desc := range config.Ping.IPs {
+		if query.match(ip, desc) {
+			result = append(result, IPResult{IP: ip, TestDesc: desc})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].IP < result[j].IP })
+
+	return result, nil
+}
+
+// ListIPsInCIDR returns sorted list of monitored IPs inside network cidr (like "10.0.0.0/8")
+func (c *Client) ListIPsInCIDR(cidr string) ([]string, error) {
+	return c.ListIPsInCIDRContext(context.Background(), cidr)
+}
+
+// ListIPsInCIDRContext is same as ListIPsInCIDR but request is bound to ctx
+func (c *Client) ListIPsInCIDRContext(ctx context.Context, cidr string) ([]string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if nil != err {
+		return nil, err
+	}
+
+	found, err := c.SearchIPsContext(ctx, IPQuery{CIDR: *network})
+	if nil != err {
+		return nil, err
+	}
+
+	ips := make([]string, 0, len(found))
+	for _, r := range found {
+		ips = append(ips, r.IP)
+	}
+	return ips, nil
+}
+
+// CIDRCoverageReport is result of GetCIDRCoverage
+type CIDRCoverageReport struct {
+	CIDR      string
+	Monitored int      // count of monitored IPs inside network
+	Total     *big.Int // count of addresses in network
+	Ratio     float64  // Monitored / Total
+}
+
+// GetCIDRCoverage reports how many addresses of network cidr are monitored
+func (c *Client) GetCIDRCoverage(cidr string) (CIDRCoverageReport, error) {
+	return c.GetCIDRCoverageContext(context.Background(), cidr)
+}
+
+// GetCIDRCoverageContext is same as GetCIDRCoverage but request is bound to ctx
+func (c *Client) GetCIDRCoverageContext(ctx context.Context, cidr string) (CIDRCoverageReport, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if nil != err {
+		return CIDRCoverageReport{}, err
+	}
+
+	ips, err := c.ListIPsInCIDRContext(ctx, cidr)
+	if nil != err {
+		return CIDRCoverageReport{}, err
+	}
+
+	ones, bits := network.Mask.Size()
+	report := CIDRCoverageReport{
+		CIDR:      network.String(),
+		Monitored: len(ips),
+		Total:     new(big.Int).Lsh(big.NewInt(1), uint(bits-ones)),
+	}
+	report.Ratio, _ = new(big.Float).Quo(new(big.Float).SetInt64(int64(len(ips))), new(big.Float).SetInt(report.Total)).Float64()
+
+	return report, nil
+}
+
+// GetUnmonitoredIPs returns IPs of allKnownIPs (like list of active hosts from IPAM) which are inside any of cidrs
+// but are not monitored, in order of allKnownIPs without duplicates (also if networks overlap)
+func (c *Client) GetUnmonitoredIPs(cidrs []string, allKnownIPs []string) ([]string, error) {
+	return c.GetUnmonitoredIPsContext(context.Background(), cidrs, allKnownIPs)
+}
+
+// GetUnmonitoredIPsContext is same as GetUnmonitoredIPs but request is bound to ctx
+func (c *Client) GetUnmonitoredIPsContext(ctx context.Context, cidrs []string, allKnownIPs []string) ([]string, error) {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if nil != err {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+
+	known := make([]net.IP, 0, len(allKnownIPs))
+	for _, ip := range allKnownIPs {
+		parsed := net.ParseIP(strings.Trim(ip, "[]"))
+		if nil == parsed {
+			return nil, errors.New("invalid ip " + ip)
+		}
+		known = append(known, parsed)
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	// monitored set also marks already reported IPs
+	seen := make(map[string]bool, len(config.Ping.IPs))
+	for ip := range config.Ping.IPs {
+		seen[normalizeIP(ip)] = true
+	}
+
+	result := []string{}
+	for _, ip := range known {
+		if seen[ip.String()] {
+			continue
+		}
+		for _, network := range networks {
+			if network.Contains(ip) {
+				seen[ip.String()] = true
+				result = append(result, ip.String())
+				break
+			}
+		}
+	}
+	return result, nil
+}
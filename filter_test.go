@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"testing"
+)
+
+// largeConfig returns config with n IPs spread over 10 groups and 4 slaves, every 100th IP is favorite
+func largeConfig(n int) ConfigInfo {
+	var config ConfigInfo
+	config.Ping.IPs = make(map[string]TestDesc, n)
+	config.HTTP.URLs = map[string]TestDesc{}
+	slaves := []string{"PRAGUE", "LONDON", "PARIS", "TOKYO"}
+	for i := 0; i < n; i++ {
+		config.Ping.IPs[fmt.Sprintf("10.%d.%d.%d", i>>16&255, i>>8&255, i&255)] = TestDesc{
+			Description: "host " + strconv.Itoa(i),
+			Groups:      []string{"GROUP" + strconv.Itoa(i%10) + "->"},
+			Slaves:      []string{slaves[i%len(slaves)]},
+			Favorite:    0 == i%100,
+		}
+	}
+	return config
+}
+
+// filteringConfigServer answers GET /v1/config, with serverSide=true it applies filter query parameters itself
+type filteringConfigServer struct {
+	config     ConfigInfo
+	raw        []byte
+	serverSide bool
+}
+
+func newFilteringConfigServer(n int, serverSide bool) *filteringConfigServer {
+	s := &filteringConfigServer{config: largeConfig(n), serverSide: serverSide}
+	s.raw, _ = json.Marshal(s.config)
+	return s
+}
+
+func (s *filteringConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !s.serverSide || 0 == len(query) {
+		w.Write(s.raw)
+		return
+	}
+
+	filter := IPFilter{
+		Group:               query.Get("group"),
+		SlaveNames:          query["slave"],
+		DescriptionContains: query.Get("description"),
+		FavoritesOnly:       "true" == query.Get("favorite"),
+	}
+	filtered := s.config
+	filtered.Ping.IPs = map[string]TestDesc{}
+	for ip, desc := range s.config.Ping.IPs {
+		if filter.Match(desc) {
+			filtered.Ping.IPs[ip] = desc
+		}
+	}
+	writeTestJSON(w, filtered)
+}
+
+func TestGetConfigInfoFiltered(t *testing.T) {
+	filter := IPFilter{Group: "GROUP3", SlaveNames: []string{"TOKYO"}, DescriptionContains: "HOST 1"}
+
+	// same result regardless of server support for filtering
+	for _, serverSide := range []bool{false, true} {
+		c := newTestClient(t, newFilteringConfigServer(1000, serverSide).ServeHTTP)
+		config, err := c.GetConfigInfoFiltered(filter)
+		if nil != err {
+			t.Fatal(err)
+		}
+		// i%10 == 3 and i%4 == 3 with description starting with "host 1": 103, 123, 143, 163 and 183
+		if 5 != len(config.Ping.IPs) {
+			t.Fatalf("serverSide=%v: expected 5 IPs, got %d", serverSide, len(config.Ping.IPs))
+		}
+		for ip, desc := range config.Ping.IPs {
+			if !filter.Match(desc) {
+				t.Fatalf("serverSide=%v: %s %+v doesn't match filter", serverSide, ip, desc)
+			}
+		}
+	}
+}
+
+func benchmarkGetConfigInfoFiltered(b *testing.B, serverSide bool) {
+	s := newFilteringConfigServer(10000, serverSide)
+	c := newTestClient(b, s.ServeHTTP)
+	filter := IPFilter{Group: "GROUP0", FavoritesOnly: true}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config, err := c.GetConfigInfoFiltered(filter)
+		if nil != err {
+			b.Fatal(err)
+		}
+		if 100 != len(config.Ping.IPs) {
+			b.Fatalf("expected 100 IPs, got %d", len(config.Ping.IPs))
+		}
+	}
+}
+
+func BenchmarkGetConfigInfoFilteredClientSide(b *testing.B) {
+	benchmarkGetConfigInfoFiltered(b, false)
+}
+
+func BenchmarkGetConfigInfoFilteredServerSide(b *testing.B) {
+	benchmarkGetConfigInfoFiltered(b, true)
+}
+
+func TestSearchIPs(t *testing.T) {
+	c := newTestClient(t, newFilteringConfigServer(1000, false).ServeHTTP)
+
+	_, network, _ := net.ParseCIDR("10.0.1.0/24")
+	found, err := c.SearchIPs(IPQuery{CIDR: *network, Groups: []string{"GROUP1", "GROUP2->"}, SlaveNames: []string{"PRAGUE"}})
+	if nil != err {
+		t.Fatal(err)
+	}
+	// 256..511 with i%4 == 0 (PRAGUE) and i%10 in (1, 2) means i%20 == 12 as odd i never goes to PRAGUE
+	var expected []string
+	for i := 256; i < 512; i++ {
+		if 12 == i%20 {
+			expected = append(expected, fmt.Sprintf("10.0.1.%d", i&255))
+		}
+	}
+	sort.Strings(expected)
+	if len(expected) != len(found) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(found))
+	}
+	for i, r := range found {
+		if expected[i] != r.IP || "PRAGUE" != r.Slaves[0] {
+			t.Fatalf("result %d: expected %s, got %+v", i, expected[i], r)
+		}
+	}
+}
+
+func BenchmarkSearchIPsLocal(b *testing.B) {
+	c := newTestClient(b, newFilteringConfigServer(10000, false).ServeHTTP)
+	query := IPQuery{DescriptionContains: "host 99"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		found, err := c.SearchIPs(query)
+		if nil != err {
+			b.Fatal(err)
+		}
+		if 111 != len(found) {
+			b.Fatalf("expected 111 IPs, got %d", len(found))
+		}
+	}
+}
+
+func BenchmarkSearchIPsServerSide(b *testing.B) {
+	c := newTestClient(b, newFilteringConfigServer(10000, true).ServeHTTP)
+	filter := IPFilter{DescriptionContains: "host 99"}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		config, err := c.GetConfigInfoFiltered(filter)
+		if nil != err {
+			b.Fatal(err)
+		}
+		if 111 != len(config.Ping.IPs) {
+			b.Fatalf("expected 111 IPs, got %d", len(config.Ping.IPs))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OutputFormat selects output of FormatSlaveStatus
+type OutputFormat int
+
+// supported output formats
+const (
+	FormatTable OutputFormat = iota // aligned ASCII table (default)
+	FormatJSON                      // indented json list
+	FormatYAML                      // yaml list
+)
+
+// FormatOptions configures FormatSlaveStatus
+type FormatOptions struct {
+	Format       OutputFormat
+	MonitoredIPs map[string]int // count of IPs by slave, empty for missing slaves
+}
+
+// slaveStatusColumns are columns of FormatSlaveStatus output
+var slaveStatusColumns = []string{"Slave", "Source", "Source6", "Status", "LastSeen", "MonitoredIPs"}
+
+// FormatSlaveStatus renders statuses (result of GetSlavesStatus) ordered by slave name as table, json or yaml
+// for CLI tools, empty values are shown as "-" in table
+func FormatSlaveStatus(statuses map[string]SlaveStatus, opts FormatOptions) string {
+	slaves := make([]string, 0, len(statuses))
+	for slave := range statuses {
+		slaves = append(slaves, slave)
+	}
+	sort.Strings(slaves)
+
+	rows := make([][]string, 0, len(slaves))
+	for _, slave := range slaves {
+		s := statuses[slave]
+		lastSeen := ""
+		if !s.Last.IsZero() {
+			lastSeen = s.Last.UTC().Format(time.RFC3339)
+		}
+		monitored := ""
+		if count, ok := opts.MonitoredIPs[slave]; ok {
+			monitored = strconv.Itoa(count)
+		}
+		rows = append(rows, []string{slave, s.Source, s.Source6, s.Status, lastSeen, monitored})
+	}
+
+	switch opts.Format {
+	case FormatJSON:
+		return formatJSON(rows)
+	case FormatYAML:
+		return formatYAML(rows)
+	}
+	return formatTable(slaveStatusColumns, rows)
+}
+
+// formatTable aligns columns by widest value, columns are separated by two spaces and empty values are shown as "-"
+func formatTable(header []string, rows [][]string) string {
+	filled := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		row = append([]string{}, row...)
+		for i, value := range row {
+			if "" == value {
+				row[i] = "-"
+			}
+		}
+		filled = append(filled, row)
+	}
+	rows = filled
+
+	widths := make([]int, len(header))
+	for _, row := range append([][]string{header}, rows...) {
+		for i, value := range row {
+			if len(value) > widths[i] {
+				widths[i] = len(value)
+			}
+		}
+	}
+
+	separator := make([]string, len(header))
+	for i, width := range widths {
+		separator[i] = strings.Repeat("-", width)
+	}
+
+	var b strings.Builder
+	for _, row := range append([][]string{header, separator}, rows...) {
+		for i, value := range row {
+			if i == len(row)-1 {
+				b.WriteString(value)
+				break
+			}
+			b.WriteString(value)
+			b.WriteString(strings.Repeat(" ", widths[i]-len(value)+2))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// formatJSON renders rows as list of objects keyed by column names
+func formatJSON(rows [][]string) string {
+	list := make([]map[string]string, 0, len(rows))
+	for _, row := range rows {
+		item := make(map[string]string, len(row))
+		for i, value := range row {
+			item[slaveStatusColumns[i]] = value
+		}
+		list = append(list, item)
+	}
+
+	raw, _ := json.MarshalIndent(list, "", "  ")
+	return string(raw) + "\n"
+}
+
+// formatYAML renders rows as yaml list keeping column order, values are double-quoted json strings which are valid yaml
+func formatYAML(rows [][]string) string {
+	if 0 == len(rows) {
+		return "[]\n"
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		for i, value := range row {
+			if 0 == i {
+				b.WriteString("- ")
+			} else {
+				b.WriteString("  ")
+			}
+			b.WriteString(slaveStatusColumns[i])
+			b.WriteString(": ")
+			b.WriteString(strconv.Quote(value))
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
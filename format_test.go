@@ -0,0 +1,120 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func formatFixture() (map[string]SlaveStatus, FormatOptions) {
+	last := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	statuses := map[string]SlaveStatus{
+		"PRAGUE":         {Source: "1.1.1.1", Source6: "2001:db8::1", Status: "online", Last: last},
+		"PRAGUE2":        {Source: "1.1.1.2", Status: "offline"},
+		"LONDON-BACKUP1": {Source: "10.200.30.4", Status: "ok", Last: last},
+	}
+	return statuses, FormatOptions{MonitoredIPs: map[string]int{"PRAGUE": 1500, "LONDON-BACKUP1": 7}}
+}
+
+func TestFormatSlaveStatusTable(t *testing.T) {
+	statuses, opts := formatFixture()
+	lines := strings.Split(strings.TrimSuffix(FormatSlaveStatus(statuses, opts), "\n"), "\n")
+
+	expected := [][]string{
+		slaveStatusColumns,
+		{"LONDON-BACKUP1", "10.200.30.4", "-", "ok", "2024-03-01T12:00:00Z", "7"},
+		{"PRAGUE", "1.1.1.1", "2001:db8::1", "online", "2024-03-01T12:00:00Z", "1500"},
+		{"PRAGUE2", "1.1.1.2", "-", "offline", "-", "-"},
+	}
+	if len(expected)+1 != len(lines) {
+		t.Fatalf("expected header, separator and %d rows, got:\n%s", len(expected)-1, strings.Join(lines, "\n"))
+	}
+
+	// every column starts at the same offset in all lines and is separated by at least two spaces
+	starts := []int{0}
+	for _, column := range slaveStatusColumns[1:] {
+		starts = append(starts, strings.Index(lines[0], column))
+	}
+	for i, line := range append(lines[:1:1], lines[2:]...) {
+		for col, start := range starts {
+			end := len(line)
+			if col+1 < len(starts) {
+				end = starts[col+1]
+				if !strings.HasSuffix(line[:end], "  ") {
+					t.Errorf("line %d: column %d isn't followed by two spaces: %q", i, col, line)
+				}
+			}
+			if value := strings.TrimRight(line[start:end], " "); expected[i][col] != value {
+				t.Errorf("line %d column %d: expected %q, got %q", i, col, expected[i][col], value)
+			}
+		}
+	}
+	for col, start := range starts {
+		end := len(lines[1])
+		if col+1 < len(starts) {
+			end = starts[col+1] - 2
+		}
+		if strings.Repeat("-", end-start) != lines[1][start:end] {
+			t.Errorf("separator of column %d doesn't match its width: %q", col, lines[1])
+		}
+	}
+}
+
+func TestFormatSlaveStatusOnce(t *testing.T) {
+	statuses, opts := formatFixture()
+
+	for name, format := range map[string]OutputFormat{"table": FormatTable, "json": FormatJSON, "yaml": FormatYAML} {
+		opts.Format = format
+		output := FormatSlaveStatus(statuses, opts)
+
+		var names []string
+		switch format {
+		case FormatTable:
+			for _, line := range strings.Split(output, "\n")[2:] {
+				if fields := strings.Fields(line); 0 != len(fields) {
+					names = append(names, fields[0])
+				}
+			}
+		case FormatJSON:
+			var list []map[string]string
+			if err := json.Unmarshal([]byte(output), &list); nil != err {
+				t.Fatalf("%s: %v", name, err)
+			}
+			for _, item := range list {
+				names = append(names, item["Slave"])
+			}
+		case FormatYAML:
+			for _, line := range strings.Split(output, "\n") {
+				if strings.HasPrefix(line, "- Slave: ") {
+					names = append(names, strings.Trim(strings.TrimPrefix(line, "- Slave: "), `"`))
+				}
+			}
+		}
+
+		seen := map[string]int{}
+		for _, slave := range names {
+			seen[slave]++
+		}
+		if len(statuses) != len(names) {
+			t.Errorf("%s: expected %d slaves, got %v", name, len(statuses), names)
+		}
+		for slave := range statuses {
+			if 1 != seen[slave] {
+				t.Errorf("%s: slave %s appears %d times", name, slave, seen[slave])
+			}
+		}
+	}
+}
+
+func TestFormatSlaveStatusEmpty(t *testing.T) {
+	if "[]\n" != FormatSlaveStatus(nil, FormatOptions{Format: FormatJSON}) {
+		t.Error("expected empty json list")
+	}
+	if "[]\n" != FormatSlaveStatus(nil, FormatOptions{Format: FormatYAML}) {
+		t.Error("expected empty yaml list")
+	}
+	if lines := strings.Split(strings.TrimSuffix(FormatSlaveStatus(nil, FormatOptions{}), "\n"), "\n"); 2 != len(lines) {
+		t.Errorf("expected only header and separator, got %q", lines)
+	}
+}
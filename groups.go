@@ -0,0 +1,623 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// groupSuffix is appended by server to every group name
+const groupSuffix = "->"
+
+// GetGroupList returns sorted list of all configured groups (without trailing "->")
+func (c *Client) GetGroupList() ([]string, error) {
+	return c.GetGroupListContext(context.Background())
+}
+
+// GetGroupListContext is same as GetGroupList but request is bound to ctx
+func (c *Client) GetGroupListContext(ctx context.Context) ([]string, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	return groupNames(config), nil
+}
+
+// GetGroupMembers returns sorted list of IPs/URLs in group, pass recursive=true to include subgroups
+func (c *Client) GetGroupMembers(group string, recursive bool) ([]string, error) {
+	return c.GetGroupMembersContext(context.Background(), group, recursive)
+}
+
+// GetGroupMembersContext is same as GetGroupMembers but request is bound to ctx
+func (c *Client) GetGroupMembersContext(ctx context.Context, group string, recursive bool) ([]string, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	list := []string{}
+	for _, tests := range []map[string]TestDesc{config.Ping.IPs, config.HTTP.URLs} {
+		for target, desc := range tests {
+			if inGroup(desc.Groups, group, recursive) {
+				list = append(list, target)
+			}
+		}
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// groupNames returns sorted list of groups (without trailing "->") present in config
+func groupNames(config ConfigInfo) []string {
+	groups := map[string]bool{}
+	for group := range config.Groups {
+		groups[strings.TrimSuffix(group, groupSuffix)] = true
+	}
+	for _, tests := range []map[string]TestDesc{config.Ping.IPs, config.HTTP.URLs} {
+		for _, desc := range tests {
+			for _, group := range desc.Groups {
+				groups[strings.TrimSuffix(group, groupSuffix)] = true
+			}
+		}
+	}
+
+	list := make([]string, 0, len(groups))
+	for group := range groups {
+		list = append(list, group)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// inGroup checks if groups list contains group (or its subgroup in case of recursive)
+func inGroup(groups []string, group string, recursive bool) bool {
+	name := strings.TrimSuffix(group, groupSuffix) + groupSuffix
+	for _, g := range groups {
+		if g == name || (recursive && strings.HasPrefix(g, name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetGroupConfig returns settings of group
+func (c *Client) GetGroupConfig(group string) (GroupConfig, error) {
+	return c.GetGroupConfigContext(context.Background(), group)
+}
+
+// GetGroupConfigContext is same as GetGroupConfig but request is bound to ctx
+func (c *Client) GetGroupConfigContext(ctx context.Context, group string) (GroupConfig, error) {
+	var result GroupConfig
+	err := c.GetContext(ctx, c.url+"/v1/group/"+url.QueryEscape(strings.TrimSuffix(group, groupSuffix)+groupSuffix), &result)
+	return result, err
+}
+
+// SetGroupConfig replaces settings of group
+func (c *Client) SetGroupConfig(group string, config GroupConfig) error {
+	return c.SetGroupConfigContext(context.Background(), group, config)
+}
+
+// SetGroupConfigContext is same as SetGroupConfig but request is bound to ctx
+func (c *Client) SetGroupConfigContext(ctx context.Context, group string, config GroupConfig) error {
+	return c._okResultSend(ctx, "POST", c.url+"/v1/group/"+url.QueryEscape(strings.TrimSuffix(group, groupSuffix)+groupSuffix), config)
+}
+
+// CloneGroup adds all IPs/URLs of src group also to dst group (src subgroups are cloned as dst subgroups in case of recursive)
+// and then applies slaves changes to dst like GroupSetSlaves does; ErrGroupExists is returned if dst exists and overwrite isn't set
+func (c *Client) CloneGroup(src, dst string, slaves map[string]bool, recursive bool, overwrite bool) error {
+	return c.CloneGroupContext(context.Background(), src, dst, slaves, recursive, overwrite)
+}
+
+// CloneGroupContext is same as CloneGroup but requests are bound to ctx
+func (c *Client) CloneGroupContext(ctx context.Context, src, dst string, slaves map[string]bool, recursive bool, overwrite bool) error {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	srcName := strings.TrimSuffix(src, groupSuffix) + groupSuffix
+	dstName := strings.TrimSuffix(dst, groupSuffix) + groupSuffix
+
+	if !overwrite {
+		for _, group := range groupNames(config) {
+			if group+groupSuffix == dstName {
+				return ErrGroupExists
+			}
+		}
+	}
+
+	// cloned returns desc with dst groups added for every matching src group
+	cloned := func(desc TestDesc) TestDesc {
+		groups := append([]string{}, desc.Groups...)
+		for _, g := range desc.Groups {
+			if g == srcName || (recursive && strings.HasPrefix(g, srcName)) {
+				groups = appendUnique(groups, dstName+strings.TrimPrefix(g, srcName))
+			}
+		}
+		desc.Groups = groups
+		return desc
+	}
+
+	ips := map[string]TestDesc{}
+	for ip, desc := range config.Ping.IPs {
+		if inGroup(desc.Groups, src, recursive) {
+			ips[ip] = cloned(desc)
+		}
+	}
+	if 0 != len(ips) {
+		if err := c.AddIPsRawContext(ctx, ips); nil != err {
+			return err
+		}
+	}
+
+	for u, desc := range config.HTTP.URLs {
+		if inGroup(desc.Groups, src, recursive) {
+			if err := c.AddURLRawContext(ctx, u, cloned(desc)); nil != err {
+				return err
+			}
+		}
+	}
+
+	if 0 == len(slaves) {
+		return nil
+	}
+	return c.GroupSetSlavesContext(ctx, strings.TrimSuffix(dst, groupSuffix), slaves, recursive)
+}
+
+// appendUnique appends s to list if it's not there yet
+func appendUnique(list []string, s string) []string {
+	for _, item := range list {
+		if item == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// DeleteGroup removes group (not recursive, subgroups stay untouched); server also removes IPs/tests which are not in any other group.
+// When deleteMembers is true all IPs/tests of group are removed first even if they belong to other groups,
+// failed removals are reported by returned MultiError but group is deleted anyway
+func (c *Client) DeleteGroup(group string, deleteMembers bool) error {
+	return c.DeleteGroupContext(context.Background(), group, deleteMembers)
+}
+
+// DeleteGroupContext is same as DeleteGroup but requests are bound to ctx
+func (c *Client) DeleteGroupContext(ctx context.Context, group string, deleteMembers bool) error {
+	errs := MultiError{}
+
+	if deleteMembers {
+		config, err := c.GetConfigInfoContext(ctx)
+		if nil != err {
+			return err
+		}
+
+		ips := []string{}
+		for ip, desc := range config.Ping.IPs {
+			if inGroup(desc.Groups, group, false) {
+				ips = append(ips, ip)
+			}
+		}
+		if 0 != len(ips) {
+			if err := c.DeleteIPsContext(ctx, ips); nil != err {
+				for _, ip := range ips {
+					errs[ip] = err
+				}
+			}
+		}
+
+		for u, desc := range config.HTTP.URLs {
+			if inGroup(desc.Groups, group, false) {
+				if err := c.DeleteURLContext(ctx, u); nil != err {
+					errs[u] = err
+				}
+			}
+		}
+	}
+
+	err := c._okResultSend(ctx, "DELETE", c.url+"/v1/group/"+url.QueryEscape(strings.TrimSuffix(group, groupSuffix)+groupSuffix), nil)
+	if nil != err {
+		errs[group] = err
+	}
+
+	return errs.errOrNil()
+}
+
+// MaxGroupNameLength is longest group name accepted by server
+const MaxGroupNameLength = 128
+
+// validateGroupName checks that name can be used for new group
+func validateGroupName(name string) error {
+	if "" == name || len(name) > MaxGroupNameLength || strings.Contains(name, groupSuffix) {
+		return ErrInvalidGroupName
+	}
+	return nil
+}
+
+// AddGroup creates group with description, ips and slaves assigned to them, ErrGroupExists is returned if group already exists
+func (c *Client) AddGroup(name string, description string, ips []string, slaves []string) error {
+	return c.AddGroupContext(context.Background(), name, description, ips, slaves)
+}
+
+// AddGroupContext is same as AddGroup but request is bound to ctx
+func (c *Client) AddGroupContext(ctx context.Context, name string, description string, ips []string, slaves []string) error {
+	return c.sendGroup(ctx, "POST", name, description, ips, slaves)
+}
+
+// CreateOrUpdateGroup is same as AddGroup but replaces description, ips and slaves of group if it already exists
+func (c *Client) CreateOrUpdateGroup(name string, description string, ips []string, slaves []string) error {
+	return c.CreateOrUpdateGroupContext(context.Background(), name, description, ips, slaves)
+}
+
+// CreateOrUpdateGroupContext is same as CreateOrUpdateGroup but request is bound to ctx
+func (c *Client) CreateOrUpdateGroupContext(ctx context.Context, name string, description string, ips []string, slaves []string) error {
+	return c.sendGroup(ctx, "PUT", name, description, ips, slaves)
+}
+
+// sendGroup sends group definition to /v1/groups using method
+func (c *Client) sendGroup(ctx context.Context, method string, name string, description string, ips []string, slaves []string) error {
+	if err := validateGroupName(name); nil != err {
+		return err
+	}
+
+	if nil == ips {
+		ips = []string{}
+	}
+	if nil == slaves {
+		slaves = []string{}
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{
+		"group":       name + groupSuffix,
+		"description": description,
+		"ips":         ips,
+		"slaves":      slaves,
+	})
+	if nil != err {
+		return err
+	}
+
+	var r result
+	status, err := c.execute(ctx, newRequest(method, c.url+"/v1/groups", raw, "application/json", nil), &r)
+	if http.StatusConflict == status {
+		return ErrGroupExists
+	}
+	if nil != err {
+		return err
+	}
+
+	if r.Result != "OK" && "" != r.Error {
+		return errors.New(r.Error)
+	}
+	if r.Result != "OK" {
+		return errors.New("unknown error")
+	}
+	return nil
+}
+
+// GetIPGroups returns sorted list of groups (without trailing "->") ip belongs to, ErrIPNotFound if IP isn't monitored
+func (c *Client) GetIPGroups(ip string) ([]string, error) {
+	return c.GetIPGroupsContext(context.Background(), ip)
+}
+
+// GetIPGroupsContext is same as GetIPGroups but request is bound to ctx
+func (c *Client) GetIPGroupsContext(ctx context.Context, ip string) ([]string, error) {
+	detail, err := c.GetIPDetailsContext(ctx, ip)
+	if nil != err {
+		return nil, err
+	}
+	return trimGroups(detail.Groups), nil
+}
+
+// BulkGetIPGroups is same as GetIPGroups for many ips using only one API call, unmonitored ips are missing in result
+func (c *Client) BulkGetIPGroups(ips []string) (map[string][]string, error) {
+	return c.BulkGetIPGroupsContext(context.Background(), ips)
+}
+
+// BulkGetIPGroupsContext is same as BulkGetIPGroups but request is bound to ctx
+func (c *Client) BulkGetIPGroupsContext(ctx context.Context, ips []string) (map[string][]string, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	monitored := make(map[string]TestDesc, len(config.Ping.IPs))
+	for ip, desc := range config.Ping.IPs {
+		monitored[normalizeIP(ip)] = desc
+	}
+
+	result := make(map[string][]string, len(ips))
+	for _, ip := range ips {
+		if desc, ok := monitored[normalizeIP(ip)]; ok {
+			result[ip] = trimGroups(desc.Groups)
+		}
+	}
+	return result, nil
+}
+
+// trimGroups returns sorted copy of groups without trailing "->"
+func trimGroups(groups []string) []string {
+	list := make([]string, 0, len(groups))
+	for _, group := range groups {
+		list = appendUnique(list, strings.TrimSuffix(group, groupSuffix))
+	}
+	sort.Strings(list)
+	return list
+}
+
+// CopyGroupSlaves assigns slaves used by IPs/URLs of src group to all IPs/URLs of dst group; in case of merge=false
+// slaves used in dst but not in src are removed from dst. Applied changes are logged with debug level
+func (c *Client) CopyGroupSlaves(src, dst string, merge bool) error {
+	return c.CopyGroupSlavesContext(context.Background(), src, dst, merge)
+}
+
+// CopyGroupSlavesContext is same as CopyGroupSlaves but requests are bound to ctx
+func (c *Client) CopyGroupSlavesContext(ctx context.Context, src, dst string, merge bool) error {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	srcSlaves := groupSlaves(config, src)
+	dstSlaves := groupSlaves(config, dst)
+
+	changes := map[string]bool{}
+	for slave := range srcSlaves {
+		// slave has to be added unless every dst member already has it
+		if !dstSlaves[slave] {
+			changes[slave] = true
+		}
+	}
+	if !merge {
+		for slave := range dstSlaves {
+			if _, ok := srcSlaves[slave]; !ok {
+				changes[slave] = false
+			}
+		}
+	}
+
+	if 0 == len(changes) {
+		c.log().Debug("group slaves already in sync", "src", src, "dst", dst)
+		return nil
+	}
+
+	added := []string{}
+	removed := []string{}
+	for slave, add := range changes {
+		if add {
+			added = append(added, slave)
+		} else {
+			removed = append(removed, slave)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+
+	if err := c.GroupSetSlavesContext(ctx, strings.TrimSuffix(dst, groupSuffix), changes, false); nil != err {
+		return err
+	}
+
+	c.log().Debug("group slaves copied", "src", src, "dst", dst, "added", added, "removed", removed)
+	return nil
+}
+
+// groupSlaves returns slaves used by IPs/URLs of group, value is true if slave is used by all of them
+func groupSlaves(config ConfigInfo, group string) map[string]bool {
+	counts := map[string]int{}
+	members := 0
+	for _, tests := range []map[string]TestDesc{config.Ping.IPs, config.HTTP.URLs} {
+		for _, desc := range tests {
+			if !inGroup(desc.Groups, group, false) {
+				continue
+			}
+			members++
+			for _, slave := range desc.Slaves {
+				counts[slave]++
+			}
+		}
+	}
+
+	slaves := make(map[string]bool, len(counts))
+	for slave, count := range counts {
+		slaves[slave] = count == members
+	}
+	return slaves
+}
+
+// SetDefaultGroups sets groups automatically added to groups of every IP created by AddIP or AddIPs
+// (groups passed by caller are kept, duplicates are skipped)
+func (c *Client) SetDefaultGroups(groups []string) {
+	list := make([]string, 0, len(groups))
+	for _, group := range groups {
+		if "" != strings.TrimSuffix(group, groupSuffix) {
+			list = appendUnique(list, strings.TrimSuffix(group, groupSuffix)+groupSuffix)
+		}
+	}
+
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.defaultGroups = list
+}
+
+// ClearDefaultGroups stops adding groups set by SetDefaultGroups
+func (c *Client) ClearDefaultGroups() {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.defaultGroups = nil
+}
+
+// withDefaultGroups returns groups extended by ones set by SetDefaultGroups
+func (c *Client) withDefaultGroups(groups []string) []string {
+	c.authMu.RLock()
+	defaults := c.defaultGroups
+	c.authMu.RUnlock()
+
+	if 0 == len(defaults) {
+		return groups
+	}
+
+	result := make([]string, 0, len(groups)+len(defaults))
+	present := map[string]bool{}
+	for _, group := range append(append([]string{}, groups...), defaults...) {
+		name := strings.TrimSuffix(group, groupSuffix)
+		if present[name] {
+			continue
+		}
+		present[name] = true
+		result = append(result, group)
+	}
+	return result
+}
+
+// DeleteByGroupReport is result of DeleteIPsByGroup
+type DeleteByGroupReport struct {
+	Deleted []string         // IPs removed from monitoring
+	Demoted []string         // IPs kept in other groups and only removed from group
+	Failed  map[string]error // IPs which were neither deleted nor demoted
+}
+
+// DeleteIPsByGroup removes monitoring of IPs in group. In case of removeFromOtherGroups=false only IPs belonging
+// exclusively to group are deleted and IPs shared with other groups are just removed from group,
+// otherwise all IPs of group are deleted. Returned error is MultiError of failed IPs (same as report.Failed)
+func (c *Client) DeleteIPsByGroup(group string, removeFromOtherGroups bool) (DeleteByGroupReport, error) {
+	return c.DeleteIPsByGroupContext(context.Background(), group, removeFromOtherGroups)
+}
+
+// DeleteIPsByGroupContext is same as DeleteIPsByGroup but requests are bound to ctx
+func (c *Client) DeleteIPsByGroupContext(ctx context.Context, group string, removeFromOtherGroups bool) (DeleteByGroupReport, error) {
+	report := DeleteByGroupReport{
+		Deleted: []string{},
+		Demoted: []string{},
+		Failed:  map[string]error{},
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return report, err
+	}
+
+	name := strings.TrimSuffix(group, groupSuffix) + groupSuffix
+	remove := []string{}
+	demote := map[string]TestDesc{}
+	for ip, desc := range config.Ping.IPs {
+		if !inGroup(desc.Groups, group, false) {
+			continue
+		}
+		if removeFromOtherGroups || 0 == len(removeString(desc.Groups, name)) {
+			remove = append(remove, ip)
+			continue
+		}
+		desc.Groups = removeString(desc.Groups, name)
+		demote[ip] = desc
+	}
+	sort.Strings(remove)
+
+	if 0 != len(remove) {
+		deleted, _ := c.DeleteIPsSafeContext(ctx, remove, ContinueOnError)
+		report.Deleted = deleted.Deleted
+		for ip, err := range deleted.Failed {
+			report.Failed[ip] = err
+		}
+	}
+
+	for _, part := range batches(sortedKeys(demote), c.batchSize()) {
+		payload := make(map[string]TestDesc, len(part))
+		for _, ip := range part {
+			payload[ip] = demote[ip]
+		}
+		if err := c.AddIPsRawContext(ctx, payload); nil != err {
+			for _, ip := range part {
+				report.Failed[ip] = err
+			}
+			continue
+		}
+		report.Demoted = append(report.Demoted, part...)
+	}
+
+	return report, MultiError(report.Failed).errOrNil()
+}
+
+// GroupNode is one group of hierarchy built from "->" separated group names
+type GroupNode struct {
+	Name     string // full name of group without trailing "->" (like "europe->prague"), empty for root
+	Children []GroupNode
+	IPs      []string // IPs directly in group (not in subgroups)
+}
+
+// GetGroupHierarchy returns tree of all groups with root node named "", children and IPs are sorted;
+// parent groups without own IPs are present even if they are not configured
+func (c *Client) GetGroupHierarchy() (GroupNode, error) {
+	return c.GetGroupHierarchyContext(context.Background())
+}
+
+// GetGroupHierarchyContext is same as GetGroupHierarchy but request is bound to ctx
+func (c *Client) GetGroupHierarchyContext(ctx context.Context) (GroupNode, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return GroupNode{}, err
+	}
+
+	members := map[string][]string{}
+	for ip, desc := range config.Ping.IPs {
+		for _, group := range desc.Groups {
+			name := strings.TrimSuffix(group, groupSuffix)
+			members[name] = appendUnique(members[name], ip)
+		}
+	}
+
+	return buildGroupTree("", groupNames(config), members), nil
+}
+
+// buildGroupTree builds node name with children found in sorted list of all group names
+func buildGroupTree(name string, names []string, members map[string][]string) GroupNode {
+	node := GroupNode{
+		Name:     name,
+		Children: []GroupNode{},
+		IPs:      append([]string{}, members[name]...),
+	}
+	sort.Strings(node.IPs)
+
+	prefix := ""
+	if "" != name {
+		prefix = name + groupSuffix
+	}
+
+	children := []string{}
+	for _, group := range names {
+		if "" == group || !strings.HasPrefix(group, prefix) || group == name {
+			continue
+		}
+		child := prefix + strings.SplitN(strings.TrimPrefix(group, prefix), groupSuffix, 2)[0]
+		children = appendUnique(children, child)
+	}
+	sort.Strings(children)
+
+	for _, child := range children {
+		node.Children = append(node.Children, buildGroupTree(child, names, members))
+	}
+	return node
+}
+
+// FindInHierarchy searches tree returned by GetGroupHierarchy for group with full name (trailing "->" is ignored)
+func FindInHierarchy(root GroupNode, name string) (*GroupNode, bool) {
+	name = strings.TrimSuffix(name, groupSuffix)
+	if root.Name == name {
+		return &root, true
+	}
+	for i := range root.Children {
+		child := &root.Children[i]
+		if name != child.Name && !strings.HasPrefix(name, child.Name+groupSuffix) {
+			continue
+		}
+		return FindInHierarchy(*child, name)
+	}
+	return nil, false
+}
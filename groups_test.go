@@ -0,0 +1,443 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// configFixture is trimmed response of GET /v1/config
+const configFixture = `{
+	"counter": 42,
+	"ping": {
+		"ips": {
+			"1.1.1.1": {"cat": ["DNS->", "DNS->CLOUDFLARE->"], "desc": "cloudflare", "fav": true, "slaves": ["PRAGUE", "LONDON"], "as": 13335},
+			"8.8.8.8": {"cat": ["DNS->GOOGLE->"], "desc": "google", "fav": false, "slaves": ["PRAGUE"], "as": 15169},
+			"2001:4860:4860::8888": {"cat": ["DNS->GOOGLE->", "IPV6->"], "desc": "google v6", "fav": false, "slaves": ["LONDON"], "as": 15169},
+			"10.0.0.1": {"cat": ["INTERNAL->"], "desc": "gateway", "fav": false, "slaves": ["PRAGUE"], "as": 0}
+		},
+		"timeout": 1,
+		"interval": 1,
+		"slowdown": 0,
+		"slowEvery": 0,
+		"lastIP": ""
+	},
+	"HTTP": {
+		"urls": {
+			"https://example.com/": {"cat": ["WEB->"], "desc": "example", "fav": false, "slaves": ["PRAGUE"], "as": 0}
+		},
+		"timeout": 5,
+		"interval": 60
+	},
+	"groups": {
+		"DNS->": {"isPublic": true},
+		"EMPTY->": {}
+	}
+}`
+
+// newConfigClient returns client connected to server answering GET /v1/config with config
+func newConfigClient(t *testing.T, config string) *Client {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if "/v1/config" != r.URL.Path || "GET" != r.Method {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(config))
+	})
+}
+
+func TestGetGroupList(t *testing.T) {
+	c := newConfigClient(t, configFixture)
+
+	groups, err := c.GetGroupList()
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := []string{"DNS", "DNS->CLOUDFLARE", "DNS->GOOGLE", "EMPTY", "INTERNAL", "IPV6", "WEB"}
+	if !reflect.DeepEqual(expected, groups) {
+		t.Fatalf("expected %v, got %v", expected, groups)
+	}
+}
+
+func TestGetGroupMembers(t *testing.T) {
+	c := newConfigClient(t, configFixture)
+
+	for _, tc := range []struct {
+		group     string
+		recursive bool
+		expected  []string
+	}{
+		{"DNS", false, []string{"1.1.1.1"}},
+		{"DNS->", false, []string{"1.1.1.1"}},
+		{"DNS", true, []string{"1.1.1.1", "2001:4860:4860::8888", "8.8.8.8"}},
+		{"DNS->GOOGLE", false, []string{"2001:4860:4860::8888", "8.8.8.8"}},
+		{"WEB", false, []string{"https://example.com/"}},
+		{"EMPTY", true, []string{}},
+		{"MISSING", false, []string{}},
+	} {
+		members, err := c.GetGroupMembers(tc.group, tc.recursive)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tc.expected, members) {
+			t.Errorf("%s (recursive=%v): expected %v, got %v", tc.group, tc.recursive, tc.expected, members)
+		}
+	}
+}
+
+// configRecordingServer answers GET /v1/config with config and records all other requests answering {"result":"OK"}
+type configRecordingServer struct {
+	recordingServer
+	config string
+}
+
+func (s *configRecordingServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if "/v1/config" == r.URL.Path && "GET" == r.Method {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(s.config))
+		return
+	}
+	s.recordingServer.ServeHTTP(w, r)
+}
+
+// calls returns "METHOD path" of all recorded requests
+func (s *configRecordingServer) calls() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]string, 0, len(s.requests))
+	for _, r := range s.requests {
+		result = append(result, r.method+" "+r.path)
+	}
+	return result
+}
+
+func TestDeleteGroupNested(t *testing.T) {
+	s := &configRecordingServer{config: configFixture}
+	c := newTestClient(t, s.ServeHTTP)
+
+	// without members only group itself is removed, subgroups DNS->CLOUDFLARE-> and DNS->GOOGLE-> stay
+	if err := c.DeleteGroup("DNS", false); nil != err {
+		t.Fatal(err)
+	}
+	if calls := s.calls(); !reflect.DeepEqual([]string{"DELETE /v1/group/DNS-%3E"}, calls) {
+		t.Fatalf("unexpected requests %v", calls)
+	}
+
+	s = &configRecordingServer{config: configFixture}
+	c = newTestClient(t, s.ServeHTTP)
+
+	// members of nested subgroups are not members of parent group
+	if err := c.DeleteGroup("DNS->", true); nil != err {
+		t.Fatal(err)
+	}
+	calls := s.calls()
+	if !reflect.DeepEqual([]string{"PUT /v1/mconfig/delete", "DELETE /v1/group/DNS-%3E"}, calls) {
+		t.Fatalf("unexpected requests %v", calls)
+	}
+	var ips []string
+	json.Unmarshal(s.requests[0].body["ips"], &ips)
+	if !reflect.DeepEqual([]string{"1.1.1.1"}, ips) {
+		t.Fatalf("expected only direct member 1.1.1.1 to be deleted, got %v", ips)
+	}
+
+	s = &configRecordingServer{config: configFixture}
+	c = newTestClient(t, s.ServeHTTP)
+
+	// deepest group with both IPv4 and IPv6 members
+	if err := c.DeleteGroup("DNS->GOOGLE", true); nil != err {
+		t.Fatal(err)
+	}
+	ips = nil
+	json.Unmarshal(s.requests[0].body["ips"], &ips)
+	sort.Strings(ips)
+	if !reflect.DeepEqual([]string{"2001:4860:4860::8888", "8.8.8.8"}, ips) || "DELETE /v1/group/DNS-%3EGOOGLE-%3E" != s.calls()[1] {
+		t.Fatalf("unexpected requests %v with ips %v", s.calls(), ips)
+	}
+}
+
+func TestDeleteGroupURLMembers(t *testing.T) {
+	s := &configRecordingServer{config: configFixture}
+	c := newTestClient(t, s.ServeHTTP)
+
+	if err := c.DeleteGroup("WEB", true); nil != err {
+		t.Fatal(err)
+	}
+	expected := []string{"DELETE /v1/config/http/https:%2F%2Fexample.com%2F", "DELETE /v1/group/WEB-%3E"}
+	if calls := s.calls(); !reflect.DeepEqual(expected, calls) {
+		t.Fatalf("expected %v, got %v", expected, calls)
+	}
+}
+
+func TestAddGroupNameValidation(t *testing.T) {
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for _, name := range []string{"", "DNS->", "DNS->GOOGLE", "->", strings.Repeat("a", MaxGroupNameLength+1)} {
+		if err := c.AddGroup(name, "", nil, nil); !errors.Is(err, ErrInvalidGroupName) {
+			t.Errorf("AddGroup(%q): expected ErrInvalidGroupName, got %v", name, err)
+		}
+		if err := c.CreateOrUpdateGroup(name, "", nil, nil); !errors.Is(err, ErrInvalidGroupName) {
+			t.Errorf("CreateOrUpdateGroup(%q): expected ErrInvalidGroupName, got %v", name, err)
+		}
+	}
+	if 0 != len(s.requests) {
+		t.Fatalf("invalid names were sent to server: %d requests", len(s.requests))
+	}
+
+	for _, name := range []string{"DNS", "a", "with space", "dash-and>gt", strings.Repeat("a", MaxGroupNameLength)} {
+		if err := c.AddGroup(name, "desc", []string{"1.1.1.1"}, []string{"PRAGUE"}); nil != err {
+			t.Errorf("AddGroup(%q): %v", name, err)
+		}
+		r := s.last()
+		var group string
+		json.Unmarshal(r.body["group"], &group)
+		if "POST" != r.method || "/v1/groups" != r.path || name+"->" != group {
+			t.Errorf("AddGroup(%q): unexpected request %s %s %s", name, r.method, r.path, group)
+		}
+	}
+}
+
+func TestAddGroupExists(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		writeTestJSON(w, result{Result: "error", Error: "group exists"})
+	})
+
+	if err := c.AddGroup("DNS", "", nil, nil); !errors.Is(err, ErrGroupExists) {
+		t.Fatalf("expected ErrGroupExists, got %v", err)
+	}
+}
+
+func TestGetIPGroups(t *testing.T) {
+	s := &ipConfigServer{ips: map[string]TestDesc{
+		"1.1.1.1":     {Groups: []string{"DNS->", "DNS->CLOUDFLARE->", "ANYCAST->"}},
+		"8.8.8.8":     {Groups: []string{"DNS->GOOGLE->PRIMARY->"}},
+		"10.0.0.1":    {Groups: []string{}},
+		"10.0.0.2":    {},
+		"2001:db8::1": {Groups: []string{"IPV6->", "IPV6->"}},
+	}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for ip, expected := range map[string][]string{
+		"1.1.1.1":       {"ANYCAST", "DNS", "DNS->CLOUDFLARE"},
+		"8.8.8.8":       {"DNS->GOOGLE->PRIMARY"},
+		"10.0.0.1":      {},
+		"10.0.0.2":      {},
+		"2001:DB8:0::1": {"IPV6"},
+	} {
+		groups, err := c.GetIPGroups(ip)
+		if nil != err {
+			t.Fatalf("%s: %v", ip, err)
+		}
+		if !reflect.DeepEqual(expected, groups) {
+			t.Errorf("%s: expected %v, got %v", ip, expected, groups)
+		}
+	}
+
+	if _, err := c.GetIPGroups("9.9.9.9"); !errors.Is(err, ErrIPNotFound) {
+		t.Fatalf("expected ErrIPNotFound, got %v", err)
+	}
+}
+
+func TestBulkGetIPGroups(t *testing.T) {
+	c := newConfigClient(t, configFixture)
+
+	groups, err := c.BulkGetIPGroups([]string{"1.1.1.1", "2001:4860:4860:0::8888", "10.0.0.1", "9.9.9.9"})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"1.1.1.1":                {"DNS", "DNS->CLOUDFLARE"},
+		"2001:4860:4860:0::8888": {"DNS->GOOGLE", "IPV6"},
+		"10.0.0.1":               {"INTERNAL"},
+	}
+	if !reflect.DeepEqual(expected, groups) {
+		t.Fatalf("expected %v, got %v", expected, groups)
+	}
+}
+
+func TestCopyGroupSlavesReplace(t *testing.T) {
+	s := &configRecordingServer{config: configFixture}
+	logger := &captureLogger{}
+	c := newTestClient(t, s.ServeHTTP, WithLogger(logger))
+
+	// DNS members use PRAGUE and LONDON, INTERNAL only PRAGUE so LONDON has to be removed from DNS
+	if err := c.CopyGroupSlaves("INTERNAL", "DNS", false); nil != err {
+		t.Fatal(err)
+	}
+	if calls := s.calls(); !reflect.DeepEqual([]string{"PUT /v1/groupslaves/DNS-%3E"}, calls) {
+		t.Fatalf("unexpected requests %v", calls)
+	}
+	var slaves map[string]bool
+	json.Unmarshal(s.last().body["slaves"], &slaves)
+	if !reflect.DeepEqual(map[string]bool{"LONDON": false}, slaves) {
+		t.Fatalf("expected LONDON to be removed, got %v", slaves)
+	}
+
+	logged := logger.messages("DEBUG group slaves copied")
+	if 1 != len(logged) {
+		t.Fatalf("expected diff log, got %v", logger.lines)
+	}
+	args := logger.args[len(logger.args)-1]
+	if !reflect.DeepEqual([]interface{}{"src", "INTERNAL", "dst", "DNS", "added", []string{}, "removed", []string{"LONDON"}}, args) {
+		t.Fatalf("unexpected diff log args %v", args)
+	}
+}
+
+func TestCopyGroupSlavesMerge(t *testing.T) {
+	s := &configRecordingServer{config: configFixture}
+	c := newTestClient(t, s.ServeHTTP)
+
+	// merge keeps LONDON and PRAGUE is already used by every DNS member
+	if err := c.CopyGroupSlaves("INTERNAL", "DNS", true); nil != err {
+		t.Fatal(err)
+	}
+	if 0 != len(s.calls()) {
+		t.Fatalf("nothing should be changed, got %v", s.calls())
+	}
+
+	if err := c.CopyGroupSlaves("DNS", "INTERNAL", true); nil != err {
+		t.Fatal(err)
+	}
+	var slaves map[string]bool
+	json.Unmarshal(s.last().body["slaves"], &slaves)
+	if !reflect.DeepEqual(map[string]bool{"LONDON": true}, slaves) || "/v1/groupslaves/INTERNAL-%3E" != s.last().path {
+		t.Fatalf("expected LONDON to be added to INTERNAL, got %s %v", s.last().path, slaves)
+	}
+}
+
+func TestDefaultGroupsMerge(t *testing.T) {
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+	c.SetDefaultGroups([]string{"all", "production->", "region-eu", "all->", ""})
+
+	if err := c.AddIP("192.0.2.1", []string{"PRAGUE"}, "single", []string{"custom->", "production->"}, false); nil != err {
+		t.Fatal(err)
+	}
+	var groups []string
+	if err := json.Unmarshal(s.last().body["cat"], &groups); nil != err {
+		t.Fatal(err)
+	}
+	expected := []string{"custom->", "production->", "all->", "region-eu->"}
+	if !reflect.DeepEqual(expected, groups) {
+		t.Errorf("AddIP: expected groups %v, got %v", expected, groups)
+	}
+
+	if err := c.AddIPs([]string{"192.0.2.2", "192.0.2.3"}, []string{"PRAGUE"}, "bulk", []string{"region-eu->"}, false); nil != err {
+		t.Fatal(err)
+	}
+	var ips map[string]TestDesc
+	if err := json.Unmarshal(s.last().body["ips"], &ips); nil != err {
+		t.Fatal(err)
+	}
+	expected = []string{"region-eu->", "all->", "production->"}
+	for _, ip := range []string{"192.0.2.2", "192.0.2.3"} {
+		if !reflect.DeepEqual(expected, ips[ip].Groups) {
+			t.Errorf("AddIPs %s: expected groups %v, got %v", ip, expected, ips[ip].Groups)
+		}
+	}
+
+	c.ClearDefaultGroups()
+	if err := c.AddIP("192.0.2.4", []string{"PRAGUE"}, "cleared", []string{"custom->"}, false); nil != err {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(s.last().body["cat"], &groups); nil != err {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual([]string{"custom->"}, groups) {
+		t.Errorf("after ClearDefaultGroups expected only caller groups, got %v", groups)
+	}
+}
+
+// hierarchyFixture has groups up to three levels deep, ASIA and ASIA->JP exist only as parents of ASIA->JP->TOKYO
+const hierarchyFixture = `{
+	"ping": {
+		"ips": {
+			"10.0.0.1": {"cat": ["EUROPE->"]},
+			"10.1.0.1": {"cat": ["EUROPE->CZ->PRAGUE->"]},
+			"10.1.0.2": {"cat": ["EUROPE->CZ->PRAGUE->", "EUROPE->CZ->BRNO->"]},
+			"10.2.0.1": {"cat": ["EUROPE->CZECHIA->"]},
+			"10.3.0.1": {"cat": ["ASIA->JP->TOKYO->"]}
+		}
+	},
+	"groups": {
+		"EUROPE->": {},
+		"EUROPE->CZ->": {},
+		"EUROPE->UK->LONDON->": {}
+	}
+}`
+
+func TestGetGroupHierarchyThreeLevels(t *testing.T) {
+	c := newConfigClient(t, hierarchyFixture)
+
+	root, err := c.GetGroupHierarchy()
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	leaf := func(name string, ips ...string) GroupNode {
+		return GroupNode{Name: name, Children: []GroupNode{}, IPs: append([]string{}, ips...)}
+	}
+	expected := GroupNode{Name: "", Children: []GroupNode{
+		{Name: "ASIA", IPs: []string{}, Children: []GroupNode{
+			{Name: "ASIA->JP", IPs: []string{}, Children: []GroupNode{
+				leaf("ASIA->JP->TOKYO", "10.3.0.1"),
+			}},
+		}},
+		{Name: "EUROPE", IPs: []string{"10.0.0.1"}, Children: []GroupNode{
+			{Name: "EUROPE->CZ", IPs: []string{}, Children: []GroupNode{
+				leaf("EUROPE->CZ->BRNO", "10.1.0.2"),
+				leaf("EUROPE->CZ->PRAGUE", "10.1.0.1", "10.1.0.2"),
+			}},
+			leaf("EUROPE->CZECHIA", "10.2.0.1"),
+			{Name: "EUROPE->UK", IPs: []string{}, Children: []GroupNode{
+				leaf("EUROPE->UK->LONDON"),
+			}},
+		}},
+	}, IPs: []string{}}
+	if !reflect.DeepEqual(expected, root) {
+		t.Fatalf("expected %+v, got %+v", expected, root)
+	}
+}
+
+func TestFindInHierarchyThreeLevels(t *testing.T) {
+	c := newConfigClient(t, hierarchyFixture)
+
+	root, err := c.GetGroupHierarchy()
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	for name, ips := range map[string][]string{
+		"EUROPE->CZ->PRAGUE":   {"10.1.0.1", "10.1.0.2"},
+		"EUROPE->CZ->PRAGUE->": {"10.1.0.1", "10.1.0.2"},
+		"ASIA->JP->TOKYO":      {"10.3.0.1"},
+		"ASIA->JP":             {},
+		"EUROPE->CZECHIA":      {"10.2.0.1"},
+		"EUROPE->UK->LONDON":   {},
+	} {
+		node, ok := FindInHierarchy(root, name)
+		if !ok {
+			t.Errorf("%s not found", name)
+			continue
+		}
+		if strings.TrimSuffix(name, "->") != node.Name || !reflect.DeepEqual(ips, node.IPs) {
+			t.Errorf("%s: unexpected node %+v", name, node)
+		}
+	}
+
+	for _, name := range []string{"EUROPE->CZ->OSTRAVA", "EUROPE->C", "EUROPE->CZECH", "ASIA->JP->TOKYO->SHIBUYA", "JP"} {
+		if node, ok := FindInHierarchy(root, name); ok || nil != node {
+			t.Errorf("%s: expected not found, got %+v", name, node)
+		}
+	}
+}
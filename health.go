@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// HealthCheck verifies that master is reachable and accepts credentials, it's recommended to call it right after Init/New.
+// Returned error wraps ErrUnreachable, ErrUnauthorized or ErrServerError (with status code) so it can be tested by errors.Is
+func (c *Client) HealthCheck() error {
+	return c.HealthCheckContext(context.Background())
+}
+
+// HealthCheckContext is same as HealthCheck but request is bound to ctx
+func (c *Client) HealthCheckContext(ctx context.Context) error {
+	// version is the lightest endpoint which still requires authorization
+	var version map[string]interface{}
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/status/version", nil, "", nil), &version)
+
+	switch {
+	case nil != ctx.Err():
+		return ctx.Err()
+	case 0 == status && nil != err:
+		return fmt.Errorf("%w: %v", ErrUnreachable, err)
+	case http.StatusUnauthorized == status || http.StatusForbidden == status || ErrAuthFailed == err:
+		return ErrUnauthorized
+	case http.StatusOK != status:
+		return fmt.Errorf("%w: status code %d", ErrServerError, status)
+	case nil != err:
+		return fmt.Errorf("%w: invalid response: %v", ErrServerError, err)
+	case nil == version:
+		return fmt.Errorf("%w: empty response", ErrServerError)
+	}
+
+	return nil
+}
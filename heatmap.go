@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// LatencyHeatmap is average RTT in ms by day of week (indexed by time.Weekday, Sunday first) and hour of day in UTC,
+// slots without any successful probes are -1
+type LatencyHeatmap [7][24]float64
+
+// GetLatencyHeatmap returns time-of-day RTT distribution of ip measured by slave during last weeks
+func (c *Client) GetLatencyHeatmap(ip string, slave string, weeks int) (LatencyHeatmap, error) {
+	return c.GetLatencyHeatmapContext(context.Background(), ip, slave, weeks)
+}
+
+// GetLatencyHeatmapContext is same as GetLatencyHeatmap but requests are bound to ctx
+func (c *Client) GetLatencyHeatmapContext(ctx context.Context, ip string, slave string, weeks int) (LatencyHeatmap, error) {
+	if weeks <= 0 {
+		return emptyHeatmap(), errors.New("weeks must be positive")
+	}
+	if "" == slave {
+		return emptyHeatmap(), ErrEmptySlaveName
+	}
+
+	ip = normalizeIP(ip)
+	details, err := c.GetIPDetailsContext(ctx, ip)
+	if nil != err {
+		return emptyHeatmap(), err
+	}
+	if 0 == len(details.Groups) {
+		return emptyHeatmap(), errors.New("ip " + ip + " isn't in any group")
+	}
+
+	// historical stats are available only per group so any group of ip is good enough
+	to := time.Now().Truncate(time.Hour)
+	from := to.Add(-time.Duration(weeks) * 7 * 24 * time.Hour)
+	data, err := c.historicalStats(ctx, strings.TrimSuffix(details.Groups[0], groupSuffix), slave, from, to, time.Hour)
+	if nil != err {
+		return emptyHeatmap(), err
+	}
+
+	return aggregateHeatmap(data.Ping[ip]), nil
+}
+
+// aggregateHeatmap sums hourly stats (unix timestamp -> chunk) into day of week/hour slots
+func aggregateHeatmap(points map[int64]*AvgChunk) LatencyHeatmap {
+	var sums [7][24]AvgChunk
+	for ts, chunk := range points {
+		if nil == chunk {
+			continue
+		}
+		t := time.Unix(ts, 0).UTC()
+		slot := &sums[t.Weekday()][t.Hour()]
+		slot.Count += chunk.Count
+		slot.Loss += chunk.Loss
+		slot.Latency += chunk.Latency
+	}
+
+	heatmap := emptyHeatmap()
+	for day := range sums {
+		for hour := range sums[day] {
+			if avg := sums[day][hour].AvgLatency(); 0 != avg {
+				heatmap[day][hour] = avg
+			}
+		}
+	}
+	return heatmap
+}
+
+// emptyHeatmap returns heatmap with all slots missing
+func emptyHeatmap() LatencyHeatmap {
+	var heatmap LatencyHeatmap
+	for day := range heatmap {
+		for hour := range heatmap[day] {
+			heatmap[day][hour] = -1
+		}
+	}
+	return heatmap
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// heatmapServer answers ip details and hourly historical stats of group DNS measured by PRAGUE
+type heatmapServer struct {
+	points map[int64]*AvgChunk
+}
+
+func (s *heatmapServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case "/v1/config/ping/192.0.2.1" == r.URL.Path:
+		writeTestJSON(w, IPDetail{TestDesc: TestDesc{Groups: []string{"DNS->", "ALL->"}}})
+	case "/v1/historystats/DNS->" == r.URL.Path:
+		if "PRAGUE" != r.URL.Query().Get("slave") || "3600" != r.URL.Query().Get("resolution") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		writeTestJSON(w, GroupStatsData{Ping: map[string]map[int64]*AvgChunk{
+			"192.0.2.1": s.points,
+			"192.0.2.2": {hourUnix(2024, 1, 1, 10): {Count: 1, Latency: 1000}},
+		}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func hourUnix(year int, month time.Month, day, hour int) int64 {
+	return time.Date(year, month, day, hour, 0, 0, 0, time.UTC).Unix()
+}
+
+func TestGetLatencyHeatmap(t *testing.T) {
+	s := &heatmapServer{points: map[int64]*AvgChunk{
+		hourUnix(2024, 1, 1, 10): {Count: 10, Latency: 200},          // monday
+		hourUnix(2024, 1, 8, 10): {Count: 10, Loss: 2, Latency: 400}, // next monday, same slot
+		hourUnix(2024, 1, 2, 23): {Count: 4, Latency: 50},            // tuesday
+		hourUnix(2024, 1, 7, 0):  {Count: 5, Loss: 5},                // sunday, everything lost
+		hourUnix(2024, 1, 3, 12): nil,
+	}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	heatmap, err := c.GetLatencyHeatmap("192.0.2.1", "PRAGUE", 2)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := map[[2]int]float64{
+		{int(time.Monday), 10}:  30, // (200 + 400) / (10 + 10)
+		{int(time.Tuesday), 23}: 12.5,
+	}
+	for day := range heatmap {
+		for hour, value := range heatmap[day] {
+			want, ok := expected[[2]int{day, hour}]
+			if !ok {
+				want = -1
+			}
+			if want != value {
+				t.Errorf("%s %02d:00: expected %v, got %v", time.Weekday(day), hour, want, value)
+			}
+		}
+	}
+}
+
+func TestGetLatencyHeatmapErrors(t *testing.T) {
+	c := newTestClient(t, (&heatmapServer{}).ServeHTTP)
+
+	for name, call := range map[string]func() (LatencyHeatmap, error){
+		"zero weeks":    func() (LatencyHeatmap, error) { return c.GetLatencyHeatmap("192.0.2.1", "PRAGUE", 0) },
+		"empty slave":   func() (LatencyHeatmap, error) { return c.GetLatencyHeatmap("192.0.2.1", "", 1) },
+		"unknown ip":    func() (LatencyHeatmap, error) { return c.GetLatencyHeatmap("192.0.2.9", "PRAGUE", 1) },
+		"unknown slave": func() (LatencyHeatmap, error) { return c.GetLatencyHeatmap("192.0.2.1", "BERLIN", 1) },
+	} {
+		heatmap, err := call()
+		if nil == err {
+			t.Errorf("%s: expected error", name)
+		}
+		if emptyHeatmap() != heatmap {
+			t.Errorf("%s: expected empty heatmap", name)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetSlavesStatusHistory returns ordered status changes of slave for period from-to. If server has no history endpoint
+// only current minute is reported: slave is "online" if it has some answered tests in last-minute stats, "offline" otherwise
+func (c *Client) GetSlavesStatusHistory(slave string, from, to time.Time) ([]SlaveStatusPoint, error) {
+	return c.GetSlavesStatusHistoryContext(context.Background(), slave, from, to)
+}
+
+// GetSlavesStatusHistoryContext is same as GetSlavesStatusHistory but requests are bound to ctx
+func (c *Client) GetSlavesStatusHistoryContext(ctx context.Context, slave string, from, to time.Time) ([]SlaveStatusPoint, error) {
+	if "" == slave {
+		return nil, ErrEmptySlaveName
+	}
+	if !from.Before(to) {
+		return nil, errors.New("invalid period: from " + from.Format(time.RFC3339) + " is not before to " + to.Format(time.RFC3339))
+	}
+
+	query := url.Values{
+		"from": []string{strconv.FormatInt(from.Unix(), 10)},
+		"to":   []string{strconv.FormatInt(to.Unix(), 10)},
+	}
+
+	var history []SlaveStatusPoint
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/status/slaves/"+url.PathEscape(slave)+"/history?"+query.Encode(), nil, "", nil), &history)
+	if http.StatusNotFound == status || http.StatusMethodNotAllowed == status {
+		return c.minuteStatusHistory(ctx, slave, from, to)
+	}
+	if nil != err {
+		return nil, err
+	}
+
+	sort.SliceStable(history, func(i, j int) bool { return history[i].Timestamp.Before(history[j].Timestamp) })
+	return history, nil
+}
+
+// minuteStatusHistory builds one-point history from /v1/minute stats of groups tested by slave
+func (c *Client) minuteStatusHistory(ctx context.Context, slave string, from, to time.Time) ([]SlaveStatusPoint, error) {
+	now := time.Now().Truncate(time.Minute)
+	if now.Before(from) || now.After(to) {
+		return []SlaveStatusPoint{}, nil
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	groups := []string{}
+	for _, tests := range []map[string]TestDesc{config.Ping.IPs, config.HTTP.URLs} {
+		for _, desc := range tests {
+			for _, s := range desc.Slaves {
+				if s != slave {
+					continue
+				}
+				for _, group := range desc.Groups {
+					groups = appendUnique(groups, group)
+				}
+			}
+		}
+	}
+	sort.Strings(groups)
+
+	point := SlaveStatusPoint{
+		Timestamp: now,
+		Status:    SlaveStatus{Status: "offline"},
+	}
+
+groups:
+	for _, group := range groups {
+		ips, urls, err := c.GroupLastStatsContext(ctx, strings.TrimSuffix(group, groupSuffix), slave)
+		if nil != err {
+			return nil, err
+		}
+		for _, stats := range []map[string]*AvgChunk{ips, urls} {
+			for _, data := range stats {
+				if nil != data && data.Count > data.Loss {
+					point.Status.Status = "online"
+					break groups
+				}
+			}
+		}
+	}
+
+	return []SlaveStatusPoint{point}, nil
+}
+
+// CalcSlaveUptime returns part of time (0..1) slave was online according to ordered history, every point is treated
+// as valid until next one; last point is counted with weight of average interval (or 1 if it's the only one)
+func CalcSlaveUptime(history []SlaveStatusPoint) float64 {
+	switch len(history) {
+	case 0:
+		return 0
+	case 1:
+		if history[0].Status.Online() {
+			return 1
+		}
+		return 0
+	}
+
+	var total, online time.Duration
+	for i := 0; i < len(history)-1; i++ {
+		d := history[i+1].Timestamp.Sub(history[i].Timestamp)
+		total += d
+		if history[i].Status.Online() {
+			online += d
+		}
+	}
+
+	last := total / time.Duration(len(history)-1)
+	total += last
+	if history[len(history)-1].Status.Online() {
+		online += last
+	}
+
+	if 0 == total {
+		// all points at the same moment
+		count := 0
+		for _, point := range history {
+			if point.Status.Online() {
+				count++
+			}
+		}
+		return float64(count) / float64(len(history))
+	}
+
+	return float64(online) / float64(total)
+}
+
+// GetSlavesPacketLoss returns loss percentage of all tests of every slave aggregated over last minutes
+func (c *Client) GetSlavesPacketLoss(minutes int) (map[string]float64, error) {
+	return c.GetSlavesPacketLossContext(context.Background(), minutes)
+}
+
+// GetSlavesPacketLossContext is same as GetSlavesPacketLoss but request is bound to ctx
+func (c *Client) GetSlavesPacketLossContext(ctx context.Context, minutes int) (map[string]float64, error) {
+	if minutes < 1 {
+		return nil, errors.New("invalid period of " + strconv.Itoa(minutes) + " minutes")
+	}
+
+	var result map[string]float64
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves/loss?minutes="+strconv.Itoa(minutes), &result)
+	return result, err
+}
+
+// GetSlavesPacketLossTimeSeries returns ordered per-minute loss percentage of all tests of slave for period from-to
+func (c *Client) GetSlavesPacketLossTimeSeries(slave string, from, to time.Time) ([]LossPoint, error) {
+	return c.GetSlavesPacketLossTimeSeriesContext(context.Background(), slave, from, to)
+}
+
+// GetSlavesPacketLossTimeSeriesContext is same as GetSlavesPacketLossTimeSeries but request is bound to ctx
+func (c *Client) GetSlavesPacketLossTimeSeriesContext(ctx context.Context, slave string, from, to time.Time) ([]LossPoint, error) {
+	if "" == slave {
+		return nil, ErrEmptySlaveName
+	}
+	if !from.Before(to) {
+		return nil, errors.New("invalid period: from " + from.Format(time.RFC3339) + " is not before to " + to.Format(time.RFC3339))
+	}
+
+	query := url.Values{
+		"from": []string{strconv.FormatInt(from.Unix(), 10)},
+		"to":   []string{strconv.FormatInt(to.Unix(), 10)},
+	}
+
+	var points []LossPoint
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves/"+url.PathEscape(slave)+"/loss?"+query.Encode(), &points)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points, err
+}
+
+// GetSlavesLoadTimeSeries returns probe scheduling load of slave during period from-to ordered by time
+func (c *Client) GetSlavesLoadTimeSeries(slave string, from, to time.Time) ([]LoadPoint, error) {
+	return c.GetSlavesLoadTimeSeriesContext(context.Background(), slave, from, to)
+}
+
+// GetSlavesLoadTimeSeriesContext is same as GetSlavesLoadTimeSeries but request is bound to ctx
+func (c *Client) GetSlavesLoadTimeSeriesContext(ctx context.Context, slave string, from, to time.Time) ([]LoadPoint, error) {
+	if "" == slave {
+		return nil, ErrEmptySlaveName
+	}
+	if !from.Before(to) {
+		return nil, errors.New("invalid period: from " + from.Format(time.RFC3339) + " is not before to " + to.Format(time.RFC3339))
+	}
+
+	query := url.Values{
+		"from": []string{strconv.FormatInt(from.Unix(), 10)},
+		"to":   []string{strconv.FormatInt(to.Unix(), 10)},
+	}
+
+	var points []LoadPoint
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves/"+url.PathEscape(slave)+"/load?"+query.Encode(), &points)
+	sort.SliceStable(points, func(i, j int) bool { return points[i].Timestamp.Before(points[j].Timestamp) })
+	return points, err
+}
+
+// DetectQueueBacklog returns time windows of ordered history when ExecutedProbes/ScheduledProbes was below threshold
+// (like 0.9), consecutive points are merged into one period; points without scheduled probes are never backlog
+func DetectQueueBacklog(history []LoadPoint, threshold float64) []BacklogPeriod {
+	periods := []BacklogPeriod{}
+	var current *BacklogPeriod
+
+	for _, point := range history {
+		ratio := 1.0
+		if point.ScheduledProbes > 0 {
+			ratio = float64(point.ExecutedProbes) / float64(point.ScheduledProbes)
+		}
+
+		if ratio >= threshold {
+			if nil != current {
+				current.End = point.Timestamp
+				periods = append(periods, *current)
+				current = nil
+			}
+			continue
+		}
+
+		if nil == current {
+			current = &BacklogPeriod{Start: point.Timestamp, WorstRatio: ratio}
+		}
+		current.End = point.Timestamp
+		if ratio < current.WorstRatio {
+			current.WorstRatio = ratio
+		}
+		if point.QueueDepth > current.MaxQueue {
+			current.MaxQueue = point.QueueDepth
+		}
+	}
+
+	if nil != current {
+		periods = append(periods, *current)
+	}
+	return periods
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
@@ -9,68 +10,127 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 )
 
-var (
-	basicAuthHeader string
-)
+// SetBasicAuth sets Authorization header for all future requests (replaces bearer token if any)
+func (c *Client) SetBasicAuth(username string, password string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
 
-// SetBasicAuth sets Authorization header for all future requests
-func SetBasicAuth(username string, password string) {
 	if username == "" {
-		basicAuthHeader = ""
+		c.authHeader = ""
 	} else {
-		basicAuthHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+		c.authHeader = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
 	}
 }
 
-// Get executes simple request and decodes json response
-func Get(url string, object interface{}) error {
+// SetBearerToken sets bearer Authorization header for all future requests (replaces basic auth if any)
+func (c *Client) SetBearerToken(token string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
 
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
-	if nil != err {
-		return err
+	if token == "" {
+		c.authHeader = ""
+	} else {
+		c.authHeader = "Bearer " + token
 	}
+}
 
-	if "" != basicAuthHeader {
-		req.Header.Add("Authorization", basicAuthHeader)
-	}
+// SetTokenRefresher sets function called to obtain new bearer token when server responds with 401,
+// request is repeated once with new token and ErrAuthFailed is returned if it's rejected again
+func (c *Client) SetTokenRefresher(fn func() (string, error)) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
+	c.tokenRefresher = fn
+}
 
-	if nil != resp.Body {
-		defer resp.Body.Close()
+// authorization returns current value of Authorization header and token refresher
+func (c *Client) authorization() (string, func() (string, error)) {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
 
-		rawJSON, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
+	return c.authHeader, c.tokenRefresher
+}
 
-		if 0 != len(rawJSON) {
-			return json.Unmarshal(rawJSON, object)
-		}
+// request describes one API call
+type request struct {
+	method      string
+	url         string
+	body        []byte
+	contentType string
+	headers     http.Header
+}
 
-		if 200 != resp.StatusCode {
-			return errors.New(resp.Status)
+// RequestOption changes one API call made by Get/Send/SendForm
+type RequestOption func(*request)
+
+// WithHeaders adds headers to request (overriding default ones set by SetDefaultHeader)
+func WithHeaders(headers map[string]string) RequestOption {
+	return func(r *request) {
+		if nil == r.headers {
+			r.headers = http.Header{}
+		}
+		for key, value := range headers {
+			r.headers.Set(key, value)
 		}
 	}
+}
 
-	if 200 != resp.StatusCode {
-		return errors.New(resp.Status)
+// newRequest prepares request applying opts
+func newRequest(method string, url string, body []byte, contentType string, opts []RequestOption) *request {
+	r := &request{
+		method:      method,
+		url:         url,
+		body:        body,
+		contentType: contentType,
+	}
+	for _, opt := range opts {
+		opt(r)
 	}
+	return r
+}
 
-	return nil
+// SetDefaultHeader sets header added to all future requests, empty value removes header
+func (c *Client) SetDefaultHeader(key string, value string) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if "" == value {
+		c.headers.Del(key)
+		return
+	}
+	if nil == c.headers {
+		c.headers = http.Header{}
+	}
+	c.headers.Set(key, value)
+}
+
+// defaultHeaders returns copy of headers set by SetDefaultHeader
+func (c *Client) defaultHeaders() http.Header {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+
+	return c.headers.Clone()
+}
+
+// Get executes simple request and decodes json response
+func (c *Client) Get(url string, object interface{}, opts ...RequestOption) error {
+	return c.GetContext(context.Background(), url, object, opts...)
+}
+
+// GetContext executes simple request bound to ctx and decodes json response
+func (c *Client) GetContext(ctx context.Context, url string, object interface{}, opts ...RequestOption) error {
+	_, err := c.execute(ctx, newRequest("GET", url, nil, "", opts), object)
+	return err
 }
 
 // wrapper around send and standard result
-func _okResultSend(method string, url string, payload interface{}) error {
+func (c *Client) _okResultSend(ctx context.Context, method string, url string, payload interface{}) error {
 	var r result
 
-	err := Send(method, url, payload, &r)
+	err := c.SendContext(ctx, method, url, payload, &r)
 	if nil != err {
 		return err
 	}
@@ -87,104 +147,194 @@ func _okResultSend(method string, url string, payload interface{}) error {
 }
 
 // Send json-encoded payload to server using specified method and decode response to object
-func Send(method string, url string, payload interface{}, object interface{}) error {
+func (c *Client) Send(method string, url string, payload interface{}, object interface{}, opts ...RequestOption) error {
+	return c.SendContext(context.Background(), method, url, payload, object, opts...)
+}
 
-	var req *http.Request
-	var err error
+// SendContext is same as Send but request is bound to ctx
+func (c *Client) SendContext(ctx context.Context, method string, url string, payload interface{}, object interface{}, opts ...RequestOption) error {
 
-	client := &http.Client{}
+	var raw []byte
 
 	if nil != payload {
-		raw, err := json.Marshal(payload)
+		var err error
+		raw, err = json.Marshal(payload)
 		if nil != err {
 			return err
 		}
-		req, err = http.NewRequest(method, url, bytes.NewBuffer(raw))
-	} else {
-		req, err = http.NewRequest(method, url, nil)
 	}
 
-	if nil != err {
-		return err
+	_, err := c.execute(ctx, newRequest(method, url, raw, "application/json", opts), object)
+	return err
+}
+
+// SendForm form payload to server using specified method and decode response to object
+func (c *Client) SendForm(method string, url string, payload url.Values, object interface{}, opts ...RequestOption) error {
+	return c.SendFormContext(context.Background(), method, url, payload, object, opts...)
+}
+
+// SendFormContext is same as SendForm but request is bound to ctx
+func (c *Client) SendFormContext(ctx context.Context, method string, url string, payload url.Values, object interface{}, opts ...RequestOption) error {
+	_, err := c.execute(ctx, newRequest(method, url, []byte(payload.Encode()), "application/x-www-form-urlencoded", opts), object)
+	return err
+}
+
+// execute runs request (refreshing token on 401 if configured), decodes json response to object and returns last response status code
+func (c *Client) execute(ctx context.Context, r *request, object interface{}) (status int, err error) {
+	defer func() {
+		if nil != err {
+			c.log().Error("request error", "method", r.method, "url", r.url, "status", status, "error", err)
+		}
+	}()
+
+	if c.skipDryRun(r, object) {
+		return http.StatusOK, nil
 	}
 
-	if "" != basicAuthHeader {
-		req.Header.Add("Authorization", basicAuthHeader)
+	status, err = c.executeRetry(ctx, r, object)
+
+	_, refresher := c.authorization()
+	if http.StatusUnauthorized != status || nil == refresher {
+		return status, err
 	}
-	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	token, err := refresher()
+	if nil != err {
+		return status, err
 	}
+	c.SetBearerToken(token)
 
-	if nil != resp.Body {
-		defer resp.Body.Close()
+	status, err = c.executeRetry(ctx, r, object)
+	if http.StatusUnauthorized == status {
+		return status, ErrAuthFailed
+	}
 
-		rawJSON, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
-		}
+	return status, err
+}
 
-		if 0 != len(rawJSON) {
-			return json.Unmarshal(rawJSON, object)
+// executeRetry runs request retrying it if configured
+func (c *Client) executeRetry(ctx context.Context, r *request, object interface{}) (int, error) {
+	c.optionsMu.RLock()
+	retry := c.retry
+	c.optionsMu.RUnlock()
+	if nil == retry {
+		return c.do(ctx, r, object)
+	}
+
+	delay := retry.InitialDelay
+	for attempt := 1; ; attempt++ {
+		status, err := c.do(ctx, r, object)
+		if attempt >= retry.MaxAttempts || !retry.retryable(ctx, status, err) {
+			return status, err
 		}
 
-		if 200 != resp.StatusCode {
-			return errors.New(resp.Status)
+		c.log().Error("request failed, retrying", "method", r.method, "url", r.url, "status", status, "error", err, "attempt", attempt)
+
+		if err := sleepContext(ctx, jitter(delay)); nil != err {
+			return status, err
 		}
+		delay = retry.next(delay)
+	}
+}
 
-		return nil
+// do executes one http request and returns response status code (0 if there was no response)
+func (c *Client) do(ctx context.Context, r *request, object interface{}) (int, error) {
+
+	if nil != c.cache && "GET" == r.method {
+		if raw, ok := c.cache.get(r.url); ok {
+			return http.StatusOK, c.unmarshal(raw, object)
+		}
 	}
 
-	if 200 != resp.StatusCode {
-		return errors.New(resp.Status)
+	if err := c.wait(ctx); nil != err {
+		return 0, err
 	}
 
-	return nil
-}
+	compact, gzipped := c.encoding()
 
-// SendForm form payload to server using specified method and decode response to object
-func SendForm(method string, url string, payload url.Values, object interface{}) error {
+	requestURL := r.url
+	if compact && "GET" == r.method {
+		requestURL = compactURL(requestURL)
+	}
+
+	var req *http.Request
+	var err error
 
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, strings.NewReader(payload.Encode()))
+	if nil != r.body {
+		req, err = http.NewRequestWithContext(ctx, r.method, requestURL, bytes.NewReader(r.body))
+	} else {
+		req, err = http.NewRequestWithContext(ctx, r.method, requestURL, nil)
+	}
 	if nil != err {
-		return err
+		return 0, err
 	}
 
-	if "" != basicAuthHeader {
-		req.Header.Add("Authorization", basicAuthHeader)
+	for key, values := range c.defaultHeaders() {
+		req.Header[key] = values
+	}
+	for key, values := range r.headers {
+		req.Header[key] = values
+	}
+	if auth, _ := c.authorization(); "" != auth {
+		req.Header.Set("Authorization", auth)
+	}
+	if "" != r.contentType {
+		req.Header.Set("Content-Type", r.contentType)
+	}
+	if gzipped {
+		// transport decompresses transparently only if Accept-Encoding isn't set explicitly
+		req.Header.Set("Accept-Encoding", "gzip")
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := client.Do(req)
+	start := time.Now()
+	resp, err := c.client().Do(req)
 	if err != nil {
-		return err
+		c.log().Debug("request", "method", r.method, "url", r.url, "error", err, "duration", time.Since(start))
+		return 0, err
 	}
+	c.log().Debug("request", "method", r.method, "url", r.url, "status", resp.StatusCode, "duration", time.Since(start))
 
 	if nil != resp.Body {
 		defer resp.Body.Close()
 
-		rawJSON, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return err
+		body, err := decodedBody(resp)
+		if nil != err {
+			return resp.StatusCode, err
 		}
+		defer body.Close()
 
-		if 0 != len(rawJSON) {
-			return json.Unmarshal(rawJSON, object)
+		rawJSON, err := ioutil.ReadAll(body)
+		if err != nil {
+			return resp.StatusCode, err
 		}
 
-		if 200 != resp.StatusCode {
-			return errors.New(resp.Status)
+		if 0 != len(rawJSON) {
+			err := c.unmarshal(rawJSON, object)
+			c.cacheResponse(r, resp.StatusCode, err, rawJSON)
+			return resp.StatusCode, err
 		}
-
-		return nil
 	}
 
 	if 200 != resp.StatusCode {
-		return errors.New(resp.Status)
+		return resp.StatusCode, errors.New(resp.Status)
 	}
 
-	return nil
+	c.cacheResponse(r, resp.StatusCode, nil, nil)
+	return resp.StatusCode, nil
+}
+
+// cacheResponse stores successful GET response in cache or clears cache after successful change
+func (c *Client) cacheResponse(r *request, status int, err error, raw []byte) {
+	if nil == c.cache || nil != err || status < 200 || status > 299 {
+		return
+	}
+	if "GET" != r.method {
+		c.cache.clear()
+		return
+	}
+	if 0 == len(raw) {
+		// nothing to unmarshal on cache hit, so empty responses are always fetched again
+		return
+	}
+	c.cache.set(r.url, strings.TrimPrefix(r.url, c.url), raw)
 }
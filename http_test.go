@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient starts server with handler and returns client connected to it
+func newTestClient(t testing.TB, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	return New(server.URL, "admin", "secret", opts...)
+}
+
+// writeTestJSON encodes object as response body
+func writeTestJSON(w http.ResponseWriter, object interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(object)
+}
+
+// captureLogger records all messages as "LEVEL msg"
+type captureLogger struct {
+	mu    sync.Mutex
+	lines []string
+	args  [][]interface{}
+}
+
+func (l *captureLogger) Debug(msg string, args ...interface{}) {
+	l.add("DEBUG "+msg, args)
+}
+
+func (l *captureLogger) Error(msg string, args ...interface{}) {
+	l.add("ERROR "+msg, args)
+}
+
+func (l *captureLogger) add(line string, args []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.lines = append(l.lines, line)
+	l.args = append(l.args, args)
+}
+
+// messages returns recorded lines starting with prefix
+func (l *captureLogger) messages(prefix string) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := []string{}
+	for _, line := range l.lines {
+		if strings.HasPrefix(line, prefix) {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+func TestContextCancelAbortsRequest(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	start := time.Now()
+	_, err := c.GetConfigInfoContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("request wasn't aborted promptly: %v", time.Since(start))
+	}
+}
+
+func TestContextDeadlineAbortsRequest(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err := c.DeleteIPContext(ctx, "1.1.1.1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestCanceledContextSkipsRequest(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeTestJSON(w, map[string]string{})
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := c.GetSlavesIPsContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if n := atomic.LoadInt32(&calls); 0 != n {
+		t.Fatalf("server was called %d times", n)
+	}
+}
+
+func TestGetDecodesResponse(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if "/v1/slaves" != r.URL.Path {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || "admin" != user || "secret" != pass {
+			t.Errorf("unexpected auth %q %q", user, pass)
+		}
+		writeTestJSON(w, map[string]string{"PRAGUE": "1.1.1.1:3030"})
+	})
+
+	slaves, err := c.GetSlavesIPsContext(context.Background())
+	if nil != err {
+		t.Fatal(err)
+	}
+	if "1.1.1.1" != slaves["PRAGUE"] || 1 != len(slaves) {
+		t.Fatalf("unexpected result %v", slaves)
+	}
+}
+
+func TestCustomHeaders(t *testing.T) {
+	received := make(chan http.Header, 1)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		writeTestJSON(w, result{Result: "OK"})
+	})
+
+	c.SetDefaultHeader("Proxy-Authorization", "Basic cHJveHk6cHJveHk=")
+	c.SetDefaultHeader("X-Tenant", "default")
+
+	var r result
+	if err := c.Send("PUT", c.url+"/v1/test", map[string]string{}, &r, WithHeaders(map[string]string{"X-Tenant": "tenant-a", "X-Request-Id": "42"})); nil != err {
+		t.Fatal(err)
+	}
+	h := <-received
+	if "Basic cHJveHk6cHJveHk=" != h.Get("Proxy-Authorization") || "tenant-a" != h.Get("X-Tenant") || "42" != h.Get("X-Request-Id") {
+		t.Fatalf("unexpected headers %v", h)
+	}
+	if "application/json" != h.Get("Content-Type") || !strings.HasPrefix(h.Get("Authorization"), "Basic ") {
+		t.Fatalf("per-request headers shouldn't replace content type or auth: %v", h)
+	}
+
+	// per-request headers don't stick, removed default header isn't sent anymore
+	c.SetDefaultHeader("X-Tenant", "")
+	if err := c.Get(c.url+"/v1/test", &r); nil != err {
+		t.Fatal(err)
+	}
+	h = <-received
+	if "" != h.Get("X-Tenant") || "" != h.Get("X-Request-Id") || "Basic cHJveHk6cHJveHk=" != h.Get("Proxy-Authorization") {
+		t.Fatalf("unexpected headers of second request %v", h)
+	}
+}
+
+func TestCustomHeadersForm(t *testing.T) {
+	received := make(chan http.Header, 1)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- r.Header.Clone()
+		writeTestJSON(w, map[string]bool{})
+	})
+
+	var users map[string]bool
+	if err := c.SendForm("PUT", c.url+"/v1/users", nil, &users, WithHeaders(map[string]string{"Proxy-Authorization": "Bearer proxy"})); nil != err {
+		t.Fatal(err)
+	}
+	h := <-received
+	if "Bearer proxy" != h.Get("Proxy-Authorization") || "application/x-www-form-urlencoded" != h.Get("Content-Type") {
+		t.Fatalf("unexpected headers %v", h)
+	}
+}
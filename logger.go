@@ -0,0 +1,86 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Logger receives debug and error messages of client, args are key-value pairs like "status", 200
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+// NoopLogger discards all messages, it's used by default
+type NoopLogger struct{}
+
+// Debug implements Logger
+func (NoopLogger) Debug(msg string, args ...interface{}) {}
+
+// Error implements Logger
+func (NoopLogger) Error(msg string, args ...interface{}) {}
+
+// StdLogger writes messages using log.Printf (or Logger if set) as "LEVEL msg key=value ..."
+type StdLogger struct {
+	Logger *log.Logger
+	Debugs bool // debug messages are skipped unless set
+}
+
+// Debug implements Logger
+func (l StdLogger) Debug(msg string, args ...interface{}) {
+	if l.Debugs {
+		l.print("DEBUG", msg, args)
+	}
+}
+
+// Error implements Logger
+func (l StdLogger) Error(msg string, args ...interface{}) {
+	l.print("ERROR", msg, args)
+}
+
+func (l StdLogger) print(level string, msg string, args []interface{}) {
+	var b strings.Builder
+	b.WriteString(level)
+	b.WriteByte(' ')
+	b.WriteString(msg)
+	for i := 0; i < len(args); i += 2 {
+		if i+1 < len(args) {
+			fmt.Fprintf(&b, " %v=%v", args[i], args[i+1])
+		} else {
+			fmt.Fprintf(&b, " %v", args[i])
+		}
+	}
+
+	if nil != l.Logger {
+		l.Logger.Print(b.String())
+	} else {
+		log.Print(b.String())
+	}
+}
+
+// SetLogger sets logger for all future requests, nil means NoopLogger
+func (c *Client) SetLogger(l Logger) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	c.logger = l
+}
+
+// WithLogger is same as SetLogger usable with New and Configure
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.SetLogger(l)
+	}
+}
+
+// log returns current logger
+func (c *Client) log() Logger {
+	c.authMu.RLock()
+	defer c.authMu.RUnlock()
+
+	if nil == c.logger {
+		return NoopLogger{}
+	}
+	return c.logger
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// arg returns value of key in logged args
+func arg(args []interface{}, key string) interface{} {
+	for i := 0; i+1 < len(args); i += 2 {
+		if key == args[i] {
+			return args[i+1]
+		}
+	}
+	return nil
+}
+
+func TestLoggerRetrySequence(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503, 502, 200}}
+	logger := &captureLogger{}
+	c := newTestClient(t, h.ServeHTTP, WithLogger(logger), WithRetry(RetryConfig{MaxAttempts: 5, InitialDelay: time.Millisecond}))
+
+	if _, err := c.GetSlavesIPs(); nil != err {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"DEBUG request",
+		"ERROR request failed, retrying",
+		"DEBUG request",
+		"ERROR request failed, retrying",
+		"DEBUG request",
+	}
+	if !reflect.DeepEqual(expected, logger.lines) {
+		t.Fatalf("expected %v, got %v", expected, logger.lines)
+	}
+
+	for i, status := range []int{503, 502, 200} {
+		args := logger.args[2*i]
+		if status != arg(args, "status") || "GET" != arg(args, "method") || !strings.HasSuffix(arg(args, "url").(string), "/v1/slaves") {
+			t.Errorf("request %d: unexpected args %v", i, args)
+		}
+		if _, ok := arg(args, "duration").(time.Duration); !ok {
+			t.Errorf("request %d: missing duration in %v", i, args)
+		}
+	}
+	for i, status := range []int{503, 502} {
+		args := logger.args[2*i+1]
+		if status != arg(args, "status") || i+1 != arg(args, "attempt") {
+			t.Errorf("retry %d: unexpected args %v", i, args)
+		}
+	}
+}
+
+func TestLoggerRetryExhausted(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503}}
+	logger := &captureLogger{}
+	c := newTestClient(t, h.ServeHTTP, WithLogger(logger), WithRetry(RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond}))
+
+	if _, err := c.GetSlavesIPs(); nil == err {
+		t.Fatal("expected error")
+	}
+
+	if n := len(logger.messages("DEBUG request")); 3 != n {
+		t.Fatalf("expected 3 request lines, got %v", logger.lines)
+	}
+	if n := len(logger.messages("ERROR request failed, retrying")); 2 != n {
+		t.Fatalf("expected 2 retry lines, got %v", logger.lines)
+	}
+	final := logger.messages("ERROR request error")
+	if 1 != len(final) || "ERROR request error" != logger.lines[len(logger.lines)-1] {
+		t.Fatalf("expected final error line, got %v", logger.lines)
+	}
+	if args := logger.args[len(logger.args)-1]; 503 != arg(args, "status") || nil == arg(args, "error") {
+		t.Fatalf("unexpected final error args %v", args)
+	}
+}
+
+func TestStdLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l := StdLogger{Logger: log.New(&buf, "", 0)}
+
+	l.Debug("hidden", "a", 1)
+	l.Error("failed", "status", 503, "attempt", 2, "odd")
+	if "ERROR failed status=503 attempt=2 odd\n" != buf.String() {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+
+	buf.Reset()
+	l.Debugs = true
+	l.Debug("request", "method", "GET")
+	if "DEBUG request method=GET\n" != buf.String() {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
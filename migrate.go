@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// migrateConcurrency limits parallel migrations when src client has no concurrency limit set
+const migrateConcurrency = 8
+
+// MigrationReport is result of MigrateIPs
+type MigrationReport struct {
+	DryRun   bool
+	Migrated []string         // ips copied to destination (or that would be copied in dry-run mode)
+	Deleted  []string         // ips removed from source (or that would be removed in dry-run mode)
+	Failed   map[string]error // ips which migration failed
+}
+
+// MigrateIPs copies ips with their configuration from src to dst master and optionally removes them from src;
+// concurrency is limited by src client settings (see WithConcurrency) or to 8 parallel migrations if not set
+func MigrateIPs(src, dst *Client, ips []string, deleteSrc bool) (MigrationReport, error) {
+	return MigrateIPsContext(context.Background(), src, dst, ips, deleteSrc)
+}
+
+// MigrateIPsContext is same as MigrateIPs but requests are bound to ctx
+func MigrateIPsContext(ctx context.Context, src, dst *Client, ips []string, deleteSrc bool) (MigrationReport, error) {
+	return migrateIPs(ctx, src, dst, ips, deleteSrc, false)
+}
+
+// DryRunMigrateIPs reads configuration of ips from src and logs (as debug messages of src and dst loggers)
+// actions MigrateIPs would do without executing them, returned report lists planned actions
+func DryRunMigrateIPs(src, dst *Client, ips []string, deleteSrc bool) (MigrationReport, error) {
+	return DryRunMigrateIPsContext(context.Background(), src, dst, ips, deleteSrc)
+}
+
+// DryRunMigrateIPsContext is same as DryRunMigrateIPs but requests are bound to ctx
+func DryRunMigrateIPsContext(ctx context.Context, src, dst *Client, ips []string, deleteSrc bool) (MigrationReport, error) {
+	return migrateIPs(ctx, src, dst, ips, deleteSrc, true)
+}
+
+// migrateIPs implements MigrateIPs and DryRunMigrateIPs
+func migrateIPs(ctx context.Context, src, dst *Client, ips []string, deleteSrc bool, dryRun bool) (MigrationReport, error) {
+	report := MigrationReport{
+		DryRun: dryRun,
+		Failed: map[string]error{},
+	}
+	var mu sync.Mutex
+
+	limit := src.concurrencyLimit()
+	if limit <= 0 {
+		limit = migrateConcurrency
+	}
+
+	forEachLimit(ips, limit, func(ip string) {
+		deleted := false
+		err := func() error {
+			detail, err := src.GetIPDetailsContext(ctx, ip)
+			if nil != err {
+				return err
+			}
+			if dryRun {
+				dst.log().Debug("dry-run: would add ip", "ip", ip, "groups", detail.Groups, "slaves", detail.Slaves)
+				if deleteSrc {
+					src.log().Debug("dry-run: would delete ip", "ip", ip)
+					deleted = true
+				}
+				return nil
+			}
+
+			if err := dst.AddIPsRawContext(ctx, map[string]TestDesc{ip: detail.TestDesc}); nil != err {
+				return err
+			}
+			if !deleteSrc {
+				return nil
+			}
+			if err := src.DeleteIPContext(ctx, ip); nil != err {
+				return err
+			}
+			deleted = true
+			return nil
+		}()
+
+		mu.Lock()
+		defer mu.Unlock()
+		if nil != err {
+			report.Failed[ip] = err
+			return
+		}
+		report.Migrated = append(report.Migrated, ip)
+		if deleted {
+			report.Deleted = append(report.Deleted, ip)
+		}
+	})
+
+	sort.Strings(report.Migrated)
+	sort.Strings(report.Deleted)
+
+	return report, MultiError(report.Failed).errOrNil()
+}
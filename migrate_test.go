@@ -0,0 +1,171 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// migrationMaster is fake master serving single IP configs, /v1/mconfig/add and IP deletion
+type migrationMaster struct {
+	mu          sync.Mutex
+	ips         map[string]TestDesc
+	writes      []string
+	delay       time.Duration
+	inFlight    int
+	maxInFlight int
+}
+
+func (m *migrationMaster) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	m.inFlight++
+	if m.inFlight > m.maxInFlight {
+		m.maxInFlight = m.inFlight
+	}
+	m.mu.Unlock()
+
+	time.Sleep(m.delay)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inFlight--
+
+	ip := strings.TrimPrefix(r.URL.Path, "/v1/config/ping/")
+	switch {
+	case "GET" == r.Method && ip != r.URL.Path:
+		desc, ok := m.ips[ip]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		writeTestJSON(w, IPDetail{TestDesc: desc})
+
+	case "DELETE" == r.Method && ip != r.URL.Path:
+		m.writes = append(m.writes, "DELETE "+ip)
+		delete(m.ips, ip)
+		writeTestJSON(w, result{Result: "OK"})
+
+	case "PUT" == r.Method && "/v1/mconfig/add" == r.URL.Path:
+		var payload struct {
+			IPs map[string]TestDesc `json:"ips"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		for ip, desc := range payload.IPs {
+			m.writes = append(m.writes, "ADD "+ip)
+			m.ips[ip] = desc
+		}
+		writeTestJSON(w, result{Result: "OK"})
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (m *migrationMaster) state() (map[string]TestDesc, []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ips := make(map[string]TestDesc, len(m.ips))
+	for ip, desc := range m.ips {
+		ips[ip] = desc
+	}
+	return ips, append([]string{}, m.writes...)
+}
+
+func TestMigrateIPs(t *testing.T) {
+	src := &migrationMaster{ips: map[string]TestDesc{
+		"1.1.1.1": {Description: "cloudflare", Groups: []string{"DNS->"}, Slaves: []string{"PRAGUE"}},
+		"8.8.8.8": {Description: "google", Groups: []string{"DNS->"}, Slaves: []string{"LONDON"}},
+		"9.9.9.9": {Description: "quad9"},
+	}}
+	dst := &migrationMaster{ips: map[string]TestDesc{}}
+
+	report, err := MigrateIPs(newTestClient(t, src.ServeHTTP), newTestClient(t, dst.ServeHTTP),
+		[]string{"1.1.1.1", "8.8.8.8", "4.4.4.4"}, true)
+
+	if multi, ok := err.(MultiError); !ok || 1 != len(multi) || nil == multi["4.4.4.4"] {
+		t.Fatalf("expected error for 4.4.4.4 only, got %v", err)
+	}
+	if !reflect.DeepEqual([]string{"1.1.1.1", "8.8.8.8"}, report.Migrated) || !reflect.DeepEqual(report.Migrated, report.Deleted) || report.DryRun {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	srcIPs, _ := src.state()
+	dstIPs, _ := dst.state()
+	if 1 != len(srcIPs) || 2 != len(dstIPs) || "google" != dstIPs["8.8.8.8"].Description || "PRAGUE" != dstIPs["1.1.1.1"].Slaves[0] {
+		t.Fatalf("unexpected state src=%v dst=%v", srcIPs, dstIPs)
+	}
+}
+
+func TestMigrateIPsKeepSource(t *testing.T) {
+	src := &migrationMaster{ips: map[string]TestDesc{"1.1.1.1": {Description: "cloudflare"}}}
+	dst := &migrationMaster{ips: map[string]TestDesc{}}
+
+	report, err := MigrateIPs(newTestClient(t, src.ServeHTTP), newTestClient(t, dst.ServeHTTP), []string{"1.1.1.1"}, false)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 1 != len(report.Migrated) || 0 != len(report.Deleted) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if _, writes := src.state(); 0 != len(writes) {
+		t.Fatalf("source was changed: %v", writes)
+	}
+}
+
+func TestDryRunMigrateIPs(t *testing.T) {
+	src := &migrationMaster{ips: map[string]TestDesc{
+		"1.1.1.1": {Description: "cloudflare"},
+		"8.8.8.8": {Description: "google"},
+	}}
+	dst := &migrationMaster{ips: map[string]TestDesc{}}
+	logger := &captureLogger{}
+
+	report, err := DryRunMigrateIPs(newTestClient(t, src.ServeHTTP, WithLogger(logger)), newTestClient(t, dst.ServeHTTP, WithLogger(logger)),
+		[]string{"1.1.1.1", "8.8.8.8"}, true)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if !report.DryRun || 2 != len(report.Migrated) || 2 != len(report.Deleted) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	_, srcWrites := src.state()
+	_, dstWrites := dst.state()
+	if 0 != len(srcWrites)+len(dstWrites) {
+		t.Fatalf("dry-run changed masters: %v %v", srcWrites, dstWrites)
+	}
+
+	if adds := logger.messages("DEBUG dry-run: would add ip"); 2 != len(adds) {
+		t.Errorf("expected 2 logged adds, got %v", logger.lines)
+	}
+	if deletes := logger.messages("DEBUG dry-run: would delete ip"); 2 != len(deletes) {
+		t.Errorf("expected 2 logged deletes, got %v", logger.lines)
+	}
+}
+
+func TestMigrateIPsDefaultConcurrencyLimit(t *testing.T) {
+	src := &migrationMaster{ips: map[string]TestDesc{}, delay: 10 * time.Millisecond}
+	ips := []string{}
+	for i := 1; i <= 3*migrateConcurrency; i++ {
+		ip := "10.0.0." + strconv.Itoa(i)
+		src.ips[ip] = TestDesc{}
+		ips = append(ips, ip)
+	}
+	dst := &migrationMaster{ips: map[string]TestDesc{}}
+
+	if _, err := MigrateIPs(newTestClient(t, src.ServeHTTP), newTestClient(t, dst.ServeHTTP), ips, false); nil != err {
+		t.Fatal(err)
+	}
+
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	if src.maxInFlight > migrateConcurrency {
+		t.Fatalf("expected at most %d parallel requests, got %d", migrateConcurrency, src.maxInFlight)
+	}
+}
@@ -0,0 +1,160 @@
+package api
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// NamingConvention is style of json field names used by master
+type NamingConvention int
+
+// supported naming conventions
+const (
+	LowerCamelCase NamingConvention = iota // avgRtt, used by current masters (default)
+	CamelCase                              // AvgRtt
+	SnakeCase                              // avg_rtt, used by some older masters
+)
+
+// WithJSONNamingConvention makes client remap field names of json responses from convention before decoding,
+// only object keys decoded into struct fields are renamed so map keys (IPs, groups, slaves...) are kept intact.
+// CamelCase needs no remapping as field names are matched case-insensitively
+func WithJSONNamingConvention(convention NamingConvention) Option {
+	return func(c *Client) {
+		c.authMu.Lock()
+		defer c.authMu.Unlock()
+
+		c.naming = convention
+	}
+}
+
+// unmarshal decodes json response to object according to naming convention of client
+func (c *Client) unmarshal(raw []byte, object interface{}) error {
+	c.authMu.RLock()
+	naming := c.naming
+	c.authMu.RUnlock()
+
+	if SnakeCase != naming || nil == object {
+		return json.Unmarshal(raw, object)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); nil != err {
+		return err
+	}
+
+	remapped, err := json.Marshal(remapKeys(reflect.TypeOf(object), value))
+	if nil != err {
+		return err
+	}
+	return json.Unmarshal(remapped, object)
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// remapKeys renames keys of json objects decoded into structs of type t to names of matching fields
+// (ignoring case and underscores), everything else is kept untouched
+func remapKeys(t reflect.Type, value interface{}) interface{} {
+	for reflect.Ptr == t.Kind() {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return value
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		fields := jsonFields(t)
+		result := make(map[string]interface{}, len(object))
+		for key, item := range object {
+			field, ok := fields[normalizedKey(key)]
+			if !ok {
+				result[key] = item
+				continue
+			}
+			result[field.name] = remapKeys(field.typ, item)
+		}
+		return result
+
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return value
+		}
+		for key, item := range object {
+			object[key] = remapKeys(t.Elem(), item)
+		}
+		return object
+
+	case reflect.Slice, reflect.Array:
+		list, ok := value.([]interface{})
+		if !ok {
+			return value
+		}
+		for i, item := range list {
+			list[i] = remapKeys(t.Elem(), item)
+		}
+		return list
+	}
+
+	return value
+}
+
+// jsonField is json name and type of struct field
+type jsonField struct {
+	name string
+	typ  reflect.Type
+}
+
+// jsonFields returns exported fields of struct (including promoted ones of embedded structs) by normalized json name
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := map[string]jsonField{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if "-" == tag {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+
+		if f.Anonymous && "" == name {
+			embedded := f.Type
+			if reflect.Ptr == embedded.Kind() {
+				embedded = embedded.Elem()
+			}
+			if reflect.Struct == embedded.Kind() {
+				for key, field := range jsonFields(embedded) {
+					if _, ok := fields[key]; !ok {
+						fields[key] = field
+					}
+				}
+				continue
+			}
+		}
+		if "" != f.PkgPath {
+			continue // unexported
+		}
+
+		if "" == name {
+			name = f.Name
+		}
+		fields[normalizedKey(name)] = jsonField{name: name, typ: f.Type}
+	}
+	return fields
+}
+
+// normalizedKey returns lower-case key without underscores, so avg_rtt, AvgRtt and avgRTT are same
+func normalizedKey(key string) string {
+	return strings.ToLower(strings.Replace(key, "_", "", -1))
+}
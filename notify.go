@@ -0,0 +1,339 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// notification channel types
+const (
+	ChannelEmail     = "email"
+	ChannelSlack     = "slack"
+	ChannelPagerDuty = "pagerduty"
+	ChannelWebhook   = "webhook"
+)
+
+// NotificationChannel is one push notification configuration (see /v1/notify in api.md)
+//
+// Config keys: "method", "payload", "contentType", "frequency", "frequencyPerIP", "minSlavesFailed" and
+// "header:Name" for additional HTTP headers; missing method, contentType and payload get defaults by Type.
+// Email channels need http(s) mail gateway Endpoint as server sends only HTTP requests
+type NotificationChannel struct {
+	ID       string // name of configuration prefixed by type ("email:noc"), used also in GroupConfig.PushNotifyA and AlertRule.Channels
+	Type     string // ChannelEmail, ChannelSlack, ChannelPagerDuty or ChannelWebhook
+	Endpoint string // url called by server
+	Config   map[string]string
+}
+
+// pushNotify is push notification configuration as stored by server
+type pushNotify struct {
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	Payload         string            `json:"payload"`
+	ContentType     string            `json:"contentType"`
+	Headers         map[string]string `json:"headers"`
+	Frequency       int               `json:"frequency"`
+	FrequencyPerIP  int               `json:"frequencyPerIP"`
+	MinSlavesFailed int               `json:"minSlavesFailed"`
+}
+
+// default payloads by channel type, placeholders are replaced by server
+var channelPayloads = map[string]string{
+	ChannelSlack:     `{"text":"<*GROUP*> <*IP*>: loss <*LOSS*>%, latency <*LATENCY*>ms from <*SLAVE*>"}`,
+	ChannelPagerDuty: `{"event_action":"trigger","payload":{"summary":"<*IP*>: loss <*LOSS*>%, latency <*LATENCY*>ms","source":"<*SLAVE*>","severity":"error","group":"<*GROUP*>"}}`,
+	ChannelWebhook:   `{"ip":"<*IP*>","group":"<*GROUP*>","slave":"<*SLAVE*>","loss":"<*LOSS*>","latency":"<*LATENCY*>"}`,
+	ChannelEmail:     `{"subject":"cocopacket: <*IP*> problem","text":"<*GROUP*> <*IP*>: loss <*LOSS*>%, latency <*LATENCY*>ms from <*SLAVE*>"}`,
+}
+
+// channel converts server configuration to NotificationChannel, type is taken from ID prefix or guessed by endpoint
+func (p pushNotify) channel(id string) NotificationChannel {
+	ch := NotificationChannel{
+		ID:       id,
+		Endpoint: p.URL,
+		Config:   map[string]string{},
+	}
+
+	if i := strings.Index(id, ":"); i > 0 {
+		if _, ok := channelPayloads[id[:i]]; ok {
+			ch.Type = id[:i]
+		}
+	}
+	if "" == ch.Type {
+		switch host := strings.ToLower(hostOf(p.URL)); {
+		case strings.HasSuffix(host, "slack.com"):
+			ch.Type = ChannelSlack
+		case strings.HasSuffix(host, "pagerduty.com"):
+			ch.Type = ChannelPagerDuty
+		default:
+			ch.Type = ChannelWebhook
+		}
+	}
+
+	for key, value := range map[string]string{"method": p.Method, "payload": p.Payload, "contentType": p.ContentType} {
+		if "" != value {
+			ch.Config[key] = value
+		}
+	}
+	for key, value := range map[string]int{"frequency": p.Frequency, "frequencyPerIP": p.FrequencyPerIP, "minSlavesFailed": p.MinSlavesFailed} {
+		if 0 != value {
+			ch.Config[key] = strconv.Itoa(value)
+		}
+	}
+	for header, value := range p.Headers {
+		ch.Config["header:"+header] = value
+	}
+
+	return ch
+}
+
+// push converts NotificationChannel to server configuration
+func (ch NotificationChannel) push() (pushNotify, error) {
+	if _, ok := channelPayloads[ch.Type]; !ok {
+		return pushNotify{}, errors.New("unsupported notification channel type " + ch.Type)
+	}
+	if u, err := url.Parse(ch.Endpoint); nil != err || ("http" != u.Scheme && "https" != u.Scheme) || "" == u.Host {
+		return pushNotify{}, errors.New("invalid notification channel endpoint " + ch.Endpoint)
+	}
+
+	p := pushNotify{
+		Method:      "POST",
+		URL:         ch.Endpoint,
+		Payload:     channelPayloads[ch.Type],
+		ContentType: "application/json",
+		Headers:     map[string]string{},
+	}
+
+	for key, value := range ch.Config {
+		var err error
+		switch key {
+		case "method":
+			p.Method = value
+		case "payload":
+			p.Payload = value
+		case "contentType":
+			p.ContentType = value
+		case "frequency":
+			p.Frequency, err = strconv.Atoi(value)
+		case "frequencyPerIP":
+			p.FrequencyPerIP, err = strconv.Atoi(value)
+		case "minSlavesFailed":
+			p.MinSlavesFailed, err = strconv.Atoi(value)
+		default:
+			if !strings.HasPrefix(key, "header:") {
+				return pushNotify{}, errors.New("unknown notification channel config key " + key)
+			}
+			p.Headers[strings.TrimPrefix(key, "header:")] = value
+		}
+		if nil != err {
+			return pushNotify{}, errors.New("invalid " + key + " value " + value)
+		}
+	}
+
+	return p, nil
+}
+
+// hostOf returns host part of url or empty string
+func hostOf(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if nil != err {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// getPushNotify returns all push notification configurations
+func (c *Client) getPushNotify(ctx context.Context) (map[string]pushNotify, error) {
+	if err := c.requireVersion(ctx, MinVersionPushNotify); nil != err {
+		return nil, err
+	}
+
+	var result map[string]pushNotify
+	err := c.GetContext(ctx, c.url+"/v1/notify", &result)
+	if nil == result {
+		result = map[string]pushNotify{}
+	}
+	return result, err
+}
+
+// GetNotificationChannels returns all notification channels ordered by ID
+func (c *Client) GetNotificationChannels() ([]NotificationChannel, error) {
+	return c.GetNotificationChannelsContext(context.Background())
+}
+
+// GetNotificationChannelsContext is same as GetNotificationChannels but request is bound to ctx
+func (c *Client) GetNotificationChannelsContext(ctx context.Context) ([]NotificationChannel, error) {
+	configs, err := c.getPushNotify(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	result := make([]NotificationChannel, 0, len(configs))
+	for id, p := range configs {
+		result = append(result, p.channel(id))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+
+	return result, nil
+}
+
+// AddNotificationChannel adds notification channel and returns its ID, ch.ID is prefixed by type unless it already is
+// ("slack:ops") and empty ch.ID means type with numeric suffix ("slack:1"); existing channel with same ID is replaced
+func (c *Client) AddNotificationChannel(ch NotificationChannel) (string, error) {
+	return c.AddNotificationChannelContext(context.Background(), ch)
+}
+
+// AddNotificationChannelContext is same as AddNotificationChannel but requests are bound to ctx
+func (c *Client) AddNotificationChannelContext(ctx context.Context, ch NotificationChannel) (string, error) {
+	p, err := ch.push()
+	if nil != err {
+		return "", err
+	}
+
+	configs, err := c.getPushNotify(ctx)
+	if nil != err {
+		return "", err
+	}
+
+	prefix := ch.Type + ":"
+	id := ch.ID
+	switch {
+	case "" == id:
+		for i := 1; ; i++ {
+			id = prefix + strconv.Itoa(i)
+			if _, ok := configs[id]; !ok {
+				break
+			}
+		}
+	case !strings.HasPrefix(id, prefix):
+		id = prefix + id
+	}
+	configs[id] = p
+
+	return id, c._okResultSend(ctx, "PUT", c.url+"/v1/notify", configs)
+}
+
+// DeleteNotificationChannel removes notification channel, ErrChannelNotFound if it doesn't exist
+func (c *Client) DeleteNotificationChannel(id string) error {
+	return c.DeleteNotificationChannelContext(context.Background(), id)
+}
+
+// DeleteNotificationChannelContext is same as DeleteNotificationChannel but requests are bound to ctx
+func (c *Client) DeleteNotificationChannelContext(ctx context.Context, id string) error {
+	configs, err := c.getPushNotify(ctx)
+	if nil != err {
+		return err
+	}
+
+	if _, ok := configs[id]; !ok {
+		return ErrChannelNotFound
+	}
+	delete(configs, id)
+
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/notify", configs)
+}
+
+// NotifyCondition describes when group notification fires, zero fields are not checked
+type NotifyCondition struct {
+	MinLossPercent float64 `json:"minLossPercent"`
+	MinRTTMs       float64 `json:"minRTTMs"`
+	MinDurationSec int     `json:"minDurationSec"` // problem has to last at least that long
+}
+
+// GroupNotification is notification channel assigned to group
+type GroupNotification struct {
+	ChannelID  string          `json:"channel"`
+	Conditions NotifyCondition `json:"conditions"`
+}
+
+// AssignNotificationChannelToGroup makes channel to notify about problems in group matching conditions; server keeps
+// thresholds per group so conditions are shared by all channels of group and replaced by every call
+func (c *Client) AssignNotificationChannelToGroup(group string, channelID string, conditions NotifyCondition) error {
+	return c.AssignNotificationChannelToGroupContext(context.Background(), group, channelID, conditions)
+}
+
+// AssignNotificationChannelToGroupContext is same as AssignNotificationChannelToGroup but requests are bound to ctx
+func (c *Client) AssignNotificationChannelToGroupContext(ctx context.Context, group string, channelID string, conditions NotifyCondition) error {
+	if "" == channelID {
+		return ErrChannelNotFound
+	}
+	if conditions.MinLossPercent < 0 || conditions.MinLossPercent > 100 || conditions.MinRTTMs < 0 || conditions.MinDurationSec < 0 {
+		return errors.New("invalid notify conditions")
+	}
+
+	config, err := c.GetGroupConfigContext(ctx, group)
+	if nil != err {
+		return err
+	}
+
+	found := false
+	for _, id := range config.PushNotifyA {
+		if id == channelID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		config.PushNotifyA = append(config.PushNotifyA, channelID)
+	}
+	config.LossTreshold = float32(conditions.MinLossPercent)
+	config.LatencyThreshold = float32(conditions.MinRTTMs)
+	config.TimeThreshold = conditions.MinDurationSec
+
+	return c.SetGroupConfigContext(ctx, group, config)
+}
+
+// UnassignNotificationChannel stops channel notifying about group problems, ErrChannelNotFound if it isn't assigned
+func (c *Client) UnassignNotificationChannel(group, channelID string) error {
+	return c.UnassignNotificationChannelContext(context.Background(), group, channelID)
+}
+
+// UnassignNotificationChannelContext is same as UnassignNotificationChannel but requests are bound to ctx
+func (c *Client) UnassignNotificationChannelContext(ctx context.Context, group, channelID string) error {
+	config, err := c.GetGroupConfigContext(ctx, group)
+	if nil != err {
+		return err
+	}
+
+	channels := make([]string, 0, len(config.PushNotifyA))
+	for _, id := range config.PushNotifyA {
+		if id != channelID {
+			channels = append(channels, id)
+		}
+	}
+	if len(channels) == len(config.PushNotifyA) {
+		return ErrChannelNotFound
+	}
+	config.PushNotifyA = channels
+
+	return c.SetGroupConfigContext(ctx, group, config)
+}
+
+// ListGroupNotifications returns notification channels assigned to group ordered by channel ID
+func (c *Client) ListGroupNotifications(group string) ([]GroupNotification, error) {
+	return c.ListGroupNotificationsContext(context.Background(), group)
+}
+
+// ListGroupNotificationsContext is same as ListGroupNotifications but request is bound to ctx
+func (c *Client) ListGroupNotificationsContext(ctx context.Context, group string) ([]GroupNotification, error) {
+	config, err := c.GetGroupConfigContext(ctx, group)
+	if nil != err {
+		return nil, err
+	}
+
+	conditions := NotifyCondition{
+		MinLossPercent: float64(config.LossTreshold),
+		MinRTTMs:       float64(config.LatencyThreshold),
+		MinDurationSec: config.TimeThreshold,
+	}
+	result := make([]GroupNotification, 0, len(config.PushNotifyA))
+	for _, id := range config.PushNotifyA {
+		result = append(result, GroupNotification{ChannelID: id, Conditions: conditions})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ChannelID < result[j].ChannelID })
+
+	return result, nil
+}
@@ -0,0 +1,367 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// notifyFixture is GET /v1/notify response with email (mail gateway, type in ID prefix), Slack and generic webhook
+// configurations
+const notifyFixture = `{
+	"email:mail": {
+		"method": "POST",
+		"url": "https://mail.example.com/send",
+		"payload": "{\"to\":\"noc@example.com\",\"text\":\"<*IP*> loss <*LOSS*>%\"}",
+		"contentType": "application/json",
+		"headers": {"Authorization": "Bearer token"},
+		"frequency": 60,
+		"frequencyPerIP": 300,
+		"minSlavesFailed": 0
+	},
+	"slack": {
+		"method": "POST",
+		"url": "https://hooks.slack.com/services/T000/B000/XXXX",
+		"payload": "{\"text\":\"<*IP*>\"}",
+		"contentType": "application/json",
+		"headers": {},
+		"frequency": 0,
+		"frequencyPerIP": 0,
+		"minSlavesFailed": 2
+	},
+	"hook": {
+		"method": "GET",
+		"url": "https://monitoring.example.com/alert?ip=<*IP*>",
+		"payload": "",
+		"contentType": "",
+		"headers": null,
+		"frequency": 0,
+		"frequencyPerIP": 0,
+		"minSlavesFailed": 0
+	}
+}`
+
+// notifyServer keeps push notify configurations like master, version endpoint reports 1.0.4-6
+type notifyServer struct {
+	mu      sync.Mutex
+	configs map[string]json.RawMessage
+	puts    int
+}
+
+func newNotifyServer(t *testing.T, fixture string) *notifyServer {
+	s := &notifyServer{}
+	if err := json.Unmarshal([]byte(fixture), &s.configs); nil != err {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func (s *notifyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case "/v1/status/version" == r.URL.Path:
+		writeTestJSON(w, map[string]string{"version": "1.0.4-6"})
+	case "/v1/notify" == r.URL.Path && "GET" == r.Method:
+		writeTestJSON(w, s.configs)
+	case "/v1/notify" == r.URL.Path && "PUT" == r.Method:
+		var configs map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&configs); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.configs = configs
+		s.puts++
+		writeTestJSON(w, result{Result: "OK"})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// config returns stored configuration id decoded as server sees it
+func (s *notifyServer) config(id string) (pushNotify, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	raw, ok := s.configs[id]
+	var p pushNotify
+	json.Unmarshal(raw, &p)
+	return p, ok
+}
+
+func TestGetNotificationChannels(t *testing.T) {
+	c := newTestClient(t, newNotifyServer(t, notifyFixture).ServeHTTP)
+
+	channels, err := c.GetNotificationChannels()
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := []NotificationChannel{
+		{ID: "email:mail", Type: ChannelEmail, Endpoint: "https://mail.example.com/send", Config: map[string]string{
+			"method":               "POST",
+			"payload":              `{"to":"noc@example.com","text":"<*IP*> loss <*LOSS*>%"}`,
+			"contentType":          "application/json",
+			"frequency":            "60",
+			"frequencyPerIP":       "300",
+			"header:Authorization": "Bearer token",
+		}},
+		{ID: "hook", Type: ChannelWebhook, Endpoint: "https://monitoring.example.com/alert?ip=<*IP*>", Config: map[string]string{
+			"method": "GET",
+		}},
+		{ID: "slack", Type: ChannelSlack, Endpoint: "https://hooks.slack.com/services/T000/B000/XXXX", Config: map[string]string{
+			"method":          "POST",
+			"payload":         `{"text":"<*IP*>"}`,
+			"contentType":     "application/json",
+			"minSlavesFailed": "2",
+		}},
+	}
+	if !reflect.DeepEqual(expected, channels) {
+		t.Fatalf("expected %+v, got %+v", expected, channels)
+	}
+}
+
+func TestAddNotificationChannel(t *testing.T) {
+	s := newNotifyServer(t, notifyFixture)
+	c := newTestClient(t, s.ServeHTTP)
+
+	for _, tc := range []struct {
+		ch       NotificationChannel
+		id       string
+		expected pushNotify
+	}{
+		{
+			NotificationChannel{Type: ChannelEmail, Endpoint: "https://mail.example.com/send", Config: map[string]string{"header:Authorization": "Bearer token"}},
+			"email:1",
+			pushNotify{Method: "POST", URL: "https://mail.example.com/send", Payload: channelPayloads[ChannelEmail], ContentType: "application/json",
+				Headers: map[string]string{"Authorization": "Bearer token"}},
+		},
+		{
+			NotificationChannel{Type: ChannelEmail, Endpoint: "https://mail.example.com/other"},
+			"email:2",
+			pushNotify{Method: "POST", URL: "https://mail.example.com/other", Payload: channelPayloads[ChannelEmail], ContentType: "application/json",
+				Headers: map[string]string{}},
+		},
+		{
+			NotificationChannel{ID: "slack:ops", Type: ChannelSlack, Endpoint: "https://hooks.slack.com/services/T111/B111/YYYY", Config: map[string]string{"frequency": "120"}},
+			"slack:ops",
+			pushNotify{Method: "POST", URL: "https://hooks.slack.com/services/T111/B111/YYYY", Payload: channelPayloads[ChannelSlack], ContentType: "application/json",
+				Headers: map[string]string{}, Frequency: 120},
+		},
+		{
+			NotificationChannel{ID: "hook", Type: ChannelWebhook, Endpoint: "https://hooks.example.com/x", Config: map[string]string{"method": "PUT", "minSlavesFailed": "3"}},
+			"webhook:hook",
+			pushNotify{Method: "PUT", URL: "https://hooks.example.com/x", Payload: channelPayloads[ChannelWebhook], ContentType: "application/json",
+				Headers: map[string]string{}, MinSlavesFailed: 3},
+		},
+	} {
+		id, err := c.AddNotificationChannel(tc.ch)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if tc.id != id {
+			t.Errorf("expected id %s, got %s", tc.id, id)
+		}
+		if p, ok := s.config(id); !ok || !reflect.DeepEqual(tc.expected, p) {
+			t.Errorf("%s: expected %+v, got %+v", id, tc.expected, p)
+		}
+		if p, _ := s.config(id); tc.ch.Type != p.channel(id).Type {
+			t.Errorf("%s: expected type %s, got %s", id, tc.ch.Type, p.channel(id).Type)
+		}
+	}
+
+	// other configurations are sent back unchanged
+	if p, _ := s.config("email:mail"); 300 != p.FrequencyPerIP || "Bearer token" != p.Headers["Authorization"] {
+		t.Fatalf("existing configuration changed: %+v", p)
+	}
+
+	for _, ch := range []NotificationChannel{
+		{Type: "sms", Endpoint: "https://sms.example.com/"},
+		{Type: ChannelWebhook, Endpoint: "mailto:noc@example.com"},
+		{Type: ChannelWebhook, Endpoint: "https://hooks.example.com/", Config: map[string]string{"frequency": "often"}},
+		{Type: ChannelWebhook, Endpoint: "https://hooks.example.com/", Config: map[string]string{"retries": "3"}},
+	} {
+		if _, err := c.AddNotificationChannel(ch); nil == err {
+			t.Errorf("%+v: expected error", ch)
+		}
+	}
+	if 4 != s.puts {
+		t.Fatalf("invalid channels were sent to server: %d puts", s.puts)
+	}
+}
+
+func TestDeleteNotificationChannel(t *testing.T) {
+	s := newNotifyServer(t, notifyFixture)
+	c := newTestClient(t, s.ServeHTTP)
+
+	if err := c.DeleteNotificationChannel("slack"); nil != err {
+		t.Fatal(err)
+	}
+	if _, ok := s.config("slack"); ok {
+		t.Fatal("channel wasn't deleted")
+	}
+	if _, ok := s.config("email:mail"); !ok {
+		t.Fatal("other channel was deleted")
+	}
+
+	if err := c.DeleteNotificationChannel("slack"); !errors.Is(err, ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+}
+
+func TestNotificationChannelsVersionTooOld(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]string{"version": "1.0.1-9"})
+	})
+
+	if _, err := c.GetNotificationChannels(); !errors.Is(err, ErrVersionTooOld) {
+		t.Fatalf("expected ErrVersionTooOld, got %v", err)
+	}
+}
+
+func TestNotificationChannelsUnknownVersion(t *testing.T) {
+	s := newNotifyServer(t, notifyFixture)
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if "/v1/status/version" == r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		s.ServeHTTP(w, r)
+	})
+
+	channels, err := c.GetNotificationChannels()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 3 != len(channels) {
+		t.Fatalf("expected 3 channels, got %+v", channels)
+	}
+}
+
+// groupConfigServer keeps raw group configurations served by GET and replaced by POST /v1/group/:group
+type groupConfigServer struct {
+	mu      sync.Mutex
+	configs map[string]map[string]json.RawMessage
+	posts   int
+}
+
+func (s *groupConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	group := strings.TrimPrefix(r.URL.Path, "/v1/group/")
+	config, ok := s.configs[group]
+	if group == r.URL.Path || !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeTestJSON(w, config)
+	case "POST":
+		if err := json.NewDecoder(r.Body).Decode(&config); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.configs[group] = config
+		s.posts++
+		writeTestJSON(w, result{Result: "OK"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// field returns raw value of field of group configuration
+func (s *groupConfigServer) field(group, key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return string(s.configs[group][key])
+}
+
+func TestGroupNotifications(t *testing.T) {
+	s := &groupConfigServer{configs: map[string]map[string]json.RawMessage{
+		"DNS->": {
+			"isPublic":         json.RawMessage(`true`),
+			"lossThreshold":    json.RawMessage(`10`),
+			"latencyThreshold": json.RawMessage(`100`),
+			"timeThreshold":    json.RawMessage(`60`),
+			"pushNotify":       json.RawMessage(`{"legacy":true}`),
+			"pushNotifyA":      json.RawMessage(`["slack"]`),
+			"agSlaves":         json.RawMessage(`["PRAGUE"]`),
+			"slavesThresholds": json.RawMessage(`{"TOKYO":{"lossThreshold":20,"latencyThreshold":300,"timeThreshold":120}}`),
+		},
+	}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	list, err := c.ListGroupNotifications("DNS")
+	if nil != err {
+		t.Fatal(err)
+	}
+	expected := []GroupNotification{{ChannelID: "slack", Conditions: NotifyCondition{MinLossPercent: 10, MinRTTMs: 100, MinDurationSec: 60}}}
+	if !reflect.DeepEqual(expected, list) {
+		t.Fatalf("expected %+v, got %+v", expected, list)
+	}
+
+	// new assignment is appended and thresholds replaced
+	if err := c.AssignNotificationChannelToGroup("DNS", "mail", NotifyCondition{MinLossPercent: 5, MinRTTMs: 50, MinDurationSec: 30}); nil != err {
+		t.Fatal(err)
+	}
+	if `["slack","mail"]` != s.field("DNS->", "pushNotifyA") || "5" != s.field("DNS->", "lossThreshold") ||
+		"50" != s.field("DNS->", "latencyThreshold") || "30" != s.field("DNS->", "timeThreshold") {
+		t.Fatalf("unexpected config after new assignment %v", s.configs["DNS->"])
+	}
+
+	// updating existing assignment changes only thresholds
+	if err := c.AssignNotificationChannelToGroup("DNS->", "slack", NotifyCondition{MinLossPercent: 25}); nil != err {
+		t.Fatal(err)
+	}
+	if `["slack","mail"]` != s.field("DNS->", "pushNotifyA") || "25" != s.field("DNS->", "lossThreshold") ||
+		"0" != s.field("DNS->", "latencyThreshold") || "0" != s.field("DNS->", "timeThreshold") {
+		t.Fatalf("unexpected config after update %v", s.configs["DNS->"])
+	}
+
+	// read-modify-write keeps unrelated settings
+	if "true" != s.field("DNS->", "isPublic") || `["PRAGUE"]` != s.field("DNS->", "agSlaves") || `{"legacy":true}` != s.field("DNS->", "pushNotify") ||
+		`{"TOKYO":{"lossThreshold":20,"latencyThreshold":300,"timeThreshold":120}}` != s.field("DNS->", "slavesThresholds") {
+		t.Fatalf("unrelated settings changed %v", s.configs["DNS->"])
+	}
+
+	list, err = c.ListGroupNotifications("DNS")
+	if nil != err {
+		t.Fatal(err)
+	}
+	conditions := NotifyCondition{MinLossPercent: 25}
+	expected = []GroupNotification{{ChannelID: "mail", Conditions: conditions}, {ChannelID: "slack", Conditions: conditions}}
+	if !reflect.DeepEqual(expected, list) {
+		t.Fatalf("expected %+v, got %+v", expected, list)
+	}
+
+	if err := c.UnassignNotificationChannel("DNS", "slack"); nil != err {
+		t.Fatal(err)
+	}
+	if `["mail"]` != s.field("DNS->", "pushNotifyA") {
+		t.Fatalf("unexpected channels after unassign %s", s.field("DNS->", "pushNotifyA"))
+	}
+
+	posts := s.posts
+	if err := c.UnassignNotificationChannel("DNS", "slack"); !errors.Is(err, ErrChannelNotFound) {
+		t.Fatalf("expected ErrChannelNotFound, got %v", err)
+	}
+	if err := c.AssignNotificationChannelToGroup("DNS", "mail", NotifyCondition{MinLossPercent: 101}); nil == err {
+		t.Fatal("expected error for invalid conditions")
+	}
+	if posts != s.posts {
+		t.Fatal("failed calls changed group config")
+	}
+
+	if _, err := c.ListGroupNotifications("MISSING"); nil == err {
+		t.Fatal("expected error for missing group")
+	}
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// IPEntry is one monitored IP with its configuration
+type IPEntry struct {
+	IP string `json:"ip"`
+	TestDesc
+}
+
+// ConfigPage is one page of monitored IPs returned by GetConfigPage
+type ConfigPage struct {
+	Entries       []IPEntry `json:"entries"`
+	NextPageToken string    `json:"nextPageToken"` // empty on last page
+}
+
+// GetConfigPage returns one page of monitored IPs, pass empty pageToken for first page
+func (c *Client) GetConfigPage(pageToken string, pageSize int) (ConfigPage, error) {
+	return c.GetConfigPageContext(context.Background(), pageToken, pageSize)
+}
+
+// GetConfigPageContext is same as GetConfigPage but request is bound to ctx
+func (c *Client) GetConfigPageContext(ctx context.Context, pageToken string, pageSize int) (ConfigPage, error) {
+	var result ConfigPage
+	query := url.Values{
+		"pageToken": []string{pageToken},
+		"pageSize":  []string{strconv.Itoa(pageSize)},
+	}
+	err := c.GetContext(ctx, c.url+"/v1/config/ping?"+query.Encode(), &result)
+	return result, err
+}
+
+// ConfigIterator walks over all monitored IPs page by page
+//
+//	it := client.IterConfig(1000)
+//	for entry, ok := it.Next(); ok; entry, ok = it.Next() {
+//		...
+//	}
+//	if err := it.Err(); nil != err {
+//		...
+//	}
+type ConfigIterator struct {
+	client   *Client
+	ctx      context.Context
+	pageSize int
+	token    string
+	entries  []IPEntry
+	started  bool
+	err      error
+}
+
+// IterConfig returns iterator over all monitored IPs loading pageSize entries per request
+func (c *Client) IterConfig(pageSize int) *ConfigIterator {
+	return c.IterConfigContext(context.Background(), pageSize)
+}
+
+// IterConfigContext is same as IterConfig but requests are bound to ctx
+func (c *Client) IterConfigContext(ctx context.Context, pageSize int) *ConfigIterator {
+	return &ConfigIterator{
+		client:   c,
+		ctx:      ctx,
+		pageSize: pageSize,
+	}
+}
+
+// Next returns next entry, false means that iteration is finished (check Err for possible error)
+func (it *ConfigIterator) Next() (IPEntry, bool) {
+	for 0 == len(it.entries) {
+		if nil != it.err || (it.started && "" == it.token) {
+			return IPEntry{}, false
+		}
+
+		page, err := it.client.GetConfigPageContext(it.ctx, it.token, it.pageSize)
+		it.started = true
+		if nil != err {
+			it.err = err
+			return IPEntry{}, false
+		}
+		it.entries = page.Entries
+		it.token = page.NextPageToken
+	}
+
+	entry := it.entries[0]
+	it.entries = it.entries[1:]
+	return entry, true
+}
+
+// Err returns error that stopped iteration if any
+func (it *ConfigIterator) Err() error {
+	return it.err
+}
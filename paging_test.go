@@ -0,0 +1,125 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+)
+
+// pagedConfigServer serves count IPs page by page using offset as page token, failAt > 0 makes page at this offset fail
+type pagedConfigServer struct {
+	entries []IPEntry
+	failAt  int
+	pages   int
+}
+
+func newPagedConfigServer(count int) *pagedConfigServer {
+	s := &pagedConfigServer{}
+	for i := 0; i < count; i++ {
+		ip := fmt.Sprintf("10.%d.%d.%d", i>>16&255, i>>8&255, i&255)
+		s.entries = append(s.entries, IPEntry{IP: ip, TestDesc: TestDesc{Description: "ip " + strconv.Itoa(i), Slaves: []string{"PRAGUE"}}})
+	}
+	return s
+}
+
+func (s *pagedConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if "/v1/config/ping" != r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+	s.pages++
+
+	offset := 0
+	if token := r.URL.Query().Get("pageToken"); "" != token {
+		offset, _ = strconv.Atoi(token)
+	}
+	if s.failAt > 0 && offset == s.failAt {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	size, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
+
+	end := offset + size
+	page := ConfigPage{}
+	if end < len(s.entries) {
+		page.NextPageToken = strconv.Itoa(end)
+	} else {
+		end = len(s.entries)
+	}
+	page.Entries = s.entries[offset:end]
+	writeTestJSON(w, page)
+}
+
+func TestIterConfigLargeInstallation(t *testing.T) {
+	s := newPagedConfigServer(10000)
+	c := newTestClient(t, s.ServeHTTP)
+
+	it := c.IterConfig(1000)
+	seen := map[string]bool{}
+	n := 0
+	for entry, ok := it.Next(); ok; entry, ok = it.Next() {
+		if s.entries[n].IP != entry.IP || s.entries[n].Description != entry.Description {
+			t.Fatalf("entry %d: expected %+v, got %+v", n, s.entries[n], entry)
+		}
+		if seen[entry.IP] {
+			t.Fatalf("duplicate entry %s", entry.IP)
+		}
+		seen[entry.IP] = true
+		n++
+	}
+	if err := it.Err(); nil != err {
+		t.Fatal(err)
+	}
+	if 10000 != n {
+		t.Fatalf("expected 10000 entries, got %d", n)
+	}
+	if 10 != s.pages {
+		t.Fatalf("expected 10 pages, got %d", s.pages)
+	}
+}
+
+func TestIterConfigUnevenLastPage(t *testing.T) {
+	s := newPagedConfigServer(10000)
+	c := newTestClient(t, s.ServeHTTP)
+
+	it := c.IterConfig(3000)
+	n := 0
+	for _, ok := it.Next(); ok; _, ok = it.Next() {
+		n++
+	}
+	if nil != it.Err() || 10000 != n || 4 != s.pages {
+		t.Fatalf("unexpected result: %d entries in %d pages, err %v", n, s.pages, it.Err())
+	}
+}
+
+func TestIterConfigError(t *testing.T) {
+	s := newPagedConfigServer(10000)
+	s.failAt = 5000
+	c := newTestClient(t, s.ServeHTTP)
+
+	it := c.IterConfig(1000)
+	n := 0
+	for _, ok := it.Next(); ok; _, ok = it.Next() {
+		n++
+	}
+	if nil == it.Err() {
+		t.Fatal("expected error")
+	}
+	if 5000 != n {
+		t.Fatalf("expected 5000 entries before error, got %d", n)
+	}
+	if _, ok := it.Next(); ok {
+		t.Fatal("iterator should stay finished after error")
+	}
+}
+
+func TestIterConfigEmpty(t *testing.T) {
+	s := newPagedConfigServer(0)
+	c := newTestClient(t, s.ServeHTTP)
+
+	it := c.IterConfig(1000)
+	if _, ok := it.Next(); ok || nil != it.Err() || 1 != s.pages {
+		t.Fatalf("unexpected iteration over empty config: err %v, pages %d", it.Err(), s.pages)
+	}
+}
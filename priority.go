@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+)
+
+// SetSlavePriority sets probe weights of slaves testing ip, slaves with higher priority are picked more often
+// by round-robin; slaves assigned to ip but missing in priorities get priority 0 (lowest weight, still used)
+func (c *Client) SetSlavePriority(ip string, priorities map[string]int) error {
+	return c.SetSlavePriorityContext(context.Background(), ip, priorities)
+}
+
+// SetSlavePriorityContext is same as SetSlavePriority but request is bound to ctx
+func (c *Client) SetSlavePriorityContext(ctx context.Context, ip string, priorities map[string]int) error {
+	if nil == priorities {
+		priorities = map[string]int{}
+	}
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/ping/"+ipPath(ip)+"/priority", priorities)
+}
+
+// GetSlavePriority returns probe weights of all slaves testing ip, slaves without explicit priority are reported with 0
+func (c *Client) GetSlavePriority(ip string) (map[string]int, error) {
+	return c.GetSlavePriorityContext(context.Background(), ip)
+}
+
+// GetSlavePriorityContext is same as GetSlavePriority but requests are bound to ctx
+func (c *Client) GetSlavePriorityContext(ctx context.Context, ip string) (map[string]int, error) {
+	detail, err := c.GetIPDetailsContext(ctx, ip)
+	if nil != err {
+		return nil, err
+	}
+
+	var priorities map[string]int
+	err = c.GetContext(ctx, c.url+"/v1/config/ping/"+ipPath(ip)+"/priority", &priorities)
+	if nil != err {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(detail.Slaves))
+	for _, slave := range detail.Slaves {
+		result[slave] = priorities[slave]
+	}
+	return result, nil
+}
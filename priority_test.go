@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// priorityServer keeps slaves of ips and stores priorities sent by PUT .../priority as they are
+type priorityServer struct {
+	mu         sync.Mutex
+	slaves     map[string][]string
+	priorities map[string]map[string]int
+}
+
+func (s *priorityServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/v1/config/ping/")
+	ip := strings.TrimSuffix(path, "/priority")
+	slaves, ok := s.slaves[ip]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case ip == path && "GET" == r.Method:
+		writeTestJSON(w, IPDetail{TestDesc: TestDesc{Slaves: slaves}})
+	case ip != path && "GET" == r.Method:
+		writeTestJSON(w, s.priorities[ip])
+	case ip != path && "PUT" == r.Method:
+		var priorities map[string]int
+		if err := json.NewDecoder(r.Body).Decode(&priorities); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.priorities[ip] = priorities
+		writeTestJSON(w, result{Result: "OK"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestSlavePriorityMissingIsZero(t *testing.T) {
+	s := &priorityServer{
+		slaves:     map[string][]string{"1.1.1.1": {"PRAGUE", "LONDON", "PARIS"}},
+		priorities: map[string]map[string]int{},
+	}
+	c := newTestClient(t, s.ServeHTTP)
+
+	if err := c.SetSlavePriority("1.1.1.1", map[string]int{"PRAGUE": 10, "LONDON": 5}); nil != err {
+		t.Fatal(err)
+	}
+	if sent := s.priorities["1.1.1.1"]; !reflect.DeepEqual(map[string]int{"PRAGUE": 10, "LONDON": 5}, sent) {
+		t.Fatalf("unexpected payload %v", sent)
+	}
+
+	priorities, err := c.GetSlavePriority("1.1.1.1")
+	if nil != err {
+		t.Fatal(err)
+	}
+	// PARIS is still assigned so it's reported with lowest weight instead of being dropped
+	if expected := map[string]int{"PRAGUE": 10, "LONDON": 5, "PARIS": 0}; !reflect.DeepEqual(expected, priorities) {
+		t.Fatalf("expected %v, got %v", expected, priorities)
+	}
+
+	// clearing priorities sends empty object, all slaves stay with priority 0
+	if err := c.SetSlavePriority("1.1.1.1", nil); nil != err {
+		t.Fatal(err)
+	}
+	priorities, err = c.GetSlavePriority("1.1.1.1")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if expected := map[string]int{"PRAGUE": 0, "LONDON": 0, "PARIS": 0}; !reflect.DeepEqual(expected, priorities) {
+		t.Fatalf("expected %v, got %v", expected, priorities)
+	}
+}
+
+func TestGetSlavePriorityUnknownIP(t *testing.T) {
+	c := newTestClient(t, (&priorityServer{}).ServeHTTP)
+
+	if _, err := c.GetSlavePriority("9.9.9.9"); !errors.Is(err, ErrIPNotFound) {
+		t.Fatalf("expected ErrIPNotFound, got %v", err)
+	}
+}
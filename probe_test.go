@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// probeServer starts probe p1 and answers its polls with states one by one (last one is repeated), empty state
+// means http error
+type probeServer struct {
+	mu     sync.Mutex
+	start  map[string]string
+	states []string
+	polls  int
+}
+
+func (s *probeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case "/v1/probe/now" == r.URL.Path && "POST" == r.Method:
+		json.NewDecoder(r.Body).Decode(&s.start)
+		if "OFFLINE" == s.start["slave"] {
+			writeTestJSON(w, result{Result: "error", Error: "slave is offline"})
+			return
+		}
+		writeTestJSON(w, map[string]string{"result": "OK", "id": "p1"})
+	case "/v1/probe/now/p1" == r.URL.Path && "GET" == r.Method:
+		state := s.states[len(s.states)-1]
+		if s.polls < len(s.states) {
+			state = s.states[s.polls]
+		}
+		s.polls++
+		if "" == state {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(state))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *probeServer) pollCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.polls
+}
+
+// withProbeNowTiming sets ProbeNow timeout and poll interval for one test
+func withProbeNowTiming(t *testing.T, timeout time.Duration, interval time.Duration) {
+	oldTimeout, oldInterval := ProbeNowTimeout, ProbeNowPollInterval
+	ProbeNowTimeout, ProbeNowPollInterval = timeout, interval
+	t.Cleanup(func() {
+		ProbeNowTimeout, ProbeNowPollInterval = oldTimeout, oldInterval
+	})
+}
+
+func TestProbeNowPollingSequence(t *testing.T) {
+	withProbeNowTiming(t, 5*time.Second, time.Millisecond)
+	s := &probeServer{states: []string{
+		`{"done": false}`,
+		`{"done": false}`,
+		`{"done": true, "rtt": 12.5, "loss": 20, "timestamp": "2024-03-01T12:00:00Z", "output": "5 packets transmitted, 4 received"}`,
+	}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	probe, err := c.ProbeNow("2001:DB8::1", "PRAGUE")
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	expected := ProbeResult{
+		RTT:       12500 * time.Microsecond,
+		Loss:      20,
+		Timestamp: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+		RawOutput: "5 packets transmitted, 4 received",
+	}
+	if expected != probe {
+		t.Errorf("expected %+v, got %+v", expected, probe)
+	}
+	if 3 != s.pollCount() {
+		t.Errorf("expected 3 polls, got %d", s.pollCount())
+	}
+	if "2001:db8::1" != s.start["ip"] || "PRAGUE" != s.start["slave"] {
+		t.Errorf("unexpected probe request %v", s.start)
+	}
+}
+
+func TestProbeNowTimeout(t *testing.T) {
+	withProbeNowTiming(t, 30*time.Millisecond, 5*time.Millisecond)
+	s := &probeServer{states: []string{`{"done": false}`}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	start := time.Now()
+	if _, err := c.ProbeNow("192.0.2.1", "PRAGUE"); !errors.Is(err, ErrProbeTimeout) {
+		t.Fatalf("expected ErrProbeTimeout, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("timeout took %v", time.Since(start))
+	}
+	if s.pollCount() < 2 {
+		t.Errorf("expected repeated polls, got %d", s.pollCount())
+	}
+}
+
+func TestProbeNowErrors(t *testing.T) {
+	withProbeNowTiming(t, 5*time.Second, time.Millisecond)
+
+	// failed poll stops waiting
+	s := &probeServer{states: []string{`{"done": false}`, ""}}
+	c := newTestClient(t, s.ServeHTTP)
+	if _, err := c.ProbeNow("192.0.2.1", "PRAGUE"); nil == err || errors.Is(err, ErrProbeTimeout) {
+		t.Errorf("expected poll error, got %v", err)
+	}
+	if 2 != s.pollCount() {
+		t.Errorf("expected 2 polls, got %d", s.pollCount())
+	}
+
+	// probe refused by server isn't polled
+	s = &probeServer{states: []string{`{"done": true}`}}
+	c = newTestClient(t, s.ServeHTTP)
+	if _, err := c.ProbeNow("192.0.2.1", "OFFLINE"); nil == err || "slave is offline" != err.Error() {
+		t.Errorf("expected server error, got %v", err)
+	}
+	if _, err := c.ProbeNow("192.0.2.1", ""); !errors.Is(err, ErrEmptySlaveName) {
+		t.Errorf("expected ErrEmptySlaveName, got %v", err)
+	}
+	if 0 != s.pollCount() {
+		t.Errorf("expected no polls, got %d", s.pollCount())
+	}
+
+	// cancelled caller gets its own error, not timeout
+	s = &probeServer{states: []string{`{"done": false}`}}
+	c = newTestClient(t, s.ServeHTTP)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.ProbeNowContext(ctx, "192.0.2.1", "PRAGUE"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+)
+
+// RateLimiter limits rate of outgoing requests, *rate.Limiter from golang.org/x/time/rate satisfies it
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiter makes client wait for limiter before every request (including retries), nil disables limiting.
+// Typical usage is WithRateLimiter(rate.NewLimiter(rate.Limit(10), 1)) to allow at most 10 requests per second
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(c *Client) {
+		c.optionsMu.Lock()
+		c.limiter = limiter
+		c.optionsMu.Unlock()
+	}
+}
+
+// wait blocks until limiter allows next request, context error is returned if ctx expires meanwhile
+func (c *Client) wait(ctx context.Context) error {
+	c.optionsMu.RLock()
+	limiter := c.limiter
+	c.optionsMu.RUnlock()
+	if nil == limiter {
+		return nil
+	}
+
+	err := limiter.Wait(ctx)
+	if nil == err {
+		return nil
+	}
+	if nil != ctx.Err() {
+		return ctx.Err()
+	}
+	if _, ok := ctx.Deadline(); ok {
+		// limiter refuses to wait past deadline without waiting for it
+		return context.DeadlineExceeded
+	}
+	return err
+}
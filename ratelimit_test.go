@@ -0,0 +1,119 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// intervalLimiter allows one request per interval and like rate.Limiter refuses to wait past ctx deadline
+type intervalLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+	waits    int
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	l.waits++
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(at) {
+		l.mu.Unlock()
+		return errors.New("rate: Wait would exceed context deadline")
+	}
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleepContext(ctx, at.Sub(now))
+}
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeTestJSON(w, map[string]string{})
+	}, WithRateLimiter(&intervalLimiter{interval: 30 * time.Millisecond}))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if _, err := c.GetSlavesIPs(); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("4 requests at 1 per 30ms took only %v", elapsed)
+	}
+	if 4 != atomic.LoadInt32(&calls) {
+		t.Fatalf("expected 4 requests, got %d", calls)
+	}
+}
+
+func TestRateLimiterRejectsExcessCalls(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		writeTestJSON(w, map[string]string{})
+	}, WithRateLimiter(&intervalLimiter{interval: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := c.GetSlavesIPsContext(ctx); nil != err {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := c.GetSlavesIPsContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("call %d: expected context.DeadlineExceeded, got %v", i+2, err)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); 1 != n {
+		t.Fatalf("rejected calls reached server: %d requests", n)
+	}
+}
+
+func TestRateLimiterCoversRetries(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503, 503, 200}}
+	limiter := &intervalLimiter{interval: time.Millisecond}
+	c := newTestClient(t, h.ServeHTTP,
+		WithRateLimiter(limiter),
+		WithRetry(RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond}))
+
+	if _, err := c.GetSlavesIPs(); nil != err {
+		t.Fatal(err)
+	}
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if 3 != limiter.waits {
+		t.Fatalf("expected limiter to be consulted for all 3 attempts, got %d", limiter.waits)
+	}
+}
+
+// failingLimiter always refuses without deadline
+type failingLimiter struct{}
+
+func (failingLimiter) Wait(ctx context.Context) error {
+	return errors.New("rate: burst exceeded")
+}
+
+func TestRateLimiterError(t *testing.T) {
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}, WithRateLimiter(failingLimiter{}))
+
+	if _, err := c.GetSlavesIPs(); nil == err || "rate: burst exceeded" != err.Error() {
+		t.Fatalf("expected limiter error, got %v", err)
+	}
+	if 0 != atomic.LoadInt32(&calls) {
+		t.Fatal("request was sent despite limiter error")
+	}
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ReachabilityStatus is result of probes of one IP from one slave
+type ReachabilityStatus int
+
+// reachability statuses, Unknown is zero value
+const (
+	Unknown     ReachabilityStatus = iota // no data (slave offline, IP not tested by slave...)
+	Reachable                             // at least one probe succeeded during last minute
+	Unreachable                           // all probes were lost during last minute
+)
+
+func (s ReachabilityStatus) String() string {
+	switch s {
+	case Reachable:
+		return "reachable"
+	case Unreachable:
+		return "unreachable"
+	}
+	return "unknown"
+}
+
+// ReachabilityMatrix is reachability of IPs by slave: matrix[slave][ip]
+type ReachabilityMatrix map[string]map[string]ReachabilityStatus
+
+// GetIPReachabilityMatrix returns last-minute reachability of ips from slaves (all slaves if empty) queried in parallel,
+// every pair is present in result and is Unknown if there is no data; failed slaves are reported by returned MultiError
+func (c *Client) GetIPReachabilityMatrix(ips []string, slaves []string) (ReachabilityMatrix, error) {
+	return c.GetIPReachabilityMatrixContext(context.Background(), ips, slaves)
+}
+
+// GetIPReachabilityMatrixContext is same as GetIPReachabilityMatrix but requests are bound to ctx
+func (c *Client) GetIPReachabilityMatrixContext(ctx context.Context, ips []string, slaves []string) (ReachabilityMatrix, error) {
+	status, err := c.GetSlavesStatusContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+	if 0 == len(slaves) {
+		for slave := range status {
+			slaves = append(slaves, slave)
+		}
+		sort.Strings(slaves)
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	// last-minute stats are available only per group, so only groups of requested IPs are loaded
+	groups := []string{}
+	ips = append([]string{}, ips...)
+	for i, ip := range ips {
+		ips[i] = normalizeIP(ip)
+		if desc, ok := config.Ping.IPs[ips[i]]; ok && 0 != len(desc.Groups) {
+			groups = appendUnique(groups, strings.TrimSuffix(desc.Groups[0], groupSuffix))
+		}
+	}
+
+	matrix := make(ReachabilityMatrix, len(slaves))
+	for _, slave := range slaves {
+		matrix[slave] = make(map[string]ReachabilityStatus, len(ips))
+		for _, ip := range ips {
+			matrix[slave][ip] = Unknown
+		}
+	}
+
+	var mu sync.Mutex
+	errs := MultiError{}
+
+	c.forEach(slaves, func(slave string) {
+		if !status[slave].Online() {
+			return
+		}
+
+		row := map[string]ReachabilityStatus{}
+		for _, group := range groups {
+			stats, _, err := c.GroupLastStatsContext(ctx, group, slave)
+			if nil != err {
+				mu.Lock()
+				errs[slave] = err
+				mu.Unlock()
+				return
+			}
+			for ip, chunk := range stats {
+				row[ip] = chunkReachability(chunk)
+			}
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		for _, ip := range ips {
+			matrix[slave][ip] = row[ip]
+		}
+	})
+
+	return matrix, errs.errOrNil()
+}
+
+// chunkReachability converts last-minute stats to reachability
+func chunkReachability(chunk *AvgChunk) ReachabilityStatus {
+	if nil == chunk || 0 == chunk.Count {
+		return Unknown
+	}
+	if chunk.Loss >= chunk.Count {
+		return Unreachable
+	}
+	return Reachable
+}
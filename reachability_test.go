@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// reachabilityServer serves slaves status, config with IPs in groups DNS and INTERNAL and last-minute stats
+// (group -> slave -> ip -> stats), slave missing in stats of group answers with error
+type reachabilityServer struct {
+	status map[string]SlaveStatus
+	stats  map[string]map[string]map[string]*AvgChunk
+}
+
+func (s *reachabilityServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case "/v1/status/slaves" == r.URL.Path:
+		writeTestJSON(w, s.status)
+	case "/v1/config" == r.URL.Path:
+		var config ConfigInfo
+		config.Ping.IPs = map[string]TestDesc{
+			"1.1.1.1":  {Groups: []string{"DNS->"}},
+			"8.8.8.8":  {Groups: []string{"DNS->", "INTERNAL->"}},
+			"10.0.0.1": {Groups: []string{"INTERNAL->"}},
+		}
+		writeTestJSON(w, config)
+	case strings.HasPrefix(r.URL.Path, "/v1/minute/"):
+		group := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/minute/"), "->")
+		ips, ok := s.stats[group][r.URL.Query().Get("slave")]
+		if !ok {
+			writeTestJSON(w, map[string]string{"result": "error", "error": "no data"})
+			return
+		}
+		writeTestJSON(w, map[string]interface{}{"Ping": ips, "HTTP": map[string]*AvgChunk{}})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGetIPReachabilityMatrixUnknown(t *testing.T) {
+	s := &reachabilityServer{
+		status: map[string]SlaveStatus{
+			"PRAGUE": {Status: "online"},
+			"LONDON": {Status: "ok"},
+			"BERLIN": {Status: "offline"},
+			"TOKYO":  {Status: "online"},
+		},
+		stats: map[string]map[string]map[string]*AvgChunk{
+			"DNS": {
+				"PRAGUE": {"1.1.1.1": {Count: 10, Loss: 1}, "8.8.8.8": {Count: 10, Loss: 10}},
+				"LONDON": {"1.1.1.1": {Count: 0}, "8.8.8.8": nil},
+				"BERLIN": {"1.1.1.1": {Count: 10}},
+			},
+			"INTERNAL": {
+				"PRAGUE": {},
+				"LONDON": {"10.0.0.1": {Count: 5, Loss: 2}},
+				"BERLIN": {"10.0.0.1": {Count: 5}},
+			},
+		},
+	}
+	c := newTestClient(t, s.ServeHTTP)
+
+	ips := []string{"1.1.1.1", "8.8.8.8", "10.0.0.1", "192.0.2.1"}
+	matrix, err := c.GetIPReachabilityMatrix(ips, []string{"PRAGUE", "LONDON", "BERLIN", "TOKYO", "GHOST"})
+
+	// TOKYO has no stats at all
+	multi, ok := err.(MultiError)
+	if !ok || 1 != len(multi) || nil == multi["TOKYO"] {
+		t.Fatalf("expected MultiError for TOKYO, got %v", err)
+	}
+
+	expected := ReachabilityMatrix{
+		// 10.0.0.1 missing in stats, 192.0.2.1 isn't monitored
+		"PRAGUE": {"1.1.1.1": Reachable, "8.8.8.8": Unreachable, "10.0.0.1": Unknown, "192.0.2.1": Unknown},
+		// no probes and nil stats
+		"LONDON": {"1.1.1.1": Unknown, "8.8.8.8": Unknown, "10.0.0.1": Reachable, "192.0.2.1": Unknown},
+		// offline slave isn't queried even if master still has its stats
+		"BERLIN": {"1.1.1.1": Unknown, "8.8.8.8": Unknown, "10.0.0.1": Unknown, "192.0.2.1": Unknown},
+		// failed slave
+		"TOKYO": {"1.1.1.1": Unknown, "8.8.8.8": Unknown, "10.0.0.1": Unknown, "192.0.2.1": Unknown},
+		// slave unknown to master
+		"GHOST": {"1.1.1.1": Unknown, "8.8.8.8": Unknown, "10.0.0.1": Unknown, "192.0.2.1": Unknown},
+	}
+	if !reflect.DeepEqual(expected, matrix) {
+		t.Fatalf("expected %v, got %v", expected, matrix)
+	}
+	if "unknown" != matrix["GHOST"]["1.1.1.1"].String() {
+		t.Errorf("unexpected string of Unknown: %s", matrix["GHOST"]["1.1.1.1"])
+	}
+}
+
+func TestGetIPReachabilityMatrixAllSlaves(t *testing.T) {
+	s := &reachabilityServer{
+		status: map[string]SlaveStatus{"PRAGUE": {Status: "online"}, "BERLIN": {Status: "offline"}},
+		stats: map[string]map[string]map[string]*AvgChunk{
+			"DNS": {"PRAGUE": {"1.1.1.1": {Count: 10}}},
+		},
+	}
+	c := newTestClient(t, s.ServeHTTP)
+
+	matrix, err := c.GetIPReachabilityMatrix([]string{"1.1.1.1"}, nil)
+	if nil != err {
+		t.Fatal(err)
+	}
+	expected := ReachabilityMatrix{
+		"PRAGUE": {"1.1.1.1": Reachable},
+		"BERLIN": {"1.1.1.1": Unknown},
+	}
+	if !reflect.DeepEqual(expected, matrix) {
+		t.Fatalf("expected %v, got %v", expected, matrix)
+	}
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+)
+
+// SlaveMove is one reassignment made by RebalanceSlaves
+type SlaveMove struct {
+	IP   string
+	From string
+	To   string // empty if IP was only removed from From as all target slaves already test it
+}
+
+// RebalanceReport is result of RebalanceSlaves
+type RebalanceReport struct {
+	Moves   []SlaveMove
+	Limited bool // true if maxReassignments stopped rebalancing before load was even
+}
+
+// RebalanceSlaves moves IP tests from slaves outside of targetSlaves to target ones and then between target slaves
+// until count of tested IPs differs by at most one; at most maxReassignments moves are made (negative means no limit)
+func (c *Client) RebalanceSlaves(targetSlaves []string, maxReassignments int) (RebalanceReport, error) {
+	return c.RebalanceSlavesContext(context.Background(), targetSlaves, maxReassignments)
+}
+
+// RebalanceSlavesContext is same as RebalanceSlaves but requests are bound to ctx
+func (c *Client) RebalanceSlavesContext(ctx context.Context, targetSlaves []string, maxReassignments int) (RebalanceReport, error) {
+	report := RebalanceReport{Moves: []SlaveMove{}}
+
+	if 0 == len(targetSlaves) {
+		return report, errors.New("no target slaves")
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return report, err
+	}
+
+	report.Moves, report.Limited = planRebalance(config.Ping.IPs, targetSlaves, maxReassignments)
+
+	// one request per from/to pair
+	type pair struct{ from, to string }
+	pairs := map[pair][]string{}
+	order := []pair{}
+	for _, move := range report.Moves {
+		p := pair{move.From, move.To}
+		if _, ok := pairs[p]; !ok {
+			order = append(order, p)
+		}
+		pairs[p] = append(pairs[p], move.IP)
+	}
+
+	for _, p := range order {
+		slaves := map[string]bool{p.from: false}
+		if "" != p.to {
+			slaves[p.to] = true
+		}
+		if err := c.IPsSetSlavesContext(ctx, pairs[p], slaves); nil != err {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// planRebalance computes moves needed to spread ips evenly over targets
+func planRebalance(ips map[string]TestDesc, targets []string, limit int) ([]SlaveMove, bool) {
+	isTarget := map[string]bool{}
+	load := map[string]int{}
+	for _, slave := range targets {
+		isTarget[slave] = true
+		load[slave] = 0
+	}
+
+	list := make([]string, 0, len(ips))
+	assigned := make(map[string]map[string]bool, len(ips))
+	for ip, desc := range ips {
+		list = append(list, ip)
+		assigned[ip] = map[string]bool{}
+		for _, slave := range desc.Slaves {
+			assigned[ip][slave] = true
+			if isTarget[slave] {
+				load[slave]++
+			}
+		}
+	}
+	sort.Strings(list)
+
+	moves := []SlaveMove{}
+	full := func() bool { return limit >= 0 && len(moves) >= limit }
+
+	// leastLoaded returns target with lowest load not testing ip yet
+	leastLoaded := func(ip string) string {
+		best := ""
+		for _, slave := range targets {
+			if !assigned[ip][slave] && ("" == best || load[slave] < load[best]) {
+				best = slave
+			}
+		}
+		return best
+	}
+
+	move := func(ip, from, to string) {
+		delete(assigned[ip], from)
+		if isTarget[from] {
+			load[from]--
+		}
+		if "" != to {
+			assigned[ip][to] = true
+			load[to]++
+		}
+		moves = append(moves, SlaveMove{IP: ip, From: from, To: to})
+	}
+
+	// slaves outside of targets first
+	for _, ip := range list {
+		slaves := append([]string{}, ips[ip].Slaves...)
+		sort.Strings(slaves)
+		for _, slave := range slaves {
+			if isTarget[slave] || !assigned[ip][slave] {
+				continue
+			}
+			if full() {
+				return moves, true
+			}
+			move(ip, slave, leastLoaded(ip))
+		}
+	}
+
+	// then from most to least loaded target
+	for {
+		sorted := append([]string{}, targets...)
+		sort.SliceStable(sorted, func(i, j int) bool { return load[sorted[i]] > load[sorted[j]] })
+		from := sorted[0]
+
+		moved := false
+		for _, ip := range list {
+			if !assigned[ip][from] {
+				continue
+			}
+			to := leastLoaded(ip)
+			if "" == to || load[from]-load[to] <= 1 {
+				continue
+			}
+			if full() {
+				return moves, true
+			}
+			move(ip, from, to)
+			moved = true
+			break
+		}
+		if !moved {
+			return moves, false
+		}
+	}
+}
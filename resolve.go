@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net"
+)
+
+// AddIPFromHostname resolves A and AAAA records of hostname with resolver (net.DefaultResolver if nil), adds all
+// resulting IPs same way as AddIPs and returns them; empty description is replaced by hostname.
+// Failure of one record type is ignored if the other one was resolved
+func (c *Client) AddIPFromHostname(hostname string, slaves []string, description string, groups []string, favorite bool, resolver *net.Resolver) ([]string, error) {
+	return c.AddIPFromHostnameContext(context.Background(), hostname, slaves, description, groups, favorite, resolver)
+}
+
+// AddIPFromHostnameContext is same as AddIPFromHostname but resolution and request are bound to ctx
+func (c *Client) AddIPFromHostnameContext(ctx context.Context, hostname string, slaves []string, description string, groups []string, favorite bool, resolver *net.Resolver) ([]string, error) {
+	if nil == resolver {
+		resolver = net.DefaultResolver
+	}
+
+	ips := []string{}
+	var lookupErr error
+	for _, network := range []string{"ip4", "ip6"} {
+		addrs, err := resolver.LookupIP(ctx, network, hostname)
+		if nil != err {
+			lookupErr = err
+			continue
+		}
+		for _, addr := range addrs {
+			ips = appendUnique(ips, normalizeIP(addr.String()))
+		}
+	}
+
+	if 0 == len(ips) {
+		if nil == lookupErr {
+			lookupErr = errors.New("no addresses found for " + hostname)
+		}
+		return nil, lookupErr
+	}
+
+	if "" == description {
+		description = hostname
+	}
+	if err := c.AddIPsContext(ctx, ips, slaves, description, groups, favorite); nil != err {
+		return nil, err
+	}
+	return ips, nil
+}
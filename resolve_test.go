@@ -0,0 +1,186 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// dnsFixture is record type (1 = A, 28 = AAAA) -> lowercase fqdn -> addresses, names missing for both types
+// are NXDOMAIN and names listed with nil addresses answer SERVFAIL
+type dnsFixture map[uint16]map[string][]string
+
+// newFixtureResolver starts minimal udp dns server answering from records and returns resolver using it
+func newFixtureResolver(t *testing.T, records dnsFixture) *net.Resolver {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if nil != err {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if nil != err {
+				return
+			}
+			if reply := records.answer(buf[:n]); nil != reply {
+				conn.WriteTo(reply, addr)
+			}
+		}
+	}()
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", conn.LocalAddr().String())
+		},
+	}
+}
+
+// answer builds response to single-question query
+func (f dnsFixture) answer(query []byte) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// question name is sequence of labels ended by zero byte followed by type and class
+	labels := []string{}
+	end := 12
+	for end < len(query) && 0 != query[end] {
+		size := int(query[end])
+		if end+1+size > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[end+1:end+1+size]))
+		end += 1 + size
+	}
+	end += 5
+	if end > len(query) {
+		return nil
+	}
+	name := strings.ToLower(strings.Join(labels, ".")) + "."
+	qtype := binary.BigEndian.Uint16(query[end-4:])
+
+	_, knownA := f[1][name]
+	_, knownAAAA := f[28][name]
+	addrs, ok := f[qtype][name]
+	rcode := uint16(0)
+	switch {
+	case !knownA && !knownAAAA:
+		rcode = 3 // NXDOMAIN
+	case ok && nil == addrs:
+		rcode = 2 // SERVFAIL
+	}
+
+	reply := make([]byte, 12, 512)
+	copy(reply, query[:2])
+	binary.BigEndian.PutUint16(reply[2:], 0x8180|rcode)
+	binary.BigEndian.PutUint16(reply[4:], 1)
+	binary.BigEndian.PutUint16(reply[6:], uint16(len(addrs)))
+	reply = append(reply, query[12:end]...)
+	for _, addr := range addrs {
+		ip := net.ParseIP(addr)
+		if 1 == qtype {
+			ip = ip.To4()
+		}
+		rr := []byte{0xc0, 12, 0, byte(qtype), 0, 1, 0, 0, 0, 60, 0, byte(len(ip))}
+		reply = append(append(reply, rr...), ip...)
+	}
+	return reply
+}
+
+// addedIPs returns IPs sent by AddIPs with their descriptions
+func addedIPs(t *testing.T, s *recordingServer) map[string]string {
+	t.Helper()
+
+	var ips map[string]TestDesc
+	if err := json.Unmarshal(s.last().body["ips"], &ips); nil != err {
+		t.Fatal(err)
+	}
+	result := map[string]string{}
+	for ip, desc := range ips {
+		result[ip] = desc.Description
+	}
+	return result
+}
+
+func TestAddIPFromHostname(t *testing.T) {
+	resolver := newFixtureResolver(t, dnsFixture{
+		1: {
+			"dual.example.":   {"192.0.2.1", "192.0.2.2"},
+			"v4only.example.": {"192.0.2.10"},
+			"v6only.example.": {},
+		},
+		28: {
+			"dual.example.":   {"2001:db8::1"},
+			"v4only.example.": nil,
+			"v6only.example.": {"2001:DB8::20"},
+		},
+	})
+
+	for _, tc := range []struct {
+		hostname    string
+		description string
+		expected    map[string]string
+	}{
+		{"dual.example.", "dual stack", map[string]string{
+			"192.0.2.1":   "192.0.2.1 dual stack",
+			"192.0.2.2":   "192.0.2.2 dual stack",
+			"2001:db8::1": "2001:db8::1 dual stack",
+		}},
+		// AAAA lookup fails with SERVFAIL
+		{"v4only.example.", "", map[string]string{"192.0.2.10": "192.0.2.10 v4only.example."}},
+		// no A records
+		{"v6only.example.", "v6", map[string]string{"2001:db8::20": "2001:db8::20 v6"}},
+	} {
+		s := &recordingServer{}
+		c := newTestClient(t, s.ServeHTTP)
+
+		ips, err := c.AddIPFromHostname(tc.hostname, []string{"PRAGUE"}, tc.description, nil, false, resolver)
+		if nil != err {
+			t.Fatalf("%s: %v", tc.hostname, err)
+		}
+
+		expectedIPs := []string{}
+		for ip := range tc.expected {
+			expectedIPs = append(expectedIPs, ip)
+		}
+		sort.Strings(expectedIPs)
+		sort.Strings(ips)
+		if !reflect.DeepEqual(expectedIPs, ips) {
+			t.Errorf("%s: expected ips %v, got %v", tc.hostname, expectedIPs, ips)
+		}
+		if added := addedIPs(t, s); !reflect.DeepEqual(tc.expected, added) {
+			t.Errorf("%s: expected added %v, got %v", tc.hostname, tc.expected, added)
+		}
+	}
+}
+
+func TestAddIPFromHostnameNotResolved(t *testing.T) {
+	resolver := newFixtureResolver(t, dnsFixture{
+		1:  {"broken.example.": nil},
+		28: {"broken.example.": nil},
+	})
+	s := &recordingServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for _, hostname := range []string{"missing.example.", "broken.example."} {
+		ips, err := c.AddIPFromHostname(hostname, []string{"PRAGUE"}, "", nil, false, resolver)
+		if nil == err || nil != ips {
+			t.Errorf("%s: expected error, got %v %v", hostname, ips, err)
+		}
+	}
+	if 0 != len(s.requests) {
+		t.Errorf("expected no requests, got %v", s.requests)
+	}
+}
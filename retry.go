@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// RetryConfig describes how failed requests are repeated
+type RetryConfig struct {
+	MaxAttempts       int           // total count of attempts including first one
+	InitialDelay      time.Duration // delay before second attempt
+	MaxDelay          time.Duration // upper limit of delay between attempts, 0 means unlimited
+	Multiplier        float64       // delay growth factor between attempts, values below 1 are treated as 1
+	RetryableStatuses []int         // http statuses to retry, connection errors are always retried
+}
+
+// DefaultRetryableStatuses are used when RetryConfig.RetryableStatuses is empty
+var DefaultRetryableStatuses = []int{502, 503, 504}
+
+// WithRetry enables exponential-backoff retry of failed requests
+func WithRetry(cfg RetryConfig) Option {
+	return func(c *Client) {
+		c.optionsMu.Lock()
+		defer c.optionsMu.Unlock()
+		if cfg.MaxAttempts < 2 {
+			c.retry = nil
+			return
+		}
+		c.retry = &cfg
+	}
+}
+
+// retryable reports if attempt finished with status/err is worth to repeat
+func (cfg *RetryConfig) retryable(ctx context.Context, status int, err error) bool {
+	if nil != ctx.Err() {
+		return false
+	}
+
+	if 0 == status {
+		return nil != err
+	}
+
+	statuses := cfg.RetryableStatuses
+	if 0 == len(statuses) {
+		statuses = DefaultRetryableStatuses
+	}
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// next returns delay to use after current one
+func (cfg *RetryConfig) next(delay time.Duration) time.Duration {
+	if cfg.Multiplier > 1 {
+		delay = time.Duration(float64(delay) * cfg.Multiplier)
+	}
+	if cfg.MaxDelay > 0 && delay > cfg.MaxDelay {
+		delay = cfg.MaxDelay
+	}
+	return delay
+}
+
+// jitter returns random duration in [delay/2, delay)
+func jitter(delay time.Duration) time.Duration {
+	if delay < 2 {
+		return delay
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(delay-half)))
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
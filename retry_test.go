@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// sequenceHandler answers with statuses one by one (last one is repeated) and records time of each request
+type sequenceHandler struct {
+	mu       sync.Mutex
+	statuses []int
+	times    []time.Time
+}
+
+func (h *sequenceHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	n := len(h.times)
+	h.times = append(h.times, time.Now())
+	status := h.statuses[len(h.statuses)-1]
+	if n < len(h.statuses) {
+		status = h.statuses[n]
+	}
+	h.mu.Unlock()
+
+	if http.StatusOK != status {
+		w.WriteHeader(status)
+		return
+	}
+	writeTestJSON(w, map[string]string{"PRAGUE": "1.1.1.1:3030"})
+}
+
+func (h *sequenceHandler) calls() []time.Time {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]time.Time{}, h.times...)
+}
+
+func TestRetrySucceedsAfterTransientErrors(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503, 502, 200}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{MaxAttempts: 5, InitialDelay: time.Millisecond}))
+
+	slaves, err := c.GetSlavesIPs()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if "1.1.1.1" != slaves["PRAGUE"] {
+		t.Fatalf("unexpected result %v", slaves)
+	}
+	if n := len(h.calls()); 3 != n {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetryStopsAfterMaxAttempts(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{MaxAttempts: 4, InitialDelay: time.Millisecond}))
+
+	if _, err := c.GetSlavesIPs(); nil == err {
+		t.Fatal("expected error")
+	}
+	if n := len(h.calls()); 4 != n {
+		t.Fatalf("expected 4 attempts, got %d", n)
+	}
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{500, 200}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{MaxAttempts: 3, InitialDelay: time.Millisecond}))
+
+	if _, err := c.GetSlavesIPs(); nil == err {
+		t.Fatal("expected error")
+	}
+	if n := len(h.calls()); 1 != n {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestRetryCustomStatuses(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{500, 429, 200}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{
+		MaxAttempts:       3,
+		InitialDelay:      time.Millisecond,
+		RetryableStatuses: []int{429, 500},
+	}))
+
+	if _, err := c.GetSlavesIPs(); nil != err {
+		t.Fatal(err)
+	}
+	if n := len(h.calls()); 3 != n {
+		t.Fatalf("expected 3 attempts, got %d", n)
+	}
+}
+
+func TestRetryBackoffTiming(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503, 503, 503, 200}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{
+		MaxAttempts:  4,
+		InitialDelay: 40 * time.Millisecond,
+		Multiplier:   2,
+		MaxDelay:     100 * time.Millisecond,
+	}))
+
+	if _, err := c.GetSlavesIPs(); nil != err {
+		t.Fatal(err)
+	}
+
+	times := h.calls()
+	if 4 != len(times) {
+		t.Fatalf("expected 4 attempts, got %d", len(times))
+	}
+
+	// delays are 40ms, 80ms and 100ms (capped) with jitter down to half of them
+	for i, expected := range []time.Duration{40 * time.Millisecond, 80 * time.Millisecond, 100 * time.Millisecond} {
+		gap := times[i+1].Sub(times[i])
+		if gap < expected/2 {
+			t.Errorf("delay before attempt %d is %v, expected at least %v", i+2, gap, expected/2)
+		}
+	}
+}
+
+func TestRetryContextCancelMidRetry(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{MaxAttempts: 10, InitialDelay: time.Hour}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := c.GetSlavesIPsContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Fatalf("backoff sleep wasn't interrupted: %v", time.Since(start))
+	}
+	if n := len(h.calls()); 1 != n {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestRetryConfigNext(t *testing.T) {
+	cfg := RetryConfig{Multiplier: 3, MaxDelay: time.Second}
+
+	delay := 100 * time.Millisecond
+	for _, expected := range []time.Duration{300 * time.Millisecond, 900 * time.Millisecond, time.Second, time.Second} {
+		delay = cfg.next(delay)
+		if expected != delay {
+			t.Fatalf("expected %v, got %v", expected, delay)
+		}
+	}
+
+	cfg = RetryConfig{Multiplier: 0.5}
+	if d := cfg.next(time.Second); time.Second != d {
+		t.Fatalf("multiplier below 1 should keep delay, got %v", d)
+	}
+}
+
+func TestJitterRange(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		if d := jitter(time.Second); d < time.Second/2 || d >= time.Second {
+			t.Fatalf("jitter out of range: %v", d)
+		}
+	}
+}
+
+func TestWithRetryDisabledForSingleAttempt(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{503, 200}}
+	c := newTestClient(t, h.ServeHTTP, WithRetry(RetryConfig{MaxAttempts: 1, InitialDelay: time.Millisecond}))
+
+	if _, err := c.GetSlavesIPs(); nil == err {
+		t.Fatal("expected error")
+	}
+	if n := len(h.calls()); 1 != n {
+		t.Fatalf("expected 1 attempt, got %d", n)
+	}
+}
+
+func TestConfigureConcurrentWithRequests(t *testing.T) {
+	h := &sequenceHandler{statuses: []int{200}}
+	c := newTestClient(t, h.ServeHTTP)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			c.Configure(
+				WithRetry(RetryConfig{MaxAttempts: 2, InitialDelay: time.Millisecond}),
+				WithConcurrency(i%3),
+				WithBulkBatchSize(i),
+				WithRateLimiter(nil),
+				WithOverloadPollInterval(time.Duration(i)*time.Millisecond),
+			)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 20; i++ {
+			if _, err := c.GetSlavesIPs(); nil != err {
+				t.Error(err)
+				return
+			}
+			c.forEach([]string{"a", "b"}, func(string) {})
+			_ = c.batchSize()
+			_ = c.overloadInterval()
+		}
+	}()
+	wg.Wait()
+}
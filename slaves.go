@@ -0,0 +1,725 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MaintenanceStatus describes maintenance window of slave
+type MaintenanceStatus struct {
+	Enabled bool      `json:"enabled"`
+	Until   time.Time `json:"until"` // zero value means until disabled manually
+}
+
+// InMaintenance checks if maintenance window is active at moment t
+func (m MaintenanceStatus) InMaintenance(t time.Time) bool {
+	return m.Enabled && (m.Until.IsZero() || t.Before(m.Until))
+}
+
+// SlaveSetMaintenance enables/disables maintenance of slave (no alerts generated), zero until means until disabled manually
+func (c *Client) SlaveSetMaintenance(slave string, enable bool, until time.Time) error {
+	return c.SlaveSetMaintenanceContext(context.Background(), slave, enable, until)
+}
+
+// SlaveSetMaintenanceContext is same as SlaveSetMaintenance but request is bound to ctx
+func (c *Client) SlaveSetMaintenanceContext(ctx context.Context, slave string, enable bool, until time.Time) error {
+	if "" == slave {
+		return ErrEmptySlaveName
+	}
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/slaves/"+url.PathEscape(slave)+"/maintenance", MaintenanceStatus{
+		Enabled: enable,
+		Until:   until,
+	})
+}
+
+// GetSlaveMaintenanceStatus returns current maintenance window of slave
+func (c *Client) GetSlaveMaintenanceStatus(slave string) (MaintenanceStatus, error) {
+	return c.GetSlaveMaintenanceStatusContext(context.Background(), slave)
+}
+
+// GetSlaveMaintenanceStatusContext is same as GetSlaveMaintenanceStatus but request is bound to ctx
+func (c *Client) GetSlaveMaintenanceStatusContext(ctx context.Context, slave string) (MaintenanceStatus, error) {
+	var result MaintenanceStatus
+	if "" == slave {
+		return result, ErrEmptySlaveName
+	}
+	err := c.GetContext(ctx, c.url+"/v1/slaves/"+url.PathEscape(slave)+"/maintenance", &result)
+	return result, err
+}
+
+// WaitUntilSlaveActive polls maintenance status of slave every pollInterval until maintenance ends or ctx is done
+func (c *Client) WaitUntilSlaveActive(ctx context.Context, slave string, pollInterval time.Duration) error {
+	for {
+		status, err := c.GetSlaveMaintenanceStatusContext(ctx, slave)
+		if nil != err {
+			return err
+		}
+		if !status.InMaintenance(time.Now()) {
+			return nil
+		}
+
+		if err := sleepContext(ctx, pollInterval); nil != err {
+			return err
+		}
+	}
+}
+
+// SlaveLatencyResult is result of one slave check made by PingAllSlaves
+type SlaveLatencyResult struct {
+	Latency time.Duration
+	Err     error
+}
+
+// GetSlaveLatency measures time of TCP connect to slave's own API (address is taken from GetSlavesAddrs)
+func (c *Client) GetSlaveLatency(slave string) (time.Duration, error) {
+	return c.GetSlaveLatencyContext(context.Background(), slave)
+}
+
+// GetSlaveLatencyContext is same as GetSlaveLatency but requests are bound to ctx
+func (c *Client) GetSlaveLatencyContext(ctx context.Context, slave string) (time.Duration, error) {
+	addrs, err := c.GetSlavesAddrsContext(ctx)
+	if nil != err {
+		return 0, err
+	}
+
+	addr, ok := addrs[slave]
+	if !ok {
+		return 0, errors.New("unknown slave " + slave)
+	}
+
+	return dialLatency(ctx, addr)
+}
+
+// PingAllSlaves measures latency to all slaves in parallel, unreachable slaves have Err set
+func (c *Client) PingAllSlaves() (map[string]SlaveLatencyResult, error) {
+	return c.PingAllSlavesContext(context.Background())
+}
+
+// PingAllSlavesContext is same as PingAllSlaves but requests are bound to ctx
+func (c *Client) PingAllSlavesContext(ctx context.Context) (map[string]SlaveLatencyResult, error) {
+	addrs, err := c.GetSlavesAddrsContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	slaves := make([]string, 0, len(addrs))
+	for slave := range addrs {
+		slaves = append(slaves, slave)
+	}
+
+	var mu sync.Mutex
+	result := make(map[string]SlaveLatencyResult, len(addrs))
+
+	c.forEach(slaves, func(slave string) {
+		latency, err := dialLatency(ctx, addrs[slave])
+
+		mu.Lock()
+		defer mu.Unlock()
+		result[slave] = SlaveLatencyResult{Latency: latency, Err: err}
+	})
+
+	return result, nil
+}
+
+// dialLatency returns time needed to open TCP connection to addr
+func dialLatency(ctx context.Context, addr string) (time.Duration, error) {
+	var d net.Dialer
+
+	start := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if nil != err {
+		return 0, err
+	}
+	latency := time.Since(start)
+	conn.Close()
+
+	return latency, nil
+}
+
+// SlaveConfig is probe settings of slave, zero/nil fields are not changed by SetSlaveConfig
+type SlaveConfig struct {
+	ProbeIntervalMs int   `json:"probeIntervalMs,omitempty"`
+	PacketSize      int   `json:"packetSize,omitempty"`
+	TTL             int   `json:"ttl,omitempty"`
+	MaxTracehops    int   `json:"maxTracehops,omitempty"`
+	ICMPEnabled     *bool `json:"icmpEnabled,omitempty"`
+	TCPEnabled      *bool `json:"tcpEnabled,omitempty"`
+}
+
+// GetSlaveConfig returns probe settings of slave
+func (c *Client) GetSlaveConfig(slave string) (SlaveConfig, error) {
+	return c.GetSlaveConfigContext(context.Background(), slave)
+}
+
+// GetSlaveConfigContext is same as GetSlaveConfig but request is bound to ctx
+func (c *Client) GetSlaveConfigContext(ctx context.Context, slave string) (SlaveConfig, error) {
+	var result SlaveConfig
+	if "" == slave {
+		return result, ErrEmptySlaveName
+	}
+	err := c.GetContext(ctx, c.url+"/v1/slaves/"+url.PathEscape(slave)+"/config", &result)
+	return result, err
+}
+
+// SetSlaveConfig updates probe settings of slave, only non-zero fields of cfg are sent so others keep current values
+func (c *Client) SetSlaveConfig(slave string, cfg SlaveConfig) error {
+	return c.SetSlaveConfigContext(context.Background(), slave, cfg)
+}
+
+// SetSlaveConfigContext is same as SetSlaveConfig but request is bound to ctx
+func (c *Client) SetSlaveConfigContext(ctx context.Context, slave string, cfg SlaveConfig) error {
+	if "" == slave {
+		return ErrEmptySlaveName
+	}
+	return c._okResultSend(ctx, "PATCH", c.url+"/v1/slaves/"+url.PathEscape(slave)+"/config", cfg)
+}
+
+// GetSlavesOnline returns sorted list of slaves currently connected to master
+func (c *Client) GetSlavesOnline() ([]string, error) {
+	return c.GetSlavesOnlineContext(context.Background())
+}
+
+// GetSlavesOnlineContext is same as GetSlavesOnline but request is bound to ctx
+func (c *Client) GetSlavesOnlineContext(ctx context.Context) ([]string, error) {
+	return c.filterSlaves(ctx, func(s SlaveStatus) bool { return s.Online() })
+}
+
+// GetSlavesOffline returns sorted list of slaves currently unreachable from master
+func (c *Client) GetSlavesOffline() ([]string, error) {
+	return c.GetSlavesOfflineContext(context.Background())
+}
+
+// GetSlavesOfflineContext is same as GetSlavesOffline but request is bound to ctx
+func (c *Client) GetSlavesOfflineContext(ctx context.Context) ([]string, error) {
+	return c.filterSlaves(ctx, func(s SlaveStatus) bool { return !s.Online() })
+}
+
+// LastSeenBefore returns sorted list of slaves that haven't reported to master during last d
+func (c *Client) LastSeenBefore(d time.Duration) ([]string, error) {
+	return c.LastSeenBeforeContext(context.Background(), d)
+}
+
+// LastSeenBeforeContext is same as LastSeenBefore but request is bound to ctx
+func (c *Client) LastSeenBeforeContext(ctx context.Context, d time.Duration) ([]string, error) {
+	limit := time.Now().Add(-d)
+	return c.filterSlaves(ctx, func(s SlaveStatus) bool { return s.Last.Before(limit) })
+}
+
+// filterSlaves returns sorted list of slaves which status matches
+func (c *Client) filterSlaves(ctx context.Context, match func(SlaveStatus) bool) ([]string, error) {
+	slaves, err := c.GetSlavesStatusContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	result := []string{}
+	for slave, status := range slaves {
+		if match(status) {
+			result = append(result, slave)
+		}
+	}
+	sort.Strings(result)
+
+	return result, nil
+}
+
+// RenameSlave changes name of slave keeping its IPs/tests assignments.
+//
+// Server-side rename (PUT /v1/slaves) is atomic. If server doesn't support it, slave is re-created under new name,
+// all IPs/tests are reassigned from old to new name and old slave is deleted; this fallback is not atomic
+// and on error in the middle both slaves may exist, so it's safe to call RenameSlave again.
+func (c *Client) RenameSlave(oldName string, newName string) error {
+	return c.RenameSlaveContext(context.Background(), oldName, newName)
+}
+
+// RenameSlaveContext is same as RenameSlave but requests are bound to ctx
+func (c *Client) RenameSlaveContext(ctx context.Context, oldName string, newName string) error {
+	if "" == oldName || "" == newName {
+		return ErrEmptySlaveName
+	}
+
+	raw, err := json.Marshal(map[string]string{
+		"oldName": oldName,
+		"newName": newName,
+	})
+	if nil != err {
+		return err
+	}
+
+	var r result
+	status, err := c.execute(ctx, newRequest("PUT", c.url+"/v1/slaves", raw, "application/json", nil), &r)
+	if http.StatusNotFound == status || http.StatusMethodNotAllowed == status {
+		return c.renameSlaveFallback(ctx, oldName, newName)
+	}
+	if nil != err {
+		return err
+	}
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return errors.New(r.Error)
+		}
+		return errors.New("unknown error")
+	}
+
+	return nil
+}
+
+// renameSlaveFallback re-creates slave with new name and moves all IPs/tests to it; old slave is deleted only after
+// IPs/tests are reassigned as master drops IPs left without slaves
+func (c *Client) renameSlaveFallback(ctx context.Context, oldName string, newName string) error {
+	addrs, err := c.GetSlavesAddrsContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	// new slave may already exist if previous attempt failed in the middle
+	if _, created := addrs[newName]; !created {
+		addr, ok := addrs[oldName]
+		if !ok {
+			return errors.New("unknown slave " + oldName)
+		}
+		ip, port, err := splitSlaveAddr(addr)
+		if nil != err {
+			return err
+		}
+		if err := c.AddSlaveContext(ctx, ip, port, newName, ""); nil != err {
+			return err
+		}
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	targets := []string{}
+	for _, tests := range []map[string]TestDesc{config.Ping.IPs, config.HTTP.URLs} {
+		for target, desc := range tests {
+			for _, slave := range desc.Slaves {
+				if slave == oldName {
+					targets = append(targets, target)
+					break
+				}
+			}
+		}
+	}
+	sort.Strings(targets)
+
+	if 0 != len(targets) {
+		if err := c.IPsSetSlavesContext(ctx, targets, map[string]bool{newName: true, oldName: false}); nil != err {
+			return err
+		}
+	}
+
+	return c.DeleteSlaveContext(ctx, oldName)
+}
+
+// UpdateSlave changes name and/or address of slave keeping its IPs/tests assignments, only non-nil fields of patch are changed.
+//
+// Server-side update (PATCH /v1/slaves/{name}) is atomic. If server doesn't support it, address is changed in place
+// (POST /v1/slaves with "update": true) and then name by RenameSlave, so changing both isn't atomic. Slave is never
+// deleted to change its address as master drops IPs left without slaves, ErrSlaveUpdateUnsupported is returned
+// instead when server can't update address in place.
+func (c *Client) UpdateSlave(name string, patch SlavePatch) error {
+	return c.UpdateSlaveContext(context.Background(), name, patch)
+}
+
+// UpdateSlaveContext is same as UpdateSlave but requests are bound to ctx
+func (c *Client) UpdateSlaveContext(ctx context.Context, name string, patch SlavePatch) error {
+	if "" == name || (nil != patch.NewName && "" == *patch.NewName) {
+		return ErrEmptySlaveName
+	}
+	if nil != patch.NewIP && nil == *patch.NewIP {
+		return errors.New("empty slave ip")
+	}
+
+	payload := map[string]interface{}{}
+	if nil != patch.NewName {
+		payload["name"] = *patch.NewName
+	}
+	if nil != patch.NewIP {
+		payload["ip"] = patch.NewIP.String()
+	}
+	if nil != patch.NewPort {
+		payload["port"] = *patch.NewPort
+	}
+	if 0 == len(payload) {
+		return nil
+	}
+
+	raw, err := json.Marshal(payload)
+	if nil != err {
+		return err
+	}
+
+	var r result
+	status, err := c.execute(ctx, newRequest("PATCH", c.url+"/v1/slaves/"+url.PathEscape(name), raw, "application/json", nil), &r)
+	if http.StatusNotFound == status || http.StatusMethodNotAllowed == status {
+		return c.updateSlaveFallback(ctx, name, patch)
+	}
+	if nil != err {
+		return err
+	}
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return errors.New(r.Error)
+		}
+		return errors.New("unknown error")
+	}
+
+	return nil
+}
+
+// updateSlaveFallback applies patch on server without PATCH support, address first so that rename (which may
+// re-create slave) copies already changed one
+func (c *Client) updateSlaveFallback(ctx context.Context, name string, patch SlavePatch) error {
+	if nil != patch.NewIP || nil != patch.NewPort {
+		if err := c.updateSlaveAddr(ctx, name, patch.NewIP, patch.NewPort); nil != err {
+			return err
+		}
+	}
+
+	if nil != patch.NewName && name != *patch.NewName {
+		return c.RenameSlaveContext(ctx, name, *patch.NewName)
+	}
+	return nil
+}
+
+// updateSlaveAddr changes address of existing slave in place using POST /v1/slaves with "update": true,
+// nil ip/port are kept
+func (c *Client) updateSlaveAddr(ctx context.Context, name string, newIP *net.IP, newPort *uint16) error {
+	addrs, err := c.GetSlavesAddrsContext(ctx)
+	if nil != err {
+		return err
+	}
+	addr, ok := addrs[name]
+	if !ok {
+		return errors.New("unknown slave " + name)
+	}
+
+	ip, port, err := splitSlaveAddr(addr)
+	if nil != err {
+		return err
+	}
+	if nil != newIP {
+		ip = *newIP
+	}
+	if nil != newPort {
+		port = *newPort
+	}
+	if net.JoinHostPort(ip.String(), strconv.Itoa(int(port))) == addr {
+		return nil
+	}
+
+	raw, err := json.Marshal(map[string]interface{}{
+		"ip":     ip.String(),
+		"port":   port,
+		"name":   name,
+		"update": true,
+	})
+	if nil != err {
+		return err
+	}
+
+	var r result
+	status, err := c.execute(ctx, newRequest("POST", c.url+"/v1/slaves", raw, "application/json", nil), &r)
+	if http.StatusNotFound == status || http.StatusMethodNotAllowed == status {
+		return ErrSlaveUpdateUnsupported
+	}
+	if nil != err {
+		return err
+	}
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return errors.New(r.Error)
+		}
+		return errors.New("unknown error")
+	}
+
+	return nil
+}
+
+// AddSlaveWithValidation is same as AddSlave but first checks that slave accepts TCP connections on ip:port within timeout,
+// returned error wraps ErrSlaveUnreachable and dial error if it doesn't (both can be tested by errors.Is/errors.As)
+func (c *Client) AddSlaveWithValidation(ip net.IP, port uint16, name string, copyFrom string, timeout time.Duration) error {
+	return c.AddSlaveWithValidationContext(context.Background(), ip, port, name, copyFrom, timeout)
+}
+
+// AddSlaveWithValidationContext is same as AddSlaveWithValidation but connection check and request are bound to ctx
+func (c *Client) AddSlaveWithValidationContext(ctx context.Context, ip net.IP, port uint16, name string, copyFrom string, timeout time.Duration) error {
+	if nil == ip {
+		return errors.New("empty slave ip")
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip.String(), strconv.Itoa(int(port))))
+	if nil != err {
+		return slaveUnreachableError{err}
+	}
+	conn.Close()
+
+	return c.AddSlaveContext(ctx, ip, port, name, copyFrom)
+}
+
+// slaveUnreachableError is dial error of AddSlaveWithValidation
+type slaveUnreachableError struct {
+	err error
+}
+
+func (e slaveUnreachableError) Error() string {
+	return ErrSlaveUnreachable.Error() + ": " + e.err.Error()
+}
+
+func (e slaveUnreachableError) Is(target error) bool {
+	return ErrSlaveUnreachable == target
+}
+
+func (e slaveUnreachableError) Unwrap() error {
+	return e.err
+}
+
+// splitSlaveAddr parses ip:port address of slave
+func splitSlaveAddr(addr string) (net.IP, uint16, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if nil != err {
+		return nil, 0, err
+	}
+
+	ip := net.ParseIP(host)
+	if nil == ip {
+		return nil, 0, errors.New("invalid slave ip " + host)
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if nil != err {
+		return nil, 0, err
+	}
+
+	return ip, uint16(port), nil
+}
+
+// SlaveAggStats is aggregated load of one slave, useful for capacity planning
+type SlaveAggStats struct {
+	MonitoredIPCount   int     `json:"monitoredIPCount"`
+	MonitoredURLCount  int     `json:"monitoredURLCount"`
+	PacketsSentLastMin int     `json:"packetsSentLastMin"`
+	AvgRTTLastMin      float64 `json:"avgRTTLastMin"`    // ms
+	MaxLossIPLastMin   string  `json:"maxLossIPLastMin"` // IP with highest loss during last minute
+}
+
+// GetSlaveStats returns aggregated statistics of slave
+func (c *Client) GetSlaveStats(slave string) (SlaveAggStats, error) {
+	return c.GetSlaveStatsContext(context.Background(), slave)
+}
+
+// GetSlaveStatsContext is same as GetSlaveStats but request is bound to ctx
+func (c *Client) GetSlaveStatsContext(ctx context.Context, slave string) (SlaveAggStats, error) {
+	var result SlaveAggStats
+	if "" == slave {
+		return result, ErrEmptySlaveName
+	}
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves/"+url.PathEscape(slave)+"/stats", &result)
+	return result, err
+}
+
+// GetSlavesGeoInfo returns geographic location of all slaves as known by server,
+// see cocogeo.LocalGeoEnrich for lookup in local GeoLite2 database
+func (c *Client) GetSlavesGeoInfo() (map[string]SlaveGeoInfo, error) {
+	return c.GetSlavesGeoInfoContext(context.Background())
+}
+
+// GetSlavesGeoInfoContext is same as GetSlavesGeoInfo but request is bound to ctx
+func (c *Client) GetSlavesGeoInfoContext(ctx context.Context) (map[string]SlaveGeoInfo, error) {
+	var result map[string]SlaveGeoInfo
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves/geo", &result)
+	return result, err
+}
+
+// DefaultOverloadPollInterval is delay between checks of AlertIfSlaveOverloaded unless set by
+// WithOverloadPollInterval
+const DefaultOverloadPollInterval = 30 * time.Second
+
+// WithOverloadPollInterval sets delay between checks of AlertIfSlaveOverloaded, d <= 0 means
+// DefaultOverloadPollInterval
+func WithOverloadPollInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.optionsMu.Lock()
+		c.overloadPollInterval = d
+		c.optionsMu.Unlock()
+	}
+}
+
+func (c *Client) overloadInterval() time.Duration {
+	c.optionsMu.RLock()
+	defer c.optionsMu.RUnlock()
+	if c.overloadPollInterval <= 0 {
+		return DefaultOverloadPollInterval
+	}
+	return c.overloadPollInterval
+}
+
+// OverloadAlert is sent by AlertIfSlaveOverloaded when connection count of slave crosses threshold
+type OverloadAlert struct {
+	Slave       string
+	Connections int
+	Threshold   int
+	Overloaded  bool // false when count dropped back to threshold or below
+	Time        time.Time
+}
+
+// GetSlaveConnectionCount returns count of active probe connections of slave
+func (c *Client) GetSlaveConnectionCount(slave string) (int, error) {
+	return c.GetSlaveConnectionCountContext(context.Background(), slave)
+}
+
+// GetSlaveConnectionCountContext is same as GetSlaveConnectionCount but request is bound to ctx
+func (c *Client) GetSlaveConnectionCountContext(ctx context.Context, slave string) (int, error) {
+	if "" == slave {
+		return 0, ErrEmptySlaveName
+	}
+
+	var result struct {
+		Connections int `json:"connections"`
+	}
+	err := c.GetContext(ctx, c.url+"/v1/status/slaves/"+url.PathEscape(slave)+"/connections", &result)
+	return result.Connections, err
+}
+
+// GetAllSlavesConnectionCount returns count of active probe connections of every slave, it's taken from slaves status
+// if server reports it there, otherwise slaves are asked one by one (see WithConcurrency)
+func (c *Client) GetAllSlavesConnectionCount() (map[string]int, error) {
+	return c.GetAllSlavesConnectionCountContext(context.Background())
+}
+
+// GetAllSlavesConnectionCountContext is same as GetAllSlavesConnectionCount but requests are bound to ctx
+func (c *Client) GetAllSlavesConnectionCountContext(ctx context.Context) (map[string]int, error) {
+	var status map[string]struct {
+		Connections *int `json:"connections"`
+	}
+	if err := c.GetContext(ctx, c.url+"/v1/status/slaves", &status); nil != err {
+		return nil, err
+	}
+
+	result := make(map[string]int, len(status))
+	missing := []string{}
+	for slave, s := range status {
+		if nil == s.Connections {
+			missing = append(missing, slave)
+			continue
+		}
+		result[slave] = *s.Connections
+	}
+	sort.Strings(missing)
+
+	var mu sync.Mutex
+	errs := MultiError{}
+
+	c.forEach(missing, func(slave string) {
+		count, err := c.GetSlaveConnectionCountContext(ctx, slave)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if nil != err {
+			errs[slave] = err
+			return
+		}
+		result[slave] = count
+	})
+
+	return result, errs.errOrNil()
+}
+
+// AlertIfSlaveOverloaded checks connection count of slave every poll interval (see WithOverloadPollInterval) and
+// sends alert to ch when it rises above threshold (and again when it drops back); it blocks until check fails or
+// forever, see context variant
+func (c *Client) AlertIfSlaveOverloaded(slave string, threshold int, ch chan<- OverloadAlert) error {
+	return c.AlertIfSlaveOverloadedContext(context.Background(), slave, threshold, ch)
+}
+
+// AlertIfSlaveOverloadedContext is same as AlertIfSlaveOverloaded but returns ctx.Err() when ctx is done
+func (c *Client) AlertIfSlaveOverloadedContext(ctx context.Context, slave string, threshold int, ch chan<- OverloadAlert) error {
+	overloaded := false
+
+	for {
+		count, err := c.GetSlaveConnectionCountContext(ctx, slave)
+		if nil != err {
+			if nil != ctx.Err() {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		if (count > threshold) != overloaded {
+			overloaded = !overloaded
+			alert := OverloadAlert{
+				Slave:       slave,
+				Connections: count,
+				Threshold:   threshold,
+				Overloaded:  overloaded,
+				Time:        time.Now(),
+			}
+			select {
+			case ch <- alert:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		if err := sleepContext(ctx, c.overloadInterval()); nil != err {
+			return err
+		}
+	}
+}
+
+// SlaveCapacity is current load and limits of slave, zero MaxIPs/MaxURLs mean no limit
+type SlaveCapacity struct {
+	CurrentIPs      int     `json:"currentIPs"`
+	MaxIPs          int     `json:"maxIPs"`
+	CurrentURLs     int     `json:"currentURLs"`
+	MaxURLs         int     `json:"maxURLs"`
+	CPUUsagePercent float64 `json:"cpuUsage"`
+	MemUsagePercent float64 `json:"memUsage"`
+}
+
+// GetSlavesCapacity returns load and limits of all slaves
+func (c *Client) GetSlavesCapacity() (map[string]SlaveCapacity, error) {
+	return c.GetSlavesCapacityContext(context.Background())
+}
+
+// GetSlavesCapacityContext is same as GetSlavesCapacity but request is bound to ctx
+func (c *Client) GetSlavesCapacityContext(ctx context.Context) (map[string]SlaveCapacity, error) {
+	var result map[string]SlaveCapacity
+	err := c.GetContext(ctx, c.url+"/v1/capacity/slaves", &result)
+	return result, err
+}
+
+// IsSlaveAtCapacity reports if slave can't handle more IPs (CurrentIPs >= MaxIPs), slave without limit is never at capacity
+func (c *Client) IsSlaveAtCapacity(slave string) (bool, error) {
+	return c.IsSlaveAtCapacityContext(context.Background(), slave)
+}
+
+// IsSlaveAtCapacityContext is same as IsSlaveAtCapacity but request is bound to ctx
+func (c *Client) IsSlaveAtCapacityContext(ctx context.Context, slave string) (bool, error) {
+	if "" == slave {
+		return false, ErrEmptySlaveName
+	}
+
+	capacity, err := c.GetSlavesCapacityContext(ctx)
+	if nil != err {
+		return false, err
+	}
+
+	current, ok := capacity[slave]
+	if !ok {
+		return false, errors.New("unknown slave " + slave)
+	}
+	return current.MaxIPs > 0 && current.CurrentIPs >= current.MaxIPs, nil
+}
@@ -0,0 +1,341 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// slaveConfigServer keeps per-slave config as raw json fields and merges PATCH payloads into it
+type slaveConfigServer struct {
+	mu      sync.Mutex
+	configs map[string]map[string]json.RawMessage
+	patches []map[string]json.RawMessage
+}
+
+func (s *slaveConfigServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	slave := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/slaves/"), "/config")
+	config, ok := s.configs[slave]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		writeTestJSON(w, config)
+	case "PATCH":
+		var patch map[string]json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&patch); nil != err {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		s.patches = append(s.patches, patch)
+		for key, value := range patch {
+			config[key] = value
+		}
+		writeTestJSON(w, result{Result: "OK"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestSetSlaveConfigKeepsOmittedFields(t *testing.T) {
+	s := &slaveConfigServer{configs: map[string]map[string]json.RawMessage{
+		"PRAGUE": {
+			"probeIntervalMs": json.RawMessage(`1000`),
+			"packetSize":      json.RawMessage(`64`),
+			"ttl":             json.RawMessage(`64`),
+			"maxTracehops":    json.RawMessage(`30`),
+			"icmpEnabled":     json.RawMessage(`true`),
+			"tcpEnabled":      json.RawMessage(`true`),
+		},
+	}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	disabled := false
+	if err := c.SetSlaveConfig("PRAGUE", SlaveConfig{PacketSize: 1400, TCPEnabled: &disabled}); nil != err {
+		t.Fatal(err)
+	}
+
+	if patch := s.patches[0]; 2 != len(patch) || "1400" != string(patch["packetSize"]) || "false" != string(patch["tcpEnabled"]) {
+		t.Fatalf("only changed fields should be sent, got %v", patch)
+	}
+
+	cfg, err := c.GetSlaveConfig("PRAGUE")
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 1000 != cfg.ProbeIntervalMs || 1400 != cfg.PacketSize || 64 != cfg.TTL || 30 != cfg.MaxTracehops ||
+		nil == cfg.ICMPEnabled || !*cfg.ICMPEnabled || nil == cfg.TCPEnabled || *cfg.TCPEnabled {
+		t.Fatalf("unexpected merged config %+v", cfg)
+	}
+}
+
+func TestSlaveConfigEmptyName(t *testing.T) {
+	c := newTestClient(t, http.NotFound)
+
+	if _, err := c.GetSlaveConfig(""); !errors.Is(err, ErrEmptySlaveName) {
+		t.Fatalf("expected ErrEmptySlaveName, got %v", err)
+	}
+	if err := c.SetSlaveConfig("", SlaveConfig{TTL: 10}); !errors.Is(err, ErrEmptySlaveName) {
+		t.Fatalf("expected ErrEmptySlaveName, got %v", err)
+	}
+}
+
+// slavesStatusFixture covers all status spellings and last seen times relative to now
+func slavesStatusFixture(now time.Time) map[string]SlaveStatus {
+	return map[string]SlaveStatus{
+		"PRAGUE":  {Host: "10.0.0.1", Status: "online", Last: now.Add(-10 * time.Second)},
+		"LONDON":  {Host: "10.0.0.2", Status: "OK", Last: now.Add(-30 * time.Second)},
+		"PARIS":   {Host: "10.0.0.3", Status: "Online", Last: now.Add(-10 * time.Minute)},
+		"TOKYO":   {Host: "10.0.0.4", Status: "offline", Last: now.Add(-2 * time.Hour)},
+		"SYDNEY":  {Host: "10.0.0.5", Status: "connecting", Last: now.Add(-5 * time.Minute)},
+		"BERLIN":  {Host: "10.0.0.6", Status: "", Last: time.Time{}},
+		"NEWYORK": {Host: "10.0.0.7", Status: "error", Last: now.Add(-time.Second)},
+	}
+}
+
+func newSlavesStatusClient(t *testing.T, status map[string]SlaveStatus) *Client {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if "/v1/status/slaves" != r.URL.Path {
+			http.NotFound(w, r)
+			return
+		}
+		writeTestJSON(w, status)
+	})
+}
+
+func TestGetSlavesOnlineOffline(t *testing.T) {
+	c := newSlavesStatusClient(t, slavesStatusFixture(time.Now()))
+
+	online, err := c.GetSlavesOnline()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if expected := []string{"LONDON", "PARIS", "PRAGUE"}; !reflect.DeepEqual(expected, online) {
+		t.Fatalf("expected online %v, got %v", expected, online)
+	}
+
+	offline, err := c.GetSlavesOffline()
+	if nil != err {
+		t.Fatal(err)
+	}
+	if expected := []string{"BERLIN", "NEWYORK", "SYDNEY", "TOKYO"}; !reflect.DeepEqual(expected, offline) {
+		t.Fatalf("expected offline %v, got %v", expected, offline)
+	}
+}
+
+func TestLastSeenBefore(t *testing.T) {
+	c := newSlavesStatusClient(t, slavesStatusFixture(time.Now()))
+
+	for _, tc := range []struct {
+		d        time.Duration
+		expected []string
+	}{
+		{time.Minute, []string{"BERLIN", "PARIS", "SYDNEY", "TOKYO"}},
+		{6 * time.Minute, []string{"BERLIN", "PARIS", "TOKYO"}},
+		{time.Hour, []string{"BERLIN", "TOKYO"}},
+		{24 * time.Hour, []string{"BERLIN"}},
+	} {
+		stale, err := c.LastSeenBefore(tc.d)
+		if nil != err {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(tc.expected, stale) {
+			t.Errorf("%v: expected %v, got %v", tc.d, tc.expected, stale)
+		}
+	}
+}
+
+func TestSlaveFiltersEmptyAndError(t *testing.T) {
+	c := newSlavesStatusClient(t, map[string]SlaveStatus{})
+
+	online, err := c.GetSlavesOnline()
+	if nil != err || nil == online || 0 != len(online) {
+		t.Fatalf("expected empty non-nil list, got %v %v", online, err)
+	}
+
+	c = newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	if _, err := c.GetSlavesOffline(); nil == err {
+		t.Fatal("expected error")
+	}
+}
+
+// connectionCountServer answers connection count requests with counts one by one (last one is repeated)
+type connectionCountServer struct {
+	mu     sync.Mutex
+	counts []int
+	calls  int
+}
+
+func (s *connectionCountServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if "/v1/status/slaves/PRAGUE/connections" != r.URL.Path {
+		http.NotFound(w, r)
+		return
+	}
+
+	s.mu.Lock()
+	count := s.counts[len(s.counts)-1]
+	if s.calls < len(s.counts) {
+		count = s.counts[s.calls]
+	}
+	s.calls++
+	s.mu.Unlock()
+
+	writeTestJSON(w, map[string]int{"connections": count})
+}
+
+func TestAlertIfSlaveOverloaded(t *testing.T) {
+	s := &connectionCountServer{counts: []int{5, 15, 20, 8, 3}}
+	c := newTestClient(t, s.ServeHTTP, WithOverloadPollInterval(time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := make(chan OverloadAlert)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.AlertIfSlaveOverloadedContext(ctx, "PRAGUE", 10, ch)
+	}()
+
+	var alerts []OverloadAlert
+	for len(alerts) < 2 {
+		select {
+		case alert := <-ch:
+			alerts = append(alerts, alert)
+		case err := <-done:
+			t.Fatalf("watcher stopped early: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for alerts, got %+v", alerts)
+		}
+	}
+	cancel()
+
+	if err := <-done; context.Canceled != err {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if !alerts[0].Overloaded || 15 != alerts[0].Connections || 10 != alerts[0].Threshold || "PRAGUE" != alerts[0].Slave {
+		t.Errorf("unexpected first alert %+v", alerts[0])
+	}
+	if alerts[1].Overloaded || 8 != alerts[1].Connections {
+		t.Errorf("unexpected second alert %+v", alerts[1])
+	}
+}
+
+func TestOverloadPollIntervalDefault(t *testing.T) {
+	if DefaultOverloadPollInterval != New("http://localhost", "", "").overloadInterval() {
+		t.Error("expected default poll interval")
+	}
+	if DefaultOverloadPollInterval != New("http://localhost", "", "", WithOverloadPollInterval(-time.Second)).overloadInterval() {
+		t.Error("expected default poll interval for negative value")
+	}
+}
+
+func TestUpdateSlavePatchFields(t *testing.T) {
+	newName := "VIENNA"
+	newIP := net.ParseIP("3.3.3.3")
+	newPort := uint16(4040)
+
+	for i := 1; i < 8; i++ {
+		patch := SlavePatch{}
+		expected := map[string]string{}
+		if 0 != i&1 {
+			patch.NewName = &newName
+			expected["name"] = `"VIENNA"`
+		}
+		if 0 != i&2 {
+			patch.NewIP = &newIP
+			expected["ip"] = `"3.3.3.3"`
+		}
+		if 0 != i&4 {
+			patch.NewPort = &newPort
+			expected["port"] = `4040`
+		}
+
+		s := &recordingServer{}
+		c := newTestClient(t, s.ServeHTTP)
+		if err := c.UpdateSlave("PRAGUE", patch); nil != err {
+			t.Fatal(err)
+		}
+
+		r := s.last()
+		if "PATCH" != r.method || "/v1/slaves/PRAGUE" != r.path {
+			t.Errorf("%v: unexpected request %s %s", expected, r.method, r.path)
+		}
+		body := map[string]string{}
+		for key, value := range r.body {
+			body[key] = string(value)
+		}
+		if !reflect.DeepEqual(expected, body) {
+			t.Errorf("expected payload %v, got %v", expected, body)
+		}
+	}
+}
+
+func TestWatchSlavesStatusInvalidInterval(t *testing.T) {
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeTestJSON(w, slavesStatusFixture(time.Now()))
+	})
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		ch, err := c.WatchSlavesStatus(context.Background(), interval)
+		if nil == err || nil != ch {
+			t.Errorf("interval %v: expected error, got %v", interval, err)
+		}
+	}
+	if 0 != requests {
+		t.Errorf("expected no requests, got %d", requests)
+	}
+}
+
+func TestWatchSlavesStatusEvents(t *testing.T) {
+	var mu sync.Mutex
+	status := map[string]SlaveStatus{"PRAGUE": {Host: "10.0.0.1", Status: "online"}}
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		writeTestJSON(w, status)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch, err := c.WatchSlavesStatus(ctx, time.Millisecond)
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	status = map[string]SlaveStatus{
+		"PRAGUE": {Host: "10.0.0.1", Status: "offline"},
+		"LONDON": {Host: "10.0.0.2", Status: "online"},
+	}
+	mu.Unlock()
+
+	select {
+	case event := <-ch:
+		if !reflect.DeepEqual([]string{"PRAGUE"}, event.NewlyOffline) || !reflect.DeepEqual([]string{"LONDON"}, event.NewlyOnline) {
+			t.Errorf("unexpected event %+v", event)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"sort"
+	"strings"
+)
+
+// GroupChange adds IPs to group and removes other IPs from it
+type GroupChange struct {
+	Group     string // without trailing "->"
+	AddIPs    []string
+	RemoveIPs []string
+}
+
+// ConfigSnapshot is set of changes applied together by ApplyConfigSnapshot
+type ConfigSnapshot struct {
+	Add          map[string]TestDesc
+	Update       map[string]TestDescPatch
+	Delete       []string
+	GroupChanges []GroupChange
+}
+
+// SnapshotReport is result of ApplyConfigSnapshot, every IP is listed in one of fields
+type SnapshotReport struct {
+	Added   []string
+	Updated []string // updated by Update or GroupChanges
+	Deleted []string
+	Failed  map[string]error
+}
+
+// ApplyConfigSnapshot applies snapshot with as few requests as possible: deletes go first, then adds, updates and group
+// changes are merged per IP and sent in batches (see WithBulkBatchSize); errors are reported per IP in report.Failed and
+// returned as MultiError. It's not atomic on server side, failed IPs have to be fixed by caller
+func (c *Client) ApplyConfigSnapshot(snapshot ConfigSnapshot) (SnapshotReport, error) {
+	return c.ApplyConfigSnapshotContext(context.Background(), snapshot)
+}
+
+// ApplyConfigSnapshotContext is same as ApplyConfigSnapshot but requests are bound to ctx
+func (c *Client) ApplyConfigSnapshotContext(ctx context.Context, snapshot ConfigSnapshot) (SnapshotReport, error) {
+	report := SnapshotReport{
+		Added:   []string{},
+		Updated: []string{},
+		Deleted: []string{},
+		Failed:  map[string]error{},
+	}
+
+	if 0 != len(snapshot.Delete) {
+		deleted, _ := c.DeleteIPsSafeContext(ctx, snapshot.Delete, ContinueOnError)
+		report.Deleted = deleted.Deleted
+		for ip, err := range deleted.Failed {
+			report.Failed[ip] = err
+		}
+	}
+
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return report, err
+	}
+
+	// final configuration of every added/changed IP
+	changed := map[string]TestDesc{}
+	added := map[string]bool{}
+	for ip, desc := range snapshot.Add {
+		changed[ip] = desc
+		added[ip] = true
+	}
+
+	current := func(ip string) (TestDesc, bool) {
+		if desc, ok := changed[ip]; ok {
+			return desc, true
+		}
+		desc, ok := config.Ping.IPs[ip]
+		return desc, ok
+	}
+
+	for ip, patch := range snapshot.Update {
+		desc, ok := current(ip)
+		if !ok {
+			report.Failed[ip] = ErrIPNotFound
+			continue
+		}
+		changed[ip] = patch.Apply(desc)
+	}
+
+	for _, change := range snapshot.GroupChanges {
+		group := strings.TrimSuffix(change.Group, groupSuffix) + groupSuffix
+		for _, ip := range change.AddIPs {
+			desc, ok := current(ip)
+			if !ok {
+				report.Failed[ip] = ErrIPNotFound
+				continue
+			}
+			desc.Groups = appendUnique(append([]string{}, desc.Groups...), group)
+			changed[ip] = desc
+		}
+		for _, ip := range change.RemoveIPs {
+			desc, ok := current(ip)
+			if !ok {
+				report.Failed[ip] = ErrIPNotFound
+				continue
+			}
+			desc.Groups = removeString(desc.Groups, group)
+			changed[ip] = desc
+		}
+	}
+
+	ips := make([]string, 0, len(changed))
+	for ip := range changed {
+		if _, failed := report.Failed[ip]; !failed {
+			ips = append(ips, ip)
+		}
+	}
+	sort.Strings(ips)
+
+	for _, part := range batches(ips, c.batchSize()) {
+		payload := make(map[string]TestDesc, len(part))
+		for _, ip := range part {
+			payload[ip] = changed[ip]
+		}
+
+		err := c.AddIPsRawContext(ctx, payload)
+		for _, ip := range part {
+			switch {
+			case nil != err:
+				report.Failed[ip] = err
+			case added[ip]:
+				report.Added = append(report.Added, ip)
+			default:
+				report.Updated = append(report.Updated, ip)
+			}
+		}
+	}
+
+	return report, MultiError(report.Failed).errOrNil()
+}
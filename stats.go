@@ -0,0 +1,329 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// HistoryResolutions lists aggregation periods supported by GetHistoricalStats
+var HistoryResolutions = []time.Duration{time.Minute, 5 * time.Minute, time.Hour, 24 * time.Hour}
+
+// GetHistoricalStats returns stats for all IPs/URLs in group for period from-to aggregated by resolution (one of HistoryResolutions)
+func (c *Client) GetHistoricalStats(group string, from, to time.Time, resolution time.Duration) (GroupStatsData, error) {
+	return c.GetHistoricalStatsContext(context.Background(), group, from, to, resolution)
+}
+
+// GetHistoricalStatsContext is same as GetHistoricalStats but request is bound to ctx
+func (c *Client) GetHistoricalStatsContext(ctx context.Context, group string, from, to time.Time, resolution time.Duration) (GroupStatsData, error) {
+	return c.historicalStats(ctx, group, "", from, to, resolution)
+}
+
+// historicalStats requests historical stats of group limited to one slave (all slaves if slave is empty)
+func (c *Client) historicalStats(ctx context.Context, group string, slave string, from, to time.Time, resolution time.Duration) (GroupStatsData, error) {
+	var data GroupStatsData
+
+	if !from.Before(to) {
+		return data, errors.New("invalid period: from " + from.Format(time.RFC3339) + " is not before to " + to.Format(time.RFC3339))
+	}
+
+	supported := false
+	for _, r := range HistoryResolutions {
+		if r == resolution {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return data, errors.New("unsupported resolution " + resolution.String())
+	}
+
+	if err := c.requireCapability(ctx, capabilityHistoryStats); nil != err {
+		return data, err
+	}
+
+	query := url.Values{
+		"from":       []string{strconv.FormatInt(from.Unix(), 10)},
+		"to":         []string{strconv.FormatInt(to.Unix(), 10)},
+		"resolution": []string{strconv.FormatInt(int64(resolution/time.Second), 10)},
+	}
+	if "" != slave {
+		query.Set("slave", slave)
+	}
+	err := c.GetContext(ctx, c.url+"/v1/historystats/"+url.QueryEscape(group+"->")+"?"+query.Encode(), &data)
+	return data, err
+}
+
+// IPStatsDetail is aggregated latency/loss of one IP on one slave, all RTT values are in ms
+type IPStatsDetail struct {
+	MinRTT      float64 `json:"minRTT"`
+	MaxRTT      float64 `json:"maxRTT"`
+	AvgRTT      float64 `json:"avgRTT"`
+	P50         float64 `json:"p50"`
+	P95         float64 `json:"p95"`
+	P99         float64 `json:"p99"`
+	LossPercent float64 `json:"lossPercent"`
+	SampleCount int     `json:"sampleCount"` // total count of tests including lost ones
+}
+
+// GetIPStats returns latency percentiles and loss of ip measured by slave for period from-to,
+// zero from/to defaults to last 24 hours
+func (c *Client) GetIPStats(ip string, slave string, from, to time.Time) (IPStatsDetail, error) {
+	return c.GetIPStatsContext(context.Background(), ip, slave, from, to)
+}
+
+// GetIPStatsContext is same as GetIPStats but request is bound to ctx
+func (c *Client) GetIPStatsContext(ctx context.Context, ip string, slave string, from, to time.Time) (IPStatsDetail, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-24 * time.Hour)
+	}
+	if !from.Before(to) {
+		return IPStatsDetail{}, errors.New("invalid period: from " + from.Format(time.RFC3339) + " is not before to " + to.Format(time.RFC3339))
+	}
+
+	var data struct {
+		RTT  []float64 `json:"rtt"`  // latencies of successful tests
+		Lost int       `json:"lost"` // count of lost tests
+	}
+	query := url.Values{
+		"ip":    []string{ip},
+		"slave": []string{slave},
+		"from":  []string{strconv.FormatInt(from.Unix(), 10)},
+		"to":    []string{strconv.FormatInt(to.Unix(), 10)},
+	}
+	err := c.GetContext(ctx, c.url+"/v1/stats/ip?"+query.Encode(), &data)
+	if nil != err {
+		return IPStatsDetail{}, err
+	}
+
+	return calcIPStats(data.RTT, data.Lost), nil
+}
+
+// calcIPStats aggregates latencies of successful tests and count of lost ones
+func calcIPStats(rtts []float64, lost int) IPStatsDetail {
+	result := IPStatsDetail{SampleCount: len(rtts) + lost}
+	if 0 == result.SampleCount {
+		return result
+	}
+	result.LossPercent = float64(lost) / float64(result.SampleCount) * 100
+
+	if 0 == len(rtts) {
+		return result
+	}
+
+	sorted := make([]float64, len(rtts))
+	copy(sorted, rtts)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, rtt := range sorted {
+		sum += rtt
+	}
+
+	result.MinRTT = sorted[0]
+	result.MaxRTT = sorted[len(sorted)-1]
+	result.AvgRTT = sum / float64(len(sorted))
+	result.P50 = percentile(sorted, 50)
+	result.P95 = percentile(sorted, 95)
+	result.P99 = percentile(sorted, 99)
+
+	return result
+}
+
+// percentile returns p-th percentile of sorted non-empty list using nearest-rank method
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// IPRanking is one entry of GetTopWorstIPs result
+type IPRanking struct {
+	IP          string
+	Value       float64 // loss in percent or average rtt in ms
+	Group       string
+	Description string
+}
+
+// GetTopWorstIPs returns up to n monitored IPs with highest last-minute "loss" or "rtt" on slave,
+// ties are ordered by IP; stats are collected from all groups so IPs without any group are not included
+func (c *Client) GetTopWorstIPs(slave string, metric string, n int) ([]IPRanking, error) {
+	return c.GetTopWorstIPsContext(context.Background(), slave, metric, n)
+}
+
+// GetTopWorstIPsContext is same as GetTopWorstIPs but requests are bound to ctx
+func (c *Client) GetTopWorstIPsContext(ctx context.Context, slave string, metric string, n int) ([]IPRanking, error) {
+	var value func(*AvgChunk) float64
+	switch metric {
+	case "loss":
+		value = func(a *AvgChunk) float64 { return a.LossRatio() * 100 }
+	case "rtt":
+		value = (*AvgChunk).AvgLatency
+	default:
+		return nil, errors.New("unsupported metric " + metric)
+	}
+
+	config, stats, err := c.slaveLastStats(ctx, slave)
+	if nil != err {
+		return nil, err
+	}
+
+	rankings := map[string]IPRanking{}
+	for ip, s := range stats {
+		if 0 == s.data.Count {
+			continue
+		}
+		rankings[ip] = IPRanking{
+			IP:          ip,
+			Value:       value(s.data),
+			Group:       s.group,
+			Description: config.Ping.IPs[ip].Description,
+		}
+	}
+
+	result := make([]IPRanking, 0, len(rankings))
+	for _, r := range rankings {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value != result[j].Value {
+			return result[i].Value > result[j].Value
+		}
+		return result[i].IP < result[j].IP
+	})
+
+	if n >= 0 && n < len(result) {
+		result = result[:n]
+	}
+	return result, nil
+}
+
+// groupChunk is last-minute stats of IP together with group they were loaded from
+type groupChunk struct {
+	group string
+	data  *AvgChunk
+}
+
+// slaveLastStats loads last-minute stats of IPs on slave from all groups (first group wins if IP is in several ones)
+func (c *Client) slaveLastStats(ctx context.Context, slave string) (ConfigInfo, map[string]groupChunk, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return config, nil, err
+	}
+
+	groups := groupNames(config)
+	loaded := make(map[string]map[string]*AvgChunk, len(groups))
+
+	var mu sync.Mutex
+	errs := MultiError{}
+
+	c.forEach(groups, func(group string) {
+		ips, _, err := c.GroupLastStatsContext(ctx, group, slave)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if nil != err {
+			errs[group] = err
+			return
+		}
+		loaded[group] = ips
+	})
+
+	if 0 != len(errs) {
+		return config, nil, errs
+	}
+
+	stats := map[string]groupChunk{}
+	for _, group := range groups {
+		for ip, data := range loaded[group] {
+			if _, ok := stats[ip]; ok || nil == data {
+				continue
+			}
+			stats[ip] = groupChunk{group: group, data: data}
+		}
+	}
+	return config, stats, nil
+}
+
+// TargetProbeCount is probe traffic of one IP on slave during last minute
+type TargetProbeCount struct {
+	IP           string
+	ProbesPerMin int
+	AvgRTTMs     float64
+}
+
+// GetSlavesTopTargets returns up to n IPs tested by slave with most probes during last minute, ties are ordered by IP
+func (c *Client) GetSlavesTopTargets(slave string, n int) ([]TargetProbeCount, error) {
+	return c.GetSlavesTopTargetsContext(context.Background(), slave, n)
+}
+
+// GetSlavesTopTargetsContext is same as GetSlavesTopTargets but requests are bound to ctx
+func (c *Client) GetSlavesTopTargetsContext(ctx context.Context, slave string, n int) ([]TargetProbeCount, error) {
+	return c.slaveTargets(ctx, slave, n, true)
+}
+
+// GetSlavesBottomTargets returns up to n IPs tested by slave with least probes during last minute (IPs assigned
+// to slave but missing in stats have 0 probes), ties are ordered by IP
+func (c *Client) GetSlavesBottomTargets(slave string, n int) ([]TargetProbeCount, error) {
+	return c.GetSlavesBottomTargetsContext(context.Background(), slave, n)
+}
+
+// GetSlavesBottomTargetsContext is same as GetSlavesBottomTargets but requests are bound to ctx
+func (c *Client) GetSlavesBottomTargetsContext(ctx context.Context, slave string, n int) ([]TargetProbeCount, error) {
+	return c.slaveTargets(ctx, slave, n, false)
+}
+
+// slaveTargets returns n IPs of slave ordered by probe count
+func (c *Client) slaveTargets(ctx context.Context, slave string, n int, top bool) ([]TargetProbeCount, error) {
+	if "" == slave {
+		return nil, ErrEmptySlaveName
+	}
+
+	config, stats, err := c.slaveLastStats(ctx, slave)
+	if nil != err {
+		return nil, err
+	}
+
+	targets := map[string]TargetProbeCount{}
+	for ip, desc := range config.Ping.IPs {
+		for _, s := range desc.Slaves {
+			if s == slave {
+				targets[ip] = TargetProbeCount{IP: ip}
+				break
+			}
+		}
+	}
+	for ip, s := range stats {
+		targets[ip] = TargetProbeCount{
+			IP:           ip,
+			ProbesPerMin: s.data.Count,
+			AvgRTTMs:     s.data.AvgLatency(),
+		}
+	}
+
+	result := make([]TargetProbeCount, 0, len(targets))
+	for _, t := range targets {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ProbesPerMin != result[j].ProbesPerMin {
+			return (result[i].ProbesPerMin > result[j].ProbesPerMin) == top
+		}
+		return result[i].IP < result[j].IP
+	})
+
+	if n >= 0 && n < len(result) {
+		result = result[:n]
+	}
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCalcIPStatsPercentiles(t *testing.T) {
+	// 1..100 ms in reversed order with 25 lost tests
+	rtts := make([]float64, 0, 100)
+	for i := 100; i >= 1; i-- {
+		rtts = append(rtts, float64(i))
+	}
+
+	stats := calcIPStats(rtts, 25)
+
+	expected := IPStatsDetail{
+		MinRTT:      1,
+		MaxRTT:      100,
+		AvgRTT:      50.5,
+		P50:         50,
+		P95:         95,
+		P99:         99,
+		LossPercent: 20,
+		SampleCount: 125,
+	}
+	if expected != stats {
+		t.Fatalf("expected %+v, got %+v", expected, stats)
+	}
+	if 100 != rtts[0] {
+		t.Fatal("input slice was modified")
+	}
+}
+
+func TestCalcIPStatsSmallSamples(t *testing.T) {
+	for _, tc := range []struct {
+		rtts          []float64
+		lost          int
+		p50, p95, p99 float64
+		loss          float64
+	}{
+		{[]float64{7}, 0, 7, 7, 7, 0},
+		{[]float64{30, 10}, 2, 10, 30, 30, 50},
+		{[]float64{12, 10, 11, 40, 13}, 0, 12, 40, 40, 0},
+		{nil, 4, 0, 0, 0, 100},
+		{nil, 0, 0, 0, 0, 0},
+	} {
+		stats := calcIPStats(tc.rtts, tc.lost)
+		if tc.p50 != stats.P50 || tc.p95 != stats.P95 || tc.p99 != stats.P99 || tc.loss != stats.LossPercent {
+			t.Errorf("%v lost %d: unexpected %+v", tc.rtts, tc.lost, stats)
+		}
+	}
+}
+
+func TestGetIPStats(t *testing.T) {
+	from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if "/v1/stats/ip" != r.URL.Path || "1.1.1.1" != q.Get("ip") || "PRAGUE" != q.Get("slave") ||
+			"1577836800" != q.Get("from") || "1577840400" != q.Get("to") {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		writeTestJSON(w, map[string]interface{}{
+			"rtt":  []float64{10.5, 11.5, 9.5, 30, 10},
+			"lost": 1,
+		})
+	})
+
+	stats, err := c.GetIPStats("1.1.1.1", "PRAGUE", from, to)
+	if nil != err {
+		t.Fatal(err)
+	}
+	if 9.5 != stats.MinRTT || 30 != stats.MaxRTT || 10.5 != stats.P50 || 30 != stats.P95 || 6 != stats.SampleCount {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if math.Abs(stats.AvgRTT-14.3) > 1e-9 || math.Abs(stats.LossPercent-100.0/6) > 1e-9 {
+		t.Fatalf("unexpected avg/loss %+v", stats)
+	}
+
+	if _, err := c.GetIPStats("1.1.1.1", "PRAGUE", to, from); nil == err {
+		t.Fatal("expected error for inverted period")
+	}
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+// tagsDelimiter separates description text from JSON encoded tags as server has no native tags support
+const tagsDelimiter = " ##tags:"
+
+// splitDescription separates description text and tags encoded by TagIP
+func splitDescription(description string) (string, map[string]string) {
+	i := strings.LastIndex(description, tagsDelimiter)
+	if i < 0 {
+		return description, map[string]string{}
+	}
+
+	tags := map[string]string{}
+	if err := json.Unmarshal([]byte(description[i+len(tagsDelimiter):]), &tags); nil != err {
+		// not our suffix, leave description as is
+		return description, map[string]string{}
+	}
+	return description[:i], tags
+}
+
+// joinDescription encodes tags into description
+func joinDescription(text string, tags map[string]string) string {
+	if 0 == len(tags) {
+		return text
+	}
+	raw, _ := json.Marshal(tags) // map[string]string is always serializable, keys are sorted
+	return text + tagsDelimiter + string(raw)
+}
+
+// Tags returns tags of test set by TagIP
+func (t TestDesc) Tags() map[string]string {
+	_, tags := splitDescription(t.Description)
+	return tags
+}
+
+// PlainDescription returns description without tags set by TagIP
+func (t TestDesc) PlainDescription() string {
+	text, _ := splitDescription(t.Description)
+	return text
+}
+
+// TagIP replaces tags (arbitrary key-value metadata) of monitored ip, empty map removes all tags.
+// Tags are stored as JSON suffix of description, use TestDesc.Tags and TestDesc.PlainDescription to read them
+func (c *Client) TagIP(ip string, tags map[string]string) error {
+	return c.TagIPContext(context.Background(), ip, tags)
+}
+
+// TagIPContext is same as TagIP but requests are bound to ctx
+func (c *Client) TagIPContext(ctx context.Context, ip string, tags map[string]string) error {
+	detail, err := c.GetIPDetailsContext(ctx, ip)
+	if nil != err {
+		return err
+	}
+
+	desc := detail.TestDesc
+	desc.Description = joinDescription(desc.PlainDescription(), tags)
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/ping/"+ipPath(ip), desc)
+}
+
+// GetIPsByTag returns sorted list of monitored IPs having tag key with value
+func (c *Client) GetIPsByTag(key, value string) ([]string, error) {
+	return c.GetIPsByTagContext(context.Background(), key, value)
+}
+
+// GetIPsByTagContext is same as GetIPsByTag but request is bound to ctx
+func (c *Client) GetIPsByTagContext(ctx context.Context, key, value string) ([]string, error) {
+	config, err := c.GetConfigInfoContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	ips := []string{}
+	for ip, desc := range config.Ping.IPs {
+		if v, ok := desc.Tags()[key]; ok && v == value {
+			ips = append(ips, ip)
+		}
+	}
+	sort.Strings(ips)
+
+	return ips, nil
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io/ioutil"
+)
+
+// SetTLSClientCert loads client certificate and key from PEM files and uses them for all future requests
+func (c *Client) SetTLSClientCert(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if nil != err {
+		return err
+	}
+
+	c.updateTLS(func(cfg *tls.Config) {
+		cfg.Certificates = []tls.Certificate{cert}
+	})
+	return nil
+}
+
+// SetTLSClientCertPEM uses PEM encoded client certificate and key for all future requests
+func (c *Client) SetTLSClientCertPEM(certPEM, keyPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if nil != err {
+		return err
+	}
+
+	c.updateTLS(func(cfg *tls.Config) {
+		cfg.Certificates = []tls.Certificate{cert}
+	})
+	return nil
+}
+
+// SetTLSInsecureSkipVerify disables (or enables back) verification of server certificate
+func (c *Client) SetTLSInsecureSkipVerify(skip bool) {
+	c.updateTLS(func(cfg *tls.Config) {
+		cfg.InsecureSkipVerify = skip
+	})
+}
+
+// SetTLSRootCA makes client trust only CA certificates from PEM file caFile (usable for self-signed server certs)
+func (c *Client) SetTLSRootCA(caFile string) error {
+	raw, err := ioutil.ReadFile(caFile)
+	if nil != err {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(raw) {
+		return errors.New("no certificates found in " + caFile)
+	}
+
+	c.updateTLS(func(cfg *tls.Config) {
+		cfg.RootCAs = pool
+	})
+	return nil
+}
+
+// updateTLS applies change to copy of current TLS config and switches client to fresh transport using it
+func (c *Client) updateTLS(change func(cfg *tls.Config)) {
+	c.transportMu.Lock()
+	defer c.transportMu.Unlock()
+
+	cfg := &tls.Config{}
+	if nil != c.tlsConfig {
+		cfg = c.tlsConfig.Clone()
+	}
+	change(cfg)
+	c.tlsConfig = cfg
+
+	c.setTransport()
+}
@@ -0,0 +1,300 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TracerouteHop is one hop of traceroute
+type TracerouteHop struct {
+	HopNumber int     `json:"hop"`
+	IP        string  `json:"ip"`   // empty if hop didn't answer
+	RTT       float64 `json:"rtt"`  // ms
+	Loss      float64 `json:"loss"` // percent
+}
+
+// TracerouteResult is state of traceroute job
+type TracerouteResult struct {
+	IP    string          `json:"ip"`
+	Slave string          `json:"slave"`
+	Done  bool            `json:"done"`
+	Hops  []TracerouteHop `json:"hops"`
+}
+
+// TriggerTraceroute starts traceroute to ip from slave and returns id of job
+func (c *Client) TriggerTraceroute(ip string, slave string) (string, error) {
+	return c.TriggerTracerouteContext(context.Background(), ip, slave)
+}
+
+// TriggerTracerouteContext is same as TriggerTraceroute but request is bound to ctx
+func (c *Client) TriggerTracerouteContext(ctx context.Context, ip string, slave string) (string, error) {
+	var r struct {
+		result
+		ID string `json:"id"`
+	}
+
+	err := c.SendContext(ctx, "POST", c.url+"/v1/traceroute", map[string]interface{}{
+		"ip":    ip,
+		"slave": slave,
+	}, &r)
+	if nil != err {
+		return "", err
+	}
+
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return "", errors.New(r.Error)
+		}
+		return "", errors.New("unknown error")
+	}
+
+	return r.ID, nil
+}
+
+// GetTracerouteResult returns current state of traceroute job
+func (c *Client) GetTracerouteResult(jobID string) (TracerouteResult, error) {
+	return c.GetTracerouteResultContext(context.Background(), jobID)
+}
+
+// GetTracerouteResultContext is same as GetTracerouteResult but request is bound to ctx
+func (c *Client) GetTracerouteResultContext(ctx context.Context, jobID string) (TracerouteResult, error) {
+	var result TracerouteResult
+	err := c.GetContext(ctx, c.url+"/v1/traceroute/"+url.PathEscape(jobID), &result)
+	return result, err
+}
+
+// WaitForTraceroute polls traceroute job every poll interval until it's done or ctx is done
+func (c *Client) WaitForTraceroute(ctx context.Context, jobID string, poll time.Duration) (TracerouteResult, error) {
+	for {
+		result, err := c.GetTracerouteResultContext(ctx, jobID)
+		if nil != err || result.Done {
+			return result, err
+		}
+
+		if err := sleepContext(ctx, poll); nil != err {
+			return result, err
+		}
+	}
+}
+
+// PathHop is one hop of network path between two slaves
+type PathHop struct {
+	TTL      int     `json:"ttl"`
+	IP       string  `json:"ip"` // empty if hop didn't answer
+	Hostname string  `json:"hostname"`
+	AvgRTTMs float64 `json:"avgRTT"`
+}
+
+// PathPollInterval is delay between result requests of GetNetworkPath
+var PathPollInterval = 2 * time.Second
+
+// GetNetworkPath runs traceroute from srcSlave to dstSlave and waits for its result
+func (c *Client) GetNetworkPath(srcSlave, dstSlave string) ([]PathHop, error) {
+	return c.GetNetworkPathContext(context.Background(), srcSlave, dstSlave)
+}
+
+// GetNetworkPathContext is same as GetNetworkPath but requests are bound to ctx
+func (c *Client) GetNetworkPathContext(ctx context.Context, srcSlave, dstSlave string) ([]PathHop, error) {
+	if "" == srcSlave || "" == dstSlave {
+		return nil, ErrEmptySlaveName
+	}
+
+	var r struct {
+		result
+		ID string `json:"id"`
+	}
+	err := c.SendContext(ctx, "POST", c.url+"/v1/path", map[string]interface{}{
+		"src": srcSlave,
+		"dst": dstSlave,
+	}, &r)
+	if nil != err {
+		return nil, err
+	}
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return nil, errors.New(r.Error)
+		}
+		return nil, errors.New("unknown error")
+	}
+
+	for {
+		var state struct {
+			Done bool      `json:"done"`
+			Hops []PathHop `json:"hops"`
+		}
+		if err := c.GetContext(ctx, c.url+"/v1/path/"+url.PathEscape(r.ID), &state); nil != err {
+			return nil, err
+		}
+		if state.Done {
+			return state.Hops, nil
+		}
+
+		if err := sleepContext(ctx, PathPollInterval); nil != err {
+			return nil, err
+		}
+	}
+}
+
+// PathDiff describes routing change between two network path snapshots
+type PathDiff struct {
+	A, B         []PathHop
+	Changed      bool
+	FirstChanged int      // TTL of first hop with different IP, 0 if paths are same
+	Added        []string // IPs present only in B
+	Removed      []string // IPs present only in A
+}
+
+// CompareNetworkPaths compares network paths from src to dst slave recorded nearest to moments a and b
+func (c *Client) CompareNetworkPaths(src, dst string, a, b time.Time) (PathDiff, error) {
+	return c.CompareNetworkPathsContext(context.Background(), src, dst, a, b)
+}
+
+// CompareNetworkPathsContext is same as CompareNetworkPaths but requests are bound to ctx
+func (c *Client) CompareNetworkPathsContext(ctx context.Context, src, dst string, a, b time.Time) (PathDiff, error) {
+	if "" == src || "" == dst {
+		return PathDiff{}, ErrEmptySlaveName
+	}
+
+	hopsA, err := c.networkPathAt(ctx, src, dst, a)
+	if nil != err {
+		return PathDiff{}, err
+	}
+	hopsB, err := c.networkPathAt(ctx, src, dst, b)
+	if nil != err {
+		return PathDiff{}, err
+	}
+
+	return diffPaths(hopsA, hopsB), nil
+}
+
+// networkPathAt returns path snapshot recorded nearest to moment at
+func (c *Client) networkPathAt(ctx context.Context, src, dst string, at time.Time) ([]PathHop, error) {
+	query := url.Values{
+		"src": []string{src},
+		"dst": []string{dst},
+		"at":  []string{strconv.FormatInt(at.Unix(), 10)},
+	}
+	var hops []PathHop
+	err := c.GetContext(ctx, c.url+"/v1/path/history?"+query.Encode(), &hops)
+	return hops, err
+}
+
+// diffPaths compares hops by TTL, hops without answer are ignored
+func diffPaths(a, b []PathHop) PathDiff {
+	diff := PathDiff{A: a, B: b, Added: []string{}, Removed: []string{}}
+
+	byTTL := map[int]string{}
+	inA := map[string]bool{}
+	for _, hop := range a {
+		byTTL[hop.TTL] = hop.IP
+		if "" != hop.IP {
+			inA[hop.IP] = true
+		}
+	}
+	inB := map[string]bool{}
+	for _, hop := range b {
+		if "" != hop.IP {
+			inB[hop.IP] = true
+		}
+		if "" == hop.IP || "" == byTTL[hop.TTL] || hop.IP == byTTL[hop.TTL] {
+			continue
+		}
+		if 0 == diff.FirstChanged || hop.TTL < diff.FirstChanged {
+			diff.FirstChanged = hop.TTL
+		}
+	}
+
+	for _, hop := range b {
+		if "" != hop.IP && !inA[hop.IP] {
+			diff.Added = appendUnique(diff.Added, hop.IP)
+		}
+	}
+	for _, hop := range a {
+		if "" != hop.IP && !inB[hop.IP] {
+			diff.Removed = appendUnique(diff.Removed, hop.IP)
+		}
+	}
+
+	diff.Changed = 0 != diff.FirstChanged || 0 != len(diff.Added) || 0 != len(diff.Removed)
+	return diff
+}
+
+// ProbeResult is result of on-demand probe started by ProbeNow
+type ProbeResult struct {
+	RTT       time.Duration // 0 if all packets were lost
+	Loss      float64       // percent
+	Timestamp time.Time
+	RawOutput string
+}
+
+// ProbeNowTimeout limits time ProbeNow waits for result, ProbeNowPollInterval is delay between result requests
+var (
+	ProbeNowTimeout      = 30 * time.Second
+	ProbeNowPollInterval = time.Second
+)
+
+// ProbeNow makes slave probe ip immediately (outside of regular schedule) and waits for result,
+// ErrProbeTimeout is returned if result doesn't arrive within ProbeNowTimeout
+func (c *Client) ProbeNow(ip string, slave string) (ProbeResult, error) {
+	return c.ProbeNowContext(context.Background(), ip, slave)
+}
+
+// ProbeNowContext is same as ProbeNow but requests are bound to ctx
+func (c *Client) ProbeNowContext(ctx context.Context, ip string, slave string) (ProbeResult, error) {
+	if "" == slave {
+		return ProbeResult{}, ErrEmptySlaveName
+	}
+
+	var r struct {
+		result
+		ID string `json:"id"`
+	}
+	err := c.SendContext(ctx, "POST", c.url+"/v1/probe/now", map[string]interface{}{
+		"ip":    normalizeIP(ip),
+		"slave": slave,
+	}, &r)
+	if nil != err {
+		return ProbeResult{}, err
+	}
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return ProbeResult{}, errors.New(r.Error)
+		}
+		return ProbeResult{}, errors.New("unknown error")
+	}
+
+	pollCtx, cancel := context.WithTimeout(ctx, ProbeNowTimeout)
+	defer cancel()
+
+	for {
+		var state struct {
+			Done      bool      `json:"done"`
+			RTT       float64   `json:"rtt"` // ms
+			Loss      float64   `json:"loss"`
+			Timestamp time.Time `json:"timestamp"`
+			Output    string    `json:"output"`
+		}
+		err := c.GetContext(pollCtx, c.url+"/v1/probe/now/"+url.PathEscape(r.ID), &state)
+		if nil == err && state.Done {
+			return ProbeResult{
+				RTT:       time.Duration(state.RTT * float64(time.Millisecond)),
+				Loss:      state.Loss,
+				Timestamp: state.Timestamp,
+				RawOutput: state.Output,
+			}, nil
+		}
+
+		if nil == err {
+			err = sleepContext(pollCtx, ProbeNowPollInterval)
+		}
+		if nil != err {
+			if nil == ctx.Err() && context.DeadlineExceeded == pollCtx.Err() {
+				return ProbeResult{}, ErrProbeTimeout
+			}
+			return ProbeResult{}, err
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// TransportOptions tunes connection reuse of client, see NewTransportOptions
+type TransportOptions struct {
+	MaxIdleConns          int           // idle connections kept open, client talks to one master so it's also the per-host limit
+	IdleConnTimeout       time.Duration // how long idle connection is kept open
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration // 0 means no limit
+}
+
+// NewTransportOptions returns options with values of http.DefaultTransport (but per-host limit raised to MaxIdleConns)
+func NewTransportOptions() *TransportOptions {
+	return &TransportOptions{
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: 10 * time.Second,
+	}
+}
+
+// Apply switches client to fresh transport built with options (TLS settings of client are kept)
+func (o *TransportOptions) Apply(c *Client) {
+	c.transportMu.Lock()
+	defer c.transportMu.Unlock()
+
+	options := *o
+	c.transportOptions = &options
+	c.setTransport()
+}
+
+// WithTransportOptions is same as o.Apply usable with New and Configure
+func WithTransportOptions(o *TransportOptions) Option {
+	return func(c *Client) {
+		o.Apply(c)
+	}
+}
+
+// setTransport builds transport from TLS config and transport options and assigns it to copy of http client,
+// transportMu has to be locked by caller
+func (c *Client) setTransport() {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = c.tlsConfig
+
+	if o := c.transportOptions; nil != o {
+		transport.MaxIdleConns = o.MaxIdleConns
+		transport.MaxIdleConnsPerHost = o.MaxIdleConns
+		transport.IdleConnTimeout = o.IdleConnTimeout
+		transport.TLSHandshakeTimeout = o.TLSHandshakeTimeout
+		transport.ResponseHeaderTimeout = o.ResponseHeaderTimeout
+	}
+
+	hc := *c.httpClient
+	hc.Transport = transport
+	c.httpClient = &hc
+	c.wrapClient()
+}
+
+// SetTransportWrapper makes client send all requests through transport returned by wrap for its current transport
+// (used for instrumentation like tracing), wrapper is re-applied after transport changes; nil removes wrapper
+func (c *Client) SetTransportWrapper(wrap func(http.RoundTripper) http.RoundTripper) {
+	c.transportMu.Lock()
+	defer c.transportMu.Unlock()
+
+	c.transportWrapper = wrap
+	c.wrapClient()
+}
+
+// wrapClient builds copy of http client with transport wrapped by transport wrapper, transportMu has to be locked by caller
+func (c *Client) wrapClient() {
+	if nil == c.transportWrapper {
+		c.wrappedClient = nil
+		return
+	}
+
+	hc := *c.httpClient
+	base := hc.Transport
+	if nil == base {
+		base = http.DefaultTransport
+	}
+	hc.Transport = c.transportWrapper(base)
+	c.wrappedClient = &hc
+}
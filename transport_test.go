@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newConnCountingServer starts server answering empty slave list and counting accepted connections
+func newConnCountingServer(t testing.TB) (*httptest.Server, *int32) {
+	var conns int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeTestJSON(w, map[string]string{})
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if http.StateNew == state {
+			atomic.AddInt32(&conns, 1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+
+	return server, &conns
+}
+
+func TestTransportOptionsApply(t *testing.T) {
+	server, conns := newConnCountingServer(t)
+	c := New(server.URL, "admin", "secret")
+
+	o := NewTransportOptions()
+	o.MaxIdleConns = 5
+	o.IdleConnTimeout = time.Minute
+	o.ResponseHeaderTimeout = 3 * time.Second
+	o.Apply(c)
+
+	transport, ok := c.httpClient.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport %T", c.httpClient.Transport)
+	}
+	if 5 != transport.MaxIdleConns || 5 != transport.MaxIdleConnsPerHost || time.Minute != transport.IdleConnTimeout ||
+		10*time.Second != transport.TLSHandshakeTimeout || 3*time.Second != transport.ResponseHeaderTimeout {
+		t.Fatalf("options not applied: %+v", transport)
+	}
+
+	// later changes of options don't affect client
+	o.MaxIdleConns = 1
+	if 5 != c.transportOptions.MaxIdleConns {
+		t.Fatal("client shares options with caller")
+	}
+
+	for i := 0; i < 50; i++ {
+		if _, err := c.GetSlavesIPs(); nil != err {
+			t.Fatal(err)
+		}
+	}
+	if n := atomic.LoadInt32(conns); 1 != n {
+		t.Fatalf("expected one reused connection for sequential calls, got %d", n)
+	}
+}
+
+func benchmarkSequentialCalls(b *testing.B, opt Option) {
+	server, conns := newConnCountingServer(b)
+	c := New(server.URL, "admin", "secret", opt)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1000; j++ {
+			if _, err := c.GetSlavesIPs(); nil != err {
+				b.Fatal(err)
+			}
+		}
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(atomic.LoadInt32(conns))/float64(b.N), "conns/op")
+}
+
+func BenchmarkConnectionReuse(b *testing.B) {
+	benchmarkSequentialCalls(b, WithTransportOptions(NewTransportOptions()))
+}
+
+func BenchmarkFreshConnections(b *testing.B) {
+	benchmarkSequentialCalls(b, WithHTTPClient(&http.Client{Transport: &http.Transport{DisableKeepAlives: true}}))
+}
@@ -1,6 +1,10 @@
 package api
 
-import "time"
+import (
+	"net"
+	"strings"
+	"time"
+)
 
 type result struct {
 	Result string `json:"result"`
@@ -15,18 +19,90 @@ type TestDesc struct {
 	Slaves      []string  `json:"slaves"`
 	AutoAdded   time.Time `json:"auto-added,omitempty"`
 	AS          int64     `json:"as"`
+
+	// probe customisation, zero value means slave default
+	PacketCount      int `json:"packetCount,omitempty"`   // count of ICMP packets per test
+	PacketSizeBytes  int `json:"packetSize,omitempty"`    // size of ICMP packet
+	ProbeIntervalSec int `json:"probeInterval,omitempty"` // seconds between tests
+}
+
+// TestDescPatch describes partial change of TestDesc, nil fields are left untouched
+type TestDescPatch struct {
+	Groups      *[]string
+	Description *string
+	Favorite    *bool
+	Slaves      *[]string
+	AS          *int64
+
+	PacketCount      *int
+	PacketSizeBytes  *int
+	ProbeIntervalSec *int
+}
+
+// SlavePatch describes partial change of slave, nil fields are left untouched (see UpdateSlave)
+type SlavePatch struct {
+	NewName *string
+	NewIP   *net.IP
+	NewPort *uint16
+}
+
+// Apply returns copy of desc with patch applied
+func (p TestDescPatch) Apply(desc TestDesc) TestDesc {
+	if nil != p.Groups {
+		desc.Groups = *p.Groups
+	}
+	if nil != p.Description {
+		desc.Description = *p.Description
+	}
+	if nil != p.Favorite {
+		desc.Favorite = *p.Favorite
+	}
+	if nil != p.Slaves {
+		desc.Slaves = *p.Slaves
+	}
+	if nil != p.AS {
+		desc.AS = *p.AS
+	}
+	if nil != p.PacketCount {
+		desc.PacketCount = *p.PacketCount
+	}
+	if nil != p.PacketSizeBytes {
+		desc.PacketSizeBytes = *p.PacketSizeBytes
+	}
+	if nil != p.ProbeIntervalSec {
+		desc.ProbeIntervalSec = *p.ProbeIntervalSec
+	}
+	return desc
+}
+
+// IPDetail is full configuration of one monitored IP with its current status
+type IPDetail struct {
+	TestDesc
+	LastRTT   float32   `json:"lastRTT"`   // latency of last check in ms
+	LastLoss  float32   `json:"lastLoss"`  // loss of last check in percent
+	LastCheck time.Time `json:"lastCheck"` // time of last check
 }
 
 // GroupConfig == settings for group :)
 type GroupConfig struct {
-	IsPublic         bool            `json:"isPublic"`
-	LossTreshold     float32         `json:"lossTreshold"`
-	LatencyThreshold float32         `json:"latencyTreshold"`
-	PushNotify       map[string]bool `json:"pushNotify"`
-	IsAutoGroup      bool            `json:"isAutoGroup"`
-	AGNetwork        string          `json:"agNetwork"`
-	AGCount          int             `json:"agCount"`
-	AGSlaves         []string        `json:"agSlaves"`
+	IsPublic         bool                       `json:"isPublic"`
+	LossTreshold     float32                    `json:"lossThreshold"`
+	LatencyThreshold float32                    `json:"latencyThreshold"`
+	TimeThreshold    int                        `json:"timeThreshold"`
+	PushNotify       map[string]bool            `json:"pushNotify"`  // Deprecated: use PushNotifyA
+	PushNotifyA      []string                   `json:"pushNotifyA"` // names of push notify configurations (see /v1/notify)
+	IsAutoGroup      bool                       `json:"isAutoGroup"`
+	AGNetwork        string                     `json:"agNetwork"`
+	AGCount          int                        `json:"agCount"`
+	AGSlaves         []string                   `json:"agSlaves"`
+	SlavesThresholds map[string]GroupThresholds `json:"slavesThresholds,omitempty"` // per-slave overrides
+}
+
+// GroupThresholds are push notification thresholds of group overridden for one slave
+type GroupThresholds struct {
+	LossThreshold    float32 `json:"lossThreshold"`
+	LatencyThreshold float32 `json:"latencyThreshold"`
+	TimeThreshold    int     `json:"timeThreshold"`
 }
 
 // ConfigInfo type represent information about current configuration of tests
@@ -55,6 +131,22 @@ type AvgChunk struct {
 	Latency float32 `json:"latency"` // total SUM of latency
 }
 
+// AvgLatency returns average latency in ms (0 if there are no tests)
+func (a *AvgChunk) AvgLatency() float64 {
+	if 0 == a.Count {
+		return 0
+	}
+	return float64(a.Latency) / float64(a.Count)
+}
+
+// LossRatio returns part of lost tests in range 0..1 (0 if there are no tests)
+func (a *AvgChunk) LossRatio() float64 {
+	if 0 == a.Count {
+		return 0
+	}
+	return float64(a.Loss) / float64(a.Count)
+}
+
 // GroupStatsData is result of GroupStats api call
 type GroupStatsData struct {
 	Ping map[string]map[int64]*AvgChunk
@@ -70,3 +162,44 @@ type SlaveStatus struct {
 	Source6 string    `json:"source6"`
 	Last    time.Time `json:"last"`
 }
+
+// Online reports if slave status means that slave is connected to master
+func (s SlaveStatus) Online() bool {
+	return strings.EqualFold(s.Status, "online") || strings.EqualFold(s.Status, "ok")
+}
+
+// SlaveStatusPoint is status of slave at one moment, see GetSlavesStatusHistory
+type SlaveStatusPoint struct {
+	Timestamp time.Time   `json:"timestamp"`
+	Status    SlaveStatus `json:"status"`
+}
+
+// SlaveGeoInfo is geographic location of slave
+type SlaveGeoInfo struct {
+	Country   string  `json:"country"` // ISO 3166-1 alpha-2 code
+	City      string  `json:"city"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// LossPoint is aggregated packet loss of slave at one moment, see GetSlavesPacketLossTimeSeries
+type LossPoint struct {
+	Timestamp   time.Time `json:"timestamp"`
+	LossPercent float64   `json:"lossPercent"`
+}
+
+// LoadPoint is probe scheduling load of slave at one moment, see GetSlavesLoadTimeSeries
+type LoadPoint struct {
+	Timestamp       time.Time `json:"timestamp"`
+	QueueDepth      int       `json:"queueDepth"`
+	ScheduledProbes int       `json:"scheduledProbes"`
+	ExecutedProbes  int       `json:"executedProbes"`
+}
+
+// BacklogPeriod is time window when slave executed less probes than scheduled, see DetectQueueBacklog
+type BacklogPeriod struct {
+	Start      time.Time
+	End        time.Time // timestamp of first point after backlog (or of last backlog point if there is none)
+	WorstRatio float64   // lowest ExecutedProbes/ScheduledProbes during period
+	MaxQueue   int       // highest QueueDepth during period
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+// URLOptions describes http-specific params of URL test
+type URLOptions struct {
+	ExpectedStatuses []int             `json:"expectedStatuses,omitempty"` // statuses treated as success, empty means 200 only
+	FollowRedirects  bool              `json:"followRedirects"`
+	Headers          map[string]string `json:"headers,omitempty"`
+	ClientCertPath   string            `json:"clientCert,omitempty"` // path to client certificate on slaves
+}
+
+// urlTestDesc is payload of URL test add request
+type urlTestDesc struct {
+	TestDesc
+	URLOptions
+}
+
+// validateURL checks that rawURL can be used as http test
+func validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if nil != err {
+		return err
+	}
+	if ("http" != u.Scheme && "https" != u.Scheme) || "" == u.Host {
+		return errors.New("not http(s) url: " + rawURL)
+	}
+	return nil
+}
+
+// AddURL is simple interface for single URL (http test) adding
+func (c *Client) AddURL(rawURL string, slaves []string, description string, groups []string, favorite bool, options URLOptions) error {
+	return c.AddURLContext(context.Background(), rawURL, slaves, description, groups, favorite, options)
+}
+
+// AddURLContext is same as AddURL but request is bound to ctx
+func (c *Client) AddURLContext(ctx context.Context, rawURL string, slaves []string, description string, groups []string, favorite bool, options URLOptions) error {
+	if err := validateURL(rawURL); nil != err {
+		return err
+	}
+
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/http/"+url.PathEscape(rawURL), urlTestDesc{
+		TestDesc: TestDesc{
+			Description: rawURL + " " + description,
+			Favorite:    favorite,
+			Groups:      groups,
+			Slaves:      slaves,
+		},
+		URLOptions: options,
+	})
+}
+
+// AddURLRaw is extended function adds (or replaces) one URL with full test description
+func (c *Client) AddURLRaw(rawURL string, desc TestDesc) error {
+	return c.AddURLRawContext(context.Background(), rawURL, desc)
+}
+
+// AddURLRawContext is same as AddURLRaw but request is bound to ctx
+func (c *Client) AddURLRawContext(ctx context.Context, rawURL string, desc TestDesc) error {
+	if err := validateURL(rawURL); nil != err {
+		return err
+	}
+
+	return c._okResultSend(ctx, "PUT", c.url+"/v1/config/http/"+url.PathEscape(rawURL), desc)
+}
+
+// DeleteURL removes one URL (http test) from cocopacket instance
+func (c *Client) DeleteURL(rawURL string) error {
+	return c.DeleteURLContext(context.Background(), rawURL)
+}
+
+// DeleteURLContext is same as DeleteURL but request is bound to ctx
+func (c *Client) DeleteURLContext(ctx context.Context, rawURL string) error {
+	if err := validateURL(rawURL); nil != err {
+		return err
+	}
+
+	return c._okResultSend(ctx, "DELETE", c.url+"/v1/config/http/"+url.PathEscape(rawURL), nil)
+}
+
+// AddURLs adds (or replaces) several URLs (http tests) using only one API call, all URLs are validated before sending;
+// invalid URLs or URLs rejected by server are reported by returned MultiError
+func (c *Client) AddURLs(urls map[string]TestDesc) error {
+	return c.AddURLsContext(context.Background(), urls)
+}
+
+// AddURLsContext is same as AddURLs but request is bound to ctx
+func (c *Client) AddURLsContext(ctx context.Context, urls map[string]TestDesc) error {
+	errs := MultiError{}
+	for rawURL := range urls {
+		if err := validateURL(rawURL); nil != err {
+			errs[rawURL] = err
+		}
+	}
+	if 0 != len(errs) {
+		return errs
+	}
+
+	// "urls" key makes server add http tests instead of ping ones
+	var r struct {
+		result
+		Errors map[string]string `json:"errors"` // per-URL errors in case of partial success
+	}
+	err := c.SendContext(ctx, "PUT", c.url+"/v1/mconfig/add", map[string]interface{}{
+		"urls": urls,
+	}, &r)
+	if nil != err {
+		return err
+	}
+
+	for rawURL, msg := range r.Errors {
+		errs[rawURL] = errors.New(msg)
+	}
+	if 0 != len(errs) {
+		return errs
+	}
+
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return errors.New(r.Error)
+		}
+		return errors.New("unknown error")
+	}
+
+	return nil
+}
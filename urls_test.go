@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// urlTestServer records targets of /v1/config/http/ requests
+type urlTestServer struct {
+	methods []string
+	targets []string
+	bodies  []urlTestDesc
+}
+
+func (s *urlTestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.URL.Path, "/v1/config/http/") || "" != r.URL.RawQuery {
+		http.NotFound(w, r)
+		return
+	}
+
+	var body urlTestDesc
+	if "PUT" == r.Method {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+
+	s.methods = append(s.methods, r.Method)
+	s.targets = append(s.targets, strings.TrimPrefix(r.URL.Path, "/v1/config/http/"))
+	s.bodies = append(s.bodies, body)
+	writeTestJSON(w, result{Result: "OK"})
+}
+
+var testURLs = []string{
+	"http://example.com/",
+	"https://example.com/status",
+	"https://example.com/search?q=cocopacket&lang=en",
+	"http://example.com:8080/health?full=1",
+}
+
+func TestAddURL(t *testing.T) {
+	s := &urlTestServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	options := URLOptions{ExpectedStatuses: []int{200, 204}, FollowRedirects: true, Headers: map[string]string{"Host": "example.com"}}
+	for _, rawURL := range testURLs {
+		if err := c.AddURL(rawURL, []string{"PRAGUE"}, "web", []string{"WEB->"}, true, options); nil != err {
+			t.Fatalf("%s: %v", rawURL, err)
+		}
+	}
+
+	if len(testURLs) != len(s.targets) {
+		t.Fatalf("unexpected requests %v", s.targets)
+	}
+	for i, rawURL := range testURLs {
+		if "PUT" != s.methods[i] || rawURL != s.targets[i] {
+			t.Errorf("expected PUT %s, got %s %s", rawURL, s.methods[i], s.targets[i])
+		}
+		body := s.bodies[i]
+		if rawURL+" web" != body.Description || !body.Favorite || !body.FollowRedirects || 2 != len(body.ExpectedStatuses) || "example.com" != body.Headers["Host"] {
+			t.Errorf("%s: unexpected payload %+v", rawURL, body)
+		}
+	}
+}
+
+func TestDeleteURL(t *testing.T) {
+	s := &urlTestServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for _, rawURL := range testURLs {
+		if err := c.DeleteURL(rawURL); nil != err {
+			t.Fatalf("%s: %v", rawURL, err)
+		}
+	}
+
+	for i, rawURL := range testURLs {
+		if "DELETE" != s.methods[i] || rawURL != s.targets[i] {
+			t.Errorf("expected DELETE %s, got %s %s", rawURL, s.methods[i], s.targets[i])
+		}
+	}
+}
+
+func TestURLValidation(t *testing.T) {
+	s := &urlTestServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	for _, rawURL := range []string{"", "example.com", "ftp://example.com/", "https://", "1.1.1.1", "http://[::1"} {
+		if err := c.AddURL(rawURL, nil, "", nil, false, URLOptions{}); nil == err {
+			t.Errorf("AddURL(%q) should fail", rawURL)
+		}
+		if err := c.DeleteURL(rawURL); nil == err {
+			t.Errorf("DeleteURL(%q) should fail", rawURL)
+		}
+	}
+	if 0 != len(s.targets) {
+		t.Fatalf("invalid URLs were sent to server: %v", s.targets)
+	}
+}
+
+// bulkURLServer answers PUT /v1/mconfig/add rejecting URLs listed in reject
+type bulkURLServer struct {
+	reject   map[string]string
+	payloads []map[string]json.RawMessage
+}
+
+func (s *bulkURLServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if "/v1/mconfig/add" != r.URL.Path || "PUT" != r.Method {
+		http.NotFound(w, r)
+		return
+	}
+
+	var payload map[string]json.RawMessage
+	json.NewDecoder(r.Body).Decode(&payload)
+	s.payloads = append(s.payloads, payload)
+
+	if 0 != len(s.reject) {
+		writeTestJSON(w, map[string]interface{}{"result": "error", "error": "partial failure", "errors": s.reject})
+		return
+	}
+	writeTestJSON(w, result{Result: "OK"})
+}
+
+var bulkTestURLs = []string{
+	"https://example.com/api/v1/status",
+	"https://example.com/search?q=coco+packet&lang=en&page=2",
+	"https://example.com/docs/index.html#installation",
+	"https://user@example.com:8443/a/b/c/?x=1&y=%20z#frag?not=query",
+	"http://example.com/path%2Fwith%2Fescapes;params",
+}
+
+func TestAddURLs(t *testing.T) {
+	s := &bulkURLServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	urls := map[string]TestDesc{}
+	for _, rawURL := range bulkTestURLs {
+		urls[rawURL] = TestDesc{Description: rawURL + " web", Slaves: []string{"PRAGUE"}, Groups: []string{"WEB->"}}
+	}
+	if err := c.AddURLs(urls); nil != err {
+		t.Fatal(err)
+	}
+
+	if 1 != len(s.payloads) {
+		t.Fatalf("expected one request, got %d", len(s.payloads))
+	}
+	if _, ok := s.payloads[0]["ips"]; ok {
+		t.Error("URLs must not be sent as ping targets")
+	}
+	var sent map[string]TestDesc
+	if err := json.Unmarshal(s.payloads[0]["urls"], &sent); nil != err {
+		t.Fatal(err)
+	}
+	if len(urls) != len(sent) {
+		t.Fatalf("expected %d URLs, got %v", len(urls), sent)
+	}
+	for rawURL, desc := range urls {
+		if got, ok := sent[rawURL]; !ok || desc.Description != got.Description {
+			t.Errorf("%s: sent unchanged expected, got %+v (present %v)", rawURL, got, ok)
+		}
+	}
+}
+
+func TestAddURLsPartialFailure(t *testing.T) {
+	s := &bulkURLServer{reject: map[string]string{
+		bulkTestURLs[1]: "duplicate",
+		bulkTestURLs[2]: "unsupported fragment",
+	}}
+	c := newTestClient(t, s.ServeHTTP)
+
+	urls := map[string]TestDesc{}
+	for _, rawURL := range bulkTestURLs {
+		urls[rawURL] = TestDesc{}
+	}
+
+	err := c.AddURLs(urls)
+	multi, ok := err.(MultiError)
+	if !ok || 2 != len(multi) {
+		t.Fatalf("expected MultiError with 2 URLs, got %v", err)
+	}
+	for rawURL, msg := range s.reject {
+		if nil == multi[rawURL] || msg != multi[rawURL].Error() {
+			t.Errorf("%s: expected error %q, got %v", rawURL, msg, multi[rawURL])
+		}
+	}
+}
+
+func TestAddURLsValidation(t *testing.T) {
+	s := &bulkURLServer{}
+	c := newTestClient(t, s.ServeHTTP)
+
+	urls := map[string]TestDesc{}
+	for _, rawURL := range bulkTestURLs {
+		urls[rawURL] = TestDesc{}
+	}
+	urls["example.com/path?q=1#top"] = TestDesc{}
+	urls["ftp://example.com/file#part"] = TestDesc{}
+
+	err := c.AddURLs(urls)
+	multi, ok := err.(MultiError)
+	if !ok || 2 != len(multi) || nil == multi["example.com/path?q=1#top"] || nil == multi["ftp://example.com/file#part"] {
+		t.Fatalf("expected MultiError for invalid URLs only, got %v", err)
+	}
+	if 0 != len(s.payloads) {
+		t.Fatalf("nothing should be sent when any URL is invalid, got %v", s.payloads)
+	}
+}
@@ -0,0 +1,223 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ChangeUserPassword sets new password of existing user keeping its admin status, ErrUserNotFound if there is no such user
+func (c *Client) ChangeUserPassword(login string, newPassword string) error {
+	return c.ChangeUserPasswordContext(context.Background(), login, newPassword)
+}
+
+// ChangeUserPasswordContext is same as ChangeUserPassword but requests are bound to ctx
+func (c *Client) ChangeUserPasswordContext(ctx context.Context, login string, newPassword string) error {
+	users, err := c.ListUsersContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	admin, ok := users[login]
+	if !ok {
+		return ErrUserNotFound
+	}
+
+	return c.updateUser(ctx, login, newPassword, admin)
+}
+
+// ChangeUserRole sets admin status of existing user (RoleAdmin or RoleOperator), ErrUserNotFound if there is no such user;
+// servers without role support replace whole user record including password on update, so there status can't be
+// changed without password and ErrPasswordRequired is returned (use ChangeUserRoleWithPassword)
+func (c *Client) ChangeUserRole(login string, admin bool) error {
+	return c.ChangeUserRoleContext(context.Background(), login, admin)
+}
+
+// ChangeUserRoleContext is same as ChangeUserRole but request is bound to ctx
+func (c *Client) ChangeUserRoleContext(ctx context.Context, login string, admin bool) error {
+	if admin {
+		return c.SetUserRoleContext(ctx, login, RoleAdmin)
+	}
+	return c.SetUserRoleContext(ctx, login, RoleOperator)
+}
+
+// checkUserAdmin is used on servers without role support, it checks that user already has requested admin status
+func (c *Client) checkUserAdmin(ctx context.Context, login string, admin bool) error {
+	users, err := c.ListUsersContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	current, ok := users[login]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if current != admin {
+		return ErrPasswordRequired
+	}
+
+	return nil
+}
+
+// ChangeUserRoleWithPassword sets admin status of existing user re-sending its password, ErrUserNotFound if there is no such user
+func (c *Client) ChangeUserRoleWithPassword(login string, admin bool, password string) error {
+	return c.ChangeUserRoleWithPasswordContext(context.Background(), login, admin, password)
+}
+
+// ChangeUserRoleWithPasswordContext is same as ChangeUserRoleWithPassword but requests are bound to ctx
+func (c *Client) ChangeUserRoleWithPasswordContext(ctx context.Context, login string, admin bool, password string) error {
+	if "" == password {
+		return ErrPasswordRequired
+	}
+
+	users, err := c.ListUsersContext(ctx)
+	if nil != err {
+		return err
+	}
+
+	if _, ok := users[login]; !ok {
+		return ErrUserNotFound
+	}
+
+	return c.updateUser(ctx, login, password, admin)
+}
+
+// updateUser sends user record and checks that user is still present with requested status
+func (c *Client) updateUser(ctx context.Context, login string, password string, admin bool) error {
+	var users map[string]bool
+	t := "user"
+	if admin {
+		t = "admin"
+	}
+
+	err := c.SendFormContext(ctx, "PUT", c.url+"/v1/users", url.Values{
+		"login":  []string{login},
+		"passwd": []string{password},
+		"type":   []string{t},
+	}, &users)
+	if nil != err {
+		return err
+	}
+
+	if current, ok := users[login]; !ok || current != admin {
+		return errors.New("user " + login + " was not updated")
+	}
+
+	return nil
+}
+
+// user roles, servers without role support know only RoleOperator (plain user) and RoleAdmin
+const (
+	RoleReadOnly   = "read-only"
+	RoleOperator   = "operator"
+	RoleAdmin      = "admin"
+	RoleSuperAdmin = "super-admin"
+)
+
+// UserDetails describes one user account, Email, CreatedAt and LastLogin are empty on servers without role support
+type UserDetails struct {
+	Login     string    `json:"login"`
+	Role      string    `json:"role"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastLogin time.Time `json:"lastLogin"`
+}
+
+// RoleDefinition describes API actions allowed to role
+type RoleDefinition struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Actions     []string `json:"actions"`
+}
+
+// GetUserDetails returns account details of user, ErrUserNotFound if there is no such user
+func (c *Client) GetUserDetails(login string) (UserDetails, error) {
+	return c.GetUserDetailsContext(context.Background(), login)
+}
+
+// GetUserDetailsContext is same as GetUserDetails but requests are bound to ctx
+func (c *Client) GetUserDetailsContext(ctx context.Context, login string) (UserDetails, error) {
+	var details UserDetails
+	status, err := c.execute(ctx, newRequest("GET", c.url+"/v1/users/"+url.PathEscape(login), nil, "", nil), &details)
+	if http.StatusNotFound != status && http.StatusMethodNotAllowed != status {
+		return details, err
+	}
+
+	// 404 can mean both unknown user and server without role support
+	users, err := c.ListUsersContext(ctx)
+	if nil != err {
+		return UserDetails{}, err
+	}
+	admin, ok := users[login]
+	if !ok {
+		return UserDetails{}, ErrUserNotFound
+	}
+
+	details = UserDetails{Login: login, Role: RoleOperator}
+	if admin {
+		details.Role = RoleAdmin
+	}
+	return details, nil
+}
+
+// SetUserRole changes role of existing user, ErrUserNotFound if there is no such user;
+// servers without role support accept only RoleOperator and RoleAdmin and can't change them without password
+// (ErrPasswordRequired is returned, see ChangeUserRoleWithPassword)
+func (c *Client) SetUserRole(login string, role string) error {
+	return c.SetUserRoleContext(context.Background(), login, role)
+}
+
+// SetUserRoleContext is same as SetUserRole but requests are bound to ctx
+func (c *Client) SetUserRoleContext(ctx context.Context, login string, role string) error {
+	switch role {
+	case RoleReadOnly, RoleOperator, RoleAdmin, RoleSuperAdmin:
+	default:
+		return errors.New("unknown role " + role)
+	}
+
+	raw, err := json.Marshal(map[string]string{"role": role})
+	if nil != err {
+		return err
+	}
+
+	var r result
+	status, err := c.execute(ctx, newRequest("PUT", c.url+"/v1/users/"+url.PathEscape(login)+"/role", raw, "application/json", nil), &r)
+	if http.StatusNotFound == status || http.StatusMethodNotAllowed == status {
+		switch role {
+		case RoleOperator:
+			return c.checkUserAdmin(ctx, login, false)
+		case RoleAdmin:
+			return c.checkUserAdmin(ctx, login, true)
+		}
+		if _, err := c.GetUserDetailsContext(ctx, login); nil != err {
+			return err
+		}
+		return errors.New("role " + role + " isn't supported by server")
+	}
+	if nil != err {
+		return err
+	}
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return errors.New(r.Error)
+		}
+		return errors.New("unknown error")
+	}
+
+	return nil
+}
+
+// ListRoles returns definitions of all roles known to server
+func (c *Client) ListRoles() ([]RoleDefinition, error) {
+	return c.ListRolesContext(context.Background())
+}
+
+// ListRolesContext is same as ListRoles but request is bound to ctx
+func (c *Client) ListRolesContext(ctx context.Context) ([]RoleDefinition, error) {
+	var roles []RoleDefinition
+	err := c.GetContext(ctx, c.url+"/v1/roles", &roles)
+	return roles, err
+}
@@ -0,0 +1,169 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+var allRoles = []string{RoleReadOnly, RoleOperator, RoleAdmin, RoleSuperAdmin}
+
+// roleServer keeps roles of users, without roles support it serves only legacy /v1/users (admin = RoleAdmin)
+type roleServer struct {
+	mu       sync.Mutex
+	roles    map[string]string
+	legacy   bool
+	roleSets int
+}
+
+func (s *roleServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if "/v1/users" == r.URL.Path && "GET" == r.Method {
+		users := map[string]bool{}
+		for login, role := range s.roles {
+			users[login] = RoleAdmin == role || RoleSuperAdmin == role
+		}
+		writeTestJSON(w, users)
+		return
+	}
+	if s.legacy || !strings.HasPrefix(r.URL.Path, "/v1/users/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	login := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/users/"), "/role")
+	role, ok := s.roles[login]
+	switch {
+	case !ok:
+		http.NotFound(w, r)
+	case "GET" == r.Method:
+		writeTestJSON(w, UserDetails{Login: login, Role: role})
+	case "PUT" == r.Method && strings.HasSuffix(r.URL.Path, "/role"):
+		var payload struct {
+			Role string `json:"role"`
+		}
+		json.NewDecoder(r.Body).Decode(&payload)
+		s.roles[login] = payload.Role
+		s.roleSets++
+		writeTestJSON(w, result{Result: "OK"})
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestSetUserRoleTransitions(t *testing.T) {
+	for _, from := range allRoles {
+		for _, to := range allRoles {
+			s := &roleServer{roles: map[string]string{"bob": from}}
+			c := newTestClient(t, s.ServeHTTP)
+
+			if err := c.SetUserRole("bob", to); nil != err {
+				t.Errorf("%s -> %s: %v", from, to, err)
+				continue
+			}
+			details, err := c.GetUserDetails("bob")
+			if nil != err {
+				t.Fatal(err)
+			}
+			if to != details.Role || "bob" != details.Login {
+				t.Errorf("%s -> %s: unexpected details %+v", from, to, details)
+			}
+		}
+	}
+}
+
+func TestSetUserRoleTransitionsLegacy(t *testing.T) {
+	for _, from := range []string{RoleOperator, RoleAdmin} {
+		for _, to := range allRoles {
+			s := &roleServer{roles: map[string]string{"bob": from}, legacy: true}
+			c := newTestClient(t, s.ServeHTTP)
+
+			err := c.SetUserRole("bob", to)
+			switch {
+			case from == to:
+				if nil != err {
+					t.Errorf("%s -> %s: %v", from, to, err)
+				}
+			case RoleOperator == to || RoleAdmin == to:
+				if !errors.Is(err, ErrPasswordRequired) {
+					t.Errorf("%s -> %s: expected ErrPasswordRequired, got %v", from, to, err)
+				}
+			default:
+				if nil == err || !strings.Contains(err.Error(), "isn't supported") {
+					t.Errorf("%s -> %s: expected unsupported role error, got %v", from, to, err)
+				}
+			}
+
+			details, err := c.GetUserDetails("bob")
+			if nil != err {
+				t.Fatal(err)
+			}
+			if from != details.Role {
+				t.Errorf("%s -> %s: role changed to %s", from, to, details.Role)
+			}
+		}
+	}
+}
+
+func TestSetUserRoleErrors(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		s := &roleServer{roles: map[string]string{"bob": RoleOperator}, legacy: legacy}
+		c := newTestClient(t, s.ServeHTTP)
+
+		if err := c.SetUserRole("bob", "owner"); nil == err {
+			t.Errorf("legacy=%v: expected error for unknown role", legacy)
+		}
+		for _, role := range allRoles {
+			if err := c.SetUserRole("alice", role); !errors.Is(err, ErrUserNotFound) {
+				t.Errorf("legacy=%v %s: expected ErrUserNotFound, got %v", legacy, role, err)
+			}
+		}
+		if _, err := c.GetUserDetails("alice"); !errors.Is(err, ErrUserNotFound) {
+			t.Errorf("legacy=%v: expected ErrUserNotFound, got %v", legacy, err)
+		}
+		if 0 != s.roleSets {
+			t.Errorf("legacy=%v: expected no role changes, got %d", legacy, s.roleSets)
+		}
+	}
+}
+
+func TestChangeUserRole(t *testing.T) {
+	for _, legacy := range []bool{false, true} {
+		for _, admin := range []bool{false, true} {
+			s := &roleServer{roles: map[string]string{"bob": RoleOperator}, legacy: legacy}
+			c := newTestClient(t, s.ServeHTTP)
+
+			err := c.ChangeUserRole("bob", admin)
+			expected := RoleOperator
+			switch {
+			case !legacy:
+				if nil != err {
+					t.Errorf("legacy=%v admin=%v: %v", legacy, admin, err)
+				}
+				if admin {
+					expected = RoleAdmin
+				}
+			case admin:
+				if !errors.Is(err, ErrPasswordRequired) {
+					t.Errorf("legacy=%v admin=%v: expected ErrPasswordRequired, got %v", legacy, admin, err)
+				}
+			default:
+				if nil != err {
+					t.Errorf("legacy=%v admin=%v: %v", legacy, admin, err)
+				}
+			}
+			if expected != s.roles["bob"] {
+				t.Errorf("legacy=%v admin=%v: expected role %s, got %s", legacy, admin, expected, s.roles["bob"])
+			}
+
+			if err := c.ChangeUserRole("alice", admin); !errors.Is(err, ErrUserNotFound) {
+				t.Errorf("legacy=%v admin=%v: expected ErrUserNotFound, got %v", legacy, admin, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// APIVersion is version of cocopacket master like 1.0.4-6 (Build is number after dash)
+type APIVersion struct {
+	Major        int
+	Minor        int
+	Patch        int
+	Build        int
+	Capabilities []string // optional features reported by server, may be empty on older versions
+}
+
+// minimal versions of features documented in api.md
+var (
+	MinVersionPushNotify = APIVersion{Major: 1, Minor: 0, Patch: 2}
+	MinVersionPreset     = APIVersion{Major: 1, Minor: 0, Patch: 4, Build: 4}
+)
+
+// capabilityHistoryStats is reported by servers supporting /v1/historystats
+const capabilityHistoryStats = "historystats"
+
+// ParseAPIVersion parses version string like "1.0.4-6" or "v1.2.3"
+func ParseAPIVersion(s string) (APIVersion, error) {
+	var v APIVersion
+
+	version := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexByte(version, '-'); i >= 0 {
+		build, err := strconv.Atoi(version[i+1:])
+		if nil != err {
+			return v, errors.New("invalid version " + s)
+		}
+		v.Build = build
+		version = version[:i]
+	}
+
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return v, errors.New("invalid version " + s)
+	}
+	for i, field := range []*int{&v.Major, &v.Minor, &v.Patch}[:len(parts)] {
+		n, err := strconv.Atoi(parts[i])
+		if nil != err || n < 0 {
+			return v, errors.New("invalid version " + s)
+		}
+		*field = n
+	}
+
+	return v, nil
+}
+
+// String returns version formatted like "1.0.4-6"
+func (v APIVersion) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch) + "-" + strconv.Itoa(v.Build)
+}
+
+// Less reports if v is older than other
+func (v APIVersion) Less(other APIVersion) bool {
+	a := []int{v.Major, v.Minor, v.Patch, v.Build}
+	b := []int{other.Major, other.Minor, other.Patch, other.Build}
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+// HasCapability reports if server reported capability
+func (v APIVersion) HasCapability(capability string) bool {
+	for _, c := range v.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAPIVersion returns version of master, result is cached by client after first successful call
+func (c *Client) GetAPIVersion() (APIVersion, error) {
+	return c.GetAPIVersionContext(context.Background())
+}
+
+// GetAPIVersionContext is same as GetAPIVersion but request is bound to ctx
+func (c *Client) GetAPIVersionContext(ctx context.Context) (APIVersion, error) {
+	c.versionMu.Lock()
+	cached := c.version
+	c.versionMu.Unlock()
+
+	if nil != cached {
+		return *cached, nil
+	}
+
+	// concurrent first calls may all ask server, result is same anyway
+	var result struct {
+		Version      string   `json:"version"`
+		Capabilities []string `json:"capabilities"`
+	}
+	if err := c.GetContext(ctx, c.url+"/v1/status/version", &result); nil != err {
+		return APIVersion{}, err
+	}
+
+	v, err := ParseAPIVersion(result.Version)
+	if nil != err {
+		return APIVersion{}, err
+	}
+	v.Capabilities = result.Capabilities
+
+	c.versionMu.Lock()
+	c.version = &v
+	c.versionMu.Unlock()
+
+	return v, nil
+}
+
+// RequireVersion returns ErrVersionTooOld if master is older than min
+func (c *Client) RequireVersion(min APIVersion) error {
+	return c.RequireVersionContext(context.Background(), min)
+}
+
+// RequireVersionContext is same as RequireVersion but request is bound to ctx
+func (c *Client) RequireVersionContext(ctx context.Context, min APIVersion) error {
+	v, err := c.GetAPIVersionContext(ctx)
+	if nil != err {
+		return err
+	}
+	if v.Less(min) {
+		return ErrVersionTooOld
+	}
+	return nil
+}
+
+// requireCapability returns ErrVersionTooOld if server reports capabilities but not this one; unknown version
+// (version endpoint failed) is treated as capable and real request decides
+func (c *Client) requireCapability(ctx context.Context, capability string) error {
+	v, err := c.GetAPIVersionContext(ctx)
+	if nil != err {
+		c.log().Debug("version check skipped", "capability", capability, "error", err)
+		return nil
+	}
+	if 0 != len(v.Capabilities) && !v.HasCapability(capability) {
+		return ErrVersionTooOld
+	}
+	return nil
+}
+
+// requireVersion is tolerant variant of RequireVersionContext, unknown version (version endpoint failed) is treated
+// as new enough and real request decides
+func (c *Client) requireVersion(ctx context.Context, min APIVersion) error {
+	v, err := c.GetAPIVersionContext(ctx)
+	if nil != err {
+		c.log().Debug("version check skipped", "min", min.String(), "error", err)
+		return nil
+	}
+	if v.Less(min) {
+		return ErrVersionTooOld
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseAPIVersion(t *testing.T) {
+	for input, expected := range map[string]APIVersion{
+		"1.0.4-6":  {Major: 1, Minor: 0, Patch: 4, Build: 6},
+		"v1.2.3":   {Major: 1, Minor: 2, Patch: 3},
+		" 2.1 ":    {Major: 2, Minor: 1},
+		"1.0.2-0":  {Major: 1, Minor: 0, Patch: 2},
+		"10.20.30": {Major: 10, Minor: 20, Patch: 30},
+	} {
+		v, err := ParseAPIVersion(input)
+		if nil != err || expected.String() != v.String() {
+			t.Errorf("%q: expected %s, got %s %v", input, expected, v, err)
+		}
+	}
+
+	for _, input := range []string{"", "1.x", "1.2.3.4", "1.0.4-beta", "-1.0"} {
+		if _, err := ParseAPIVersion(input); nil == err {
+			t.Errorf("%q: expected error", input)
+		}
+	}
+
+	if !(APIVersion{Major: 1, Patch: 4, Build: 6}).Less(APIVersion{Major: 1, Patch: 4, Build: 7}) ||
+		(APIVersion{Major: 1, Minor: 1}).Less(APIVersion{Major: 1, Patch: 9, Build: 9}) {
+		t.Fatal("unexpected Less result")
+	}
+}
+
+// historyServer answers version endpoint with version (404 if empty) and counts historystats requests
+type historyServer struct {
+	version      string
+	capabilities []string
+	history      int32
+}
+
+func (s *historyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case "/v1/status/version" == r.URL.Path && "" != s.version:
+		writeTestJSON(w, map[string]interface{}{"version": s.version, "capabilities": s.capabilities})
+	case strings.HasPrefix(r.URL.Path, "/v1/historystats/"):
+		atomic.AddInt32(&s.history, 1)
+		writeTestJSON(w, GroupStatsData{})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestHistoricalStatsCapability(t *testing.T) {
+	to := time.Now()
+	from := to.Add(-time.Hour)
+
+	for _, tc := range []struct {
+		name    string
+		server  *historyServer
+		allowed bool
+	}{
+		{"unknown version", &historyServer{}, true},
+		{"no capabilities reported", &historyServer{version: "1.0.4-6"}, true},
+		{"capable", &historyServer{version: "1.0.5-0", capabilities: []string{"other", capabilityHistoryStats}}, true},
+		{"too old", &historyServer{version: "1.0.4-6", capabilities: []string{"other"}}, false},
+	} {
+		c := newTestClient(t, tc.server.ServeHTTP)
+		_, err := c.GetHistoricalStats("DNS", from, to, time.Hour)
+		if tc.allowed && nil != err {
+			t.Errorf("%s: %v", tc.name, err)
+		}
+		if !tc.allowed && !errors.Is(err, ErrVersionTooOld) {
+			t.Errorf("%s: expected ErrVersionTooOld, got %v", tc.name, err)
+		}
+		if expected := map[bool]int32{true: 1, false: 0}[tc.allowed]; expected != atomic.LoadInt32(&tc.server.history) {
+			t.Errorf("%s: expected %d historystats requests, got %d", tc.name, expected, tc.server.history)
+		}
+	}
+}
+
+func TestRequireVersion(t *testing.T) {
+	c := newTestClient(t, (&historyServer{version: "1.0.4-2"}).ServeHTTP)
+
+	if err := c.RequireVersion(MinVersionPushNotify); nil != err {
+		t.Fatal(err)
+	}
+	if err := c.RequireVersion(MinVersionPreset); !errors.Is(err, ErrVersionTooOld) {
+		t.Fatalf("expected ErrVersionTooOld, got %v", err)
+	}
+
+	// unknown version is an error for explicit checks
+	c = newTestClient(t, (&historyServer{}).ServeHTTP)
+	if err := c.RequireVersion(MinVersionPushNotify); nil == err || errors.Is(err, ErrVersionTooOld) {
+		t.Fatalf("expected request error, got %v", err)
+	}
+}
+
+func TestGetAPIVersionNotBlockedBySlowRequest(t *testing.T) {
+	release := make(chan struct{})
+	var calls int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if 1 == atomic.AddInt32(&calls, 1) {
+			<-release
+		}
+		writeTestJSON(w, map[string]string{"version": "1.0.4-6"})
+	})
+
+	slow := make(chan error)
+	go func() {
+		_, err := c.GetAPIVersion()
+		slow <- err
+	}()
+	for 0 == atomic.LoadInt32(&calls) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// second caller isn't stuck behind first one's request
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	v, err := c.GetAPIVersionContext(ctx)
+	if nil != err || "1.0.4-6" != v.String() {
+		t.Fatalf("unexpected result %s %v", v, err)
+	}
+
+	close(release)
+	if err := <-slow; nil != err {
+		t.Fatal(err)
+	}
+
+	// cached afterwards
+	if _, err := c.GetAPIVersion(); nil != err || 2 != atomic.LoadInt32(&calls) {
+		t.Fatalf("expected cached version, got %v after %d requests", err, calls)
+	}
+}
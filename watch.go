@@ -0,0 +1,101 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// SlavesStatusEvent describes change of slaves status detected by WatchSlavesStatus
+type SlavesStatusEvent struct {
+	Previous     map[string]SlaveStatus
+	Current      map[string]SlaveStatus
+	NewlyOffline []string // slaves that went offline or disappeared
+	NewlyOnline  []string // slaves that went online or appeared online
+}
+
+// WatchSlavesStatus polls slaves status every interval and sends event to returned channel when status of any slave changes
+// (Last timestamp alone is not a change); polling errors are skipped, channel is closed when ctx is done.
+// Error is returned only if interval isn't positive or initial status can't be loaded.
+func (c *Client) WatchSlavesStatus(ctx context.Context, interval time.Duration) (<-chan SlavesStatusEvent, error) {
+	if interval <= 0 {
+		return nil, errors.New("interval must be positive")
+	}
+
+	previous, err := c.GetSlavesStatusContext(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	ch := make(chan SlavesStatusEvent)
+
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := c.GetSlavesStatusContext(ctx)
+			if nil != err || !slavesStatusChanged(previous, current) {
+				continue
+			}
+
+			event := SlavesStatusEvent{
+				Previous: previous,
+				Current:  current,
+			}
+			for slave, status := range current {
+				old, ok := previous[slave]
+				if status.Online() && (!ok || !old.Online()) {
+					event.NewlyOnline = append(event.NewlyOnline, slave)
+				}
+				if !status.Online() && ok && old.Online() {
+					event.NewlyOffline = append(event.NewlyOffline, slave)
+				}
+			}
+			for slave, old := range previous {
+				if _, ok := current[slave]; !ok && old.Online() {
+					event.NewlyOffline = append(event.NewlyOffline, slave)
+				}
+			}
+			sort.Strings(event.NewlyOnline)
+			sort.Strings(event.NewlyOffline)
+
+			select {
+			case <-ctx.Done():
+				return
+			case ch <- event:
+			}
+			previous = current
+		}
+	}()
+
+	return ch, nil
+}
+
+// slavesStatusChanged compares two snapshots ignoring Last timestamps
+func slavesStatusChanged(a, b map[string]SlaveStatus) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for slave, sa := range a {
+		sb, ok := b[slave]
+		if !ok {
+			return true
+		}
+		sa.Last = time.Time{}
+		sb.Last = time.Time{}
+		if sa != sb {
+			return true
+		}
+	}
+	return false
+}
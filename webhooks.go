@@ -0,0 +1,110 @@
+package api
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// webhook events
+const (
+	EventSlaveOnline  = "slave_online"
+	EventSlaveOffline = "slave_offline"
+	EventIPAlert      = "ip_alert"
+)
+
+// WebhookSignatureHeader is header of webhook calls with hex HMAC-SHA256 of body, see VerifyWebhookSignature
+const WebhookSignatureHeader = "X-Cocopacket-Signature"
+
+// Webhook is url called by master on events
+type Webhook struct {
+	ID      string    `json:"id"`
+	URL     string    `json:"url"`
+	Events  []string  `json:"events"`
+	Created time.Time `json:"created"`
+}
+
+// RegisterWebhook makes master call webhookURL (POST with json body) on events (EventSlaveOnline, EventSlaveOffline, EventIPAlert),
+// calls are signed by secret if it's not empty
+func (c *Client) RegisterWebhook(webhookURL string, events []string, secret string) (string, error) {
+	return c.RegisterWebhookContext(context.Background(), webhookURL, events, secret)
+}
+
+// RegisterWebhookContext is same as RegisterWebhook but request is bound to ctx
+func (c *Client) RegisterWebhookContext(ctx context.Context, webhookURL string, events []string, secret string) (string, error) {
+	if u, err := url.Parse(webhookURL); nil != err || ("http" != u.Scheme && "https" != u.Scheme) || "" == u.Host {
+		return "", errors.New("invalid webhook url " + webhookURL)
+	}
+	if 0 == len(events) {
+		return "", errors.New("no webhook events")
+	}
+	for _, event := range events {
+		switch event {
+		case EventSlaveOnline, EventSlaveOffline, EventIPAlert:
+		default:
+			return "", errors.New("unsupported webhook event " + event)
+		}
+	}
+
+	var r struct {
+		result
+		ID string `json:"id"`
+	}
+	err := c.SendContext(ctx, "POST", c.url+"/v1/webhooks", map[string]interface{}{
+		"url":    webhookURL,
+		"events": events,
+		"secret": secret,
+	}, &r)
+	if nil != err {
+		return "", err
+	}
+
+	if "OK" != r.Result {
+		if "" != r.Error {
+			return "", errors.New(r.Error)
+		}
+		return "", errors.New("unknown error")
+	}
+
+	return r.ID, nil
+}
+
+// DeregisterWebhook removes webhook registered by RegisterWebhook
+func (c *Client) DeregisterWebhook(webhookID string) error {
+	return c.DeregisterWebhookContext(context.Background(), webhookID)
+}
+
+// DeregisterWebhookContext is same as DeregisterWebhook but request is bound to ctx
+func (c *Client) DeregisterWebhookContext(ctx context.Context, webhookID string) error {
+	return c._okResultSend(ctx, "DELETE", c.url+"/v1/webhooks/"+url.PathEscape(webhookID), nil)
+}
+
+// ListWebhooks returns all registered webhooks (secrets are never returned by server)
+func (c *Client) ListWebhooks() ([]Webhook, error) {
+	return c.ListWebhooksContext(context.Background())
+}
+
+// ListWebhooksContext is same as ListWebhooks but request is bound to ctx
+func (c *Client) ListWebhooksContext(ctx context.Context) ([]Webhook, error) {
+	var result []Webhook
+	err := c.GetContext(ctx, c.url+"/v1/webhooks", &result)
+	return result, err
+}
+
+// VerifyWebhookSignature checks signature (value of WebhookSignatureHeader, hex HMAC-SHA256 with optional "sha256=" prefix)
+// of webhook call payload, comparison is done in constant time
+func VerifyWebhookSignature(payload []byte, signature, secret string) bool {
+	expected, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), "sha256="))
+	if nil != err {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}